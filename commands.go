@@ -0,0 +1,159 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/habbazettt/muraja-server/config"
+	"github.com/habbazettt/muraja-server/notifier"
+	"github.com/habbazettt/muraja-server/qlearning"
+	"github.com/habbazettt/muraja-server/services"
+)
+
+// runCommand menjalankan subcommand CLI. Mengembalikan false jika argumen
+// bukan subcommand yang dikenal sehingga server dijalankan seperti biasa.
+func runCommand(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+
+	switch args[0] {
+	case "create-admin":
+		os.Exit(createAdminCommand(args[1:]))
+	case "generate-vapid":
+		os.Exit(generateVapidCommand())
+	case "train":
+		os.Exit(trainCommand(args[1:]))
+	}
+
+	return false
+}
+
+func createAdminCommand(args []string) int {
+	fs := flag.NewFlagSet("create-admin", flag.ContinueOnError)
+	nama := fs.String("nama", "Administrator", "nama admin")
+	email := fs.String("email", "", "email admin")
+	password := fs.String("password", "", "password admin (minimal 6 karakter)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	config.ConnectDB()
+	config.MigrateDB()
+	defer config.CloseDB()
+
+	admin, err := config.CreateAdmin(*nama, *email, *password)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Gagal membuat admin: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Admin %s (id=%d) berhasil dibuat.\n", admin.Email, admin.ID)
+	return 0
+}
+
+func generateVapidCommand() int {
+	publicKey, privateKey, err := notifier.GenerateVAPIDKeys()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Gagal membuat kunci VAPID: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("VAPID_PUBLIC_KEY=%s\nVAPID_PRIVATE_KEY=%s\n", publicKey, privateKey)
+	return 0
+}
+
+// trainCommand melatih ulang Q-table dari data di database lalu menulis
+// q_table_model.json dan historical_best.json. Server yang berjalan akan
+// mendaftarkan berkas baru itu sebagai versi model saat sinkronisasi berikutnya.
+func trainCommand(args []string) int {
+	params, err := qlearning.ParamsFromEnv()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Peringatan: %v, memakai default\n", err)
+	}
+
+	fs := flag.NewFlagSet("train", flag.ContinueOnError)
+	gamma := fs.Float64("gamma", params.Gamma, "discount factor")
+	epoch := fs.Int("epoch", 5000, "jumlah epoch maksimal")
+	seed := fs.Uint64("seed", 1, "seed pengacak urutan episode")
+	toleransi := fs.Float64("toleransi", 1e-6, "berhenti jika perubahan Q terbesar antar-epoch di bawah nilai ini")
+	hari := fs.Int("hari", 0, "hanya memakai log harian N hari terakhir (0 = semua)")
+	outQTable := fs.String("out-qtable", config.BerkasQTable, "berkas keluaran Q-table")
+	outHistoris := fs.String("out-historis", config.BerkasHistoris, "berkas keluaran data historis")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	opsi := qlearning.OpsiLatih{
+		Params:    qlearning.Params{Alpha: params.Alpha, Gamma: *gamma},
+		Epoch:     *epoch,
+		Seed:      *seed,
+		Toleransi: *toleransi,
+	}
+	if err := opsi.Params.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Hyperparameter tidak valid: %v\n", err)
+		return 2
+	}
+	if opsi.Epoch < 1 {
+		fmt.Fprintln(os.Stderr, "epoch minimal 1")
+		return 2
+	}
+
+	var sejak time.Time
+	if *hari > 0 {
+		sejak = time.Now().AddDate(0, 0, -*hari)
+	}
+
+	db := config.ConnectDB()
+	config.MigrateDB()
+	defer config.CloseDB()
+
+	data, err := services.SiapkanDataLatih(db, sejak)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Gagal menyiapkan data latih: %v\n", err)
+		return 1
+	}
+	if len(data.Episode) == 0 {
+		fmt.Fprintf(os.Stderr, "Tidak ada episode untuk dilatih (%d jadwal dilewati).\n", data.Dilewati)
+		return 1
+	}
+
+	tabel, ringkasan := qlearning.Latih(data.Episode, opsi)
+
+	fmt.Printf("Episode: %d (dilewati %d), state: %d, alpha=1/n gamma=%g seed=%d\n",
+		len(data.Episode), data.Dilewati, len(tabel), opsi.Gamma, opsi.Seed)
+	langkah := max(1, ringkasan.Epoch/10)
+	for i, delta := range ringkasan.PerubahanMaks {
+		if epoch := i + 1; epoch == 1 || epoch%langkah == 0 || epoch == ringkasan.Epoch {
+			fmt.Printf("  epoch %4d  max |dQ| = %.6f\n", epoch, delta)
+		}
+	}
+	if ringkasan.Konvergen {
+		fmt.Printf("Konvergen setelah %d epoch (toleransi %g).\n", ringkasan.Epoch, opsi.Toleransi)
+	} else {
+		fmt.Printf("Belum konvergen setelah %d epoch (toleransi %g).\n", ringkasan.Epoch, opsi.Toleransi)
+	}
+
+	states := make([]string, 0, len(tabel))
+	for state := range tabel {
+		states = append(states, state)
+	}
+	sort.Strings(states)
+	fmt.Println("Aksi terbaik per state:")
+	for _, state := range states {
+		aksi, q, _ := tabel.Terbaik(state)
+		fmt.Printf("  %s -> %s (Q=%.4f)\n", state, aksi, q)
+	}
+
+	model := &config.Model{QTable: tabel, HistoricalBest: data.Historis}
+	if err := config.TulisBerkasModel(model, *outQTable, *outHistoris); err != nil {
+		fmt.Fprintf(os.Stderr, "Gagal menulis model: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Model ditulis ke %s dan %s.\n", *outQTable, *outHistoris)
+	return 0
+}
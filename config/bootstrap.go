@@ -0,0 +1,89 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/habbazettt/muraja-server/models"
+	"github.com/habbazettt/muraja-server/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var ErrAdminEmailTaken = errors.New("email sudah terdaftar")
+
+// CreateAdmin membuat akun admin baru. Dipakai oleh subcommand create-admin dan
+// oleh BootstrapAdminFromEnv saat server pertama kali dijalankan.
+func CreateAdmin(nama, email, password string) (*models.User, error) {
+	if DB == nil {
+		return nil, errors.New("database belum terhubung")
+	}
+	if nama == "" || email == "" || password == "" {
+		return nil, errors.New("nama, email, dan password wajib diisi")
+	}
+	if !utils.IsValidEmail(email) {
+		return nil, fmt.Errorf("email %q tidak valid", email)
+	}
+	if len(password) < 6 {
+		return nil, errors.New("password minimal 6 karakter")
+	}
+
+	var existing models.User
+	err := DB.Where("email = ?", email).First(&existing).Error
+	if err == nil {
+		return nil, ErrAdminEmailTaken
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	hashed, err := utils.HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	admin := models.User{
+		Nama:     nama,
+		Email:    email,
+		Password: hashed,
+		UserType: models.RoleAdmin,
+	}
+	if err := DB.Create(&admin).Error; err != nil {
+		return nil, err
+	}
+
+	return &admin, nil
+}
+
+// BootstrapAdminFromEnv membuat admin pertama dari BOOTSTRAP_ADMIN_* jika
+// belum ada satu pun admin di database.
+func BootstrapAdminFromEnv() {
+	email := os.Getenv("BOOTSTRAP_ADMIN_EMAIL")
+	password := os.Getenv("BOOTSTRAP_ADMIN_PASSWORD")
+	if email == "" || password == "" {
+		return
+	}
+
+	var adminCount int64
+	if err := DB.Model(&models.User{}).Where("user_type = ?", models.RoleAdmin).Count(&adminCount).Error; err != nil {
+		logrus.WithError(err).Error("❌ Gagal memeriksa admin yang ada")
+		return
+	}
+	if adminCount > 0 {
+		return
+	}
+
+	nama := os.Getenv("BOOTSTRAP_ADMIN_NAME")
+	if nama == "" {
+		nama = "Administrator"
+	}
+
+	admin, err := CreateAdmin(nama, email, password)
+	if err != nil {
+		logrus.WithError(err).Error("❌ Gagal membuat admin awal dari environment")
+		return
+	}
+
+	logrus.WithField("user_id", admin.ID).Info("✅ Admin awal berhasil dibuat dari environment")
+}
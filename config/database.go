@@ -55,17 +55,48 @@ func MigrateDB() {
 		logrus.Fatal("❌ Database belum terhubung! Jalankan ConnectDB() terlebih dahulu.")
 	}
 
+	if err := migrasiKolomJadwal(); err != nil {
+		logrus.WithError(err).Error("❌ Gagal mengubah kolom jadwal personal!")
+		return
+	}
+
 	err := DB.AutoMigrate(
 		&models.User{},
 		&models.LogHarian{},
 		&models.DetailLog{},
 		&models.JadwalPersonal{},
 		&models.JadwalRekomendasi{},
+		&models.KandidatRekomendasi{},
+		&models.StateSumberRekomendasi{},
+		&models.PasswordResetToken{},
+		&models.Session{},
+		&models.Invitation{},
+		&models.Halaqah{},
+		&models.HalaqahAnggota{},
+		&models.KekuatanHalaman{},
+		&models.RolloverLog{},
+		&models.JobRun{},
+		&models.KanalNotifikasi{},
+		&models.PengaturanSlot{},
+		&models.PengingatTerkirim{},
+		&models.QTableVersi{},
+		&models.QValue{},
+		&models.HistorisVersi{},
+		&models.UmpanBalikRekomendasi{},
+		&models.Eksperimen{},
+		&models.VarianEksperimen{},
+		&models.PenugasanEksperimen{},
 	)
 	if err != nil {
 		logrus.WithError(err).Error("❌ Gagal melakukan migrasi database!")
+		return
 	}
 
+	backfillHalamanDetailLog()
+	normalisasiSlotMurojaah()
+	resetLayoutMushafTidakDikenal()
+	isiWaktuPenilaianEfektifitas()
+
 	logrus.Info("✅ Database berhasil dimigrasi!")
 }
 
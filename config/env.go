@@ -2,6 +2,8 @@ package config
 
 import (
 	"log"
+	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -12,3 +14,17 @@ func LoadEnv() {
 		log.Println("⚠️  Tidak dapat memuat file .env, menggunakan variabel environment default")
 	}
 }
+
+func GetEnvDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Printf("⚠️  Nilai %s tidak valid (%q), menggunakan default %s", key, value, fallback)
+		return fallback
+	}
+	return d
+}
@@ -0,0 +1,183 @@
+package config
+
+import (
+	"github.com/habbazettt/muraja-server/models"
+	"github.com/habbazettt/muraja-server/mushaf"
+	"github.com/habbazettt/muraja-server/slot"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// halamanLegacy mengubah alamat juz/halaman lama (asumsi 20 halaman per juz)
+// ke halaman mutlak Mushaf Madinah, dengan memotong halaman yang melebihi juz.
+func halamanLegacy(layout *mushaf.Layout, juz, halaman int) int {
+	if juz < 1 {
+		juz = 1
+	}
+	if juz > mushaf.JumlahJuz {
+		juz = mushaf.JumlahJuz
+	}
+	if halaman < 1 {
+		halaman = 1
+	}
+	if max := layout.JumlahHalamanJuz(juz); halaman > max {
+		halaman = max
+	}
+
+	page, _ := layout.HalamanFromJuz(juz, halaman)
+	return page
+}
+
+// backfillHalamanDetailLog mengisi kolom halaman mutlak pada DetailLog yang
+// dibuat sebelum kolom tersebut ada. Total halaman yang sudah tercatat tidak diubah.
+func backfillHalamanDetailLog() {
+	layout := mushaf.Default()
+	var details []models.DetailLog
+	updated := 0
+
+	err := DB.Where("target_start_page = 0").FindInBatches(&details, 200, func(tx *gorm.DB, batch int) error {
+		for _, d := range details {
+			values := map[string]interface{}{
+				"target_start_page": halamanLegacy(layout, d.TargetStartJuz, d.TargetStartHalaman),
+				"target_end_page":   halamanLegacy(layout, d.TargetEndJuz, d.TargetEndHalaman),
+			}
+			if d.SelesaiEndJuz > 0 {
+				values["selesai_end_page"] = halamanLegacy(layout, d.SelesaiEndJuz, d.SelesaiEndHalaman)
+			}
+
+			if err := tx.Model(&models.DetailLog{}).Where("id = ?", d.ID).Updates(values).Error; err != nil {
+				return err
+			}
+			updated++
+		}
+		return nil
+	}).Error
+
+	if err != nil {
+		logrus.WithError(err).Error("❌ Gagal mengisi halaman mutlak detail log lama")
+		return
+	}
+	if updated > 0 {
+		logrus.WithField("jumlah", updated).Info("✅ Halaman mutlak detail log lama berhasil diisi")
+	}
+}
+
+// migrasiKolomJadwal mengubah kolom jadwal_personals.jadwal dari teks dipisah
+// koma menjadi array JSON. Dijalankan sebelum AutoMigrate karena Postgres tidak
+// bisa mengubah varchar ke jsonb tanpa klausa USING.
+func migrasiKolomJadwal() error {
+	var tipe string
+	err := DB.Raw(`SELECT data_type FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = 'jadwal_personals' AND column_name = 'jadwal'`).
+		Scan(&tipe).Error
+	if err != nil || tipe == "" || tipe == "jsonb" {
+		return err
+	}
+
+	return DB.Exec(`ALTER TABLE jadwal_personals ALTER COLUMN jadwal TYPE jsonb USING
+		CASE WHEN jadwal IS NULL OR btrim(jadwal) = '' THEN '[]'::jsonb
+		ELSE to_jsonb(string_to_array(jadwal, ',')) END`).Error
+}
+
+// normalisasiSlotMurojaah memetakan waktu murojaah dan jadwal lama yang masih
+// berupa teks bebas ke slot baku. Label yang tidak dikenali tetap disimpan
+// sebagai slot custom.
+func normalisasiSlotMurojaah() {
+	var jadwals []models.JadwalPersonal
+	jadwalDiubah := 0
+	err := DB.FindInBatches(&jadwals, 200, func(tx *gorm.DB, batch int) error {
+		for _, j := range jadwals {
+			items := make([]string, len(j.Jadwal))
+			for i, s := range j.Jadwal {
+				items[i] = string(s)
+			}
+			baku := slot.NormalisasiDaftar(items)
+			if baku.String() == j.Jadwal.String() {
+				continue
+			}
+			if err := tx.Model(&models.JadwalPersonal{}).Where("id = ?", j.ID).Update("jadwal", baku).Error; err != nil {
+				return err
+			}
+			jadwalDiubah++
+		}
+		return nil
+	}).Error
+	if err != nil {
+		logrus.WithError(err).Error("❌ Gagal menormalkan slot jadwal personal")
+		return
+	}
+
+	var waktuLama []string
+	if err := DB.Model(&models.DetailLog{}).Where("slot = ''").Distinct().Pluck("waktu_murojaah", &waktuLama).Error; err != nil {
+		logrus.WithError(err).Error("❌ Gagal membaca waktu murojaah lama")
+		return
+	}
+	detailDiubah := int64(0)
+	for _, waktu := range waktuLama {
+		baku := slot.Normalisasi(waktu)
+		if baku == "" {
+			baku = slot.Custom
+		}
+		res := DB.Model(&models.DetailLog{}).
+			Where("slot = '' AND waktu_murojaah = ?", waktu).
+			Updates(map[string]interface{}{"waktu_murojaah": string(baku), "slot": baku.Kategori()})
+		if res.Error != nil {
+			logrus.WithError(res.Error).WithField("waktu", waktu).Error("❌ Gagal menormalkan waktu murojaah")
+			return
+		}
+		detailDiubah += res.RowsAffected
+	}
+
+	if jadwalDiubah > 0 || detailDiubah > 0 {
+		logrus.WithFields(logrus.Fields{
+			"jadwal":    jadwalDiubah,
+			"detailLog": detailDiubah,
+		}).Info("✅ Slot murojaah lama berhasil dinormalkan")
+	}
+}
+
+// isiWaktuPenilaianEfektifitas mengisi efektifitas_dinilai_pada untuk penilaian
+// yang dibuat sebelum kolom tersebut ada. UpdatedAt adalah perkiraan terbaik
+// yang tersedia untuk data lama.
+func isiWaktuPenilaianEfektifitas() {
+	res := DB.Model(&models.JadwalPersonal{}).
+		Where("efektifitas_dinilai_pada IS NULL AND efektifitas_jadwal > 0").
+		Update("efektifitas_dinilai_pada", gorm.Expr("updated_at"))
+	if res.Error != nil {
+		logrus.WithError(res.Error).Error("❌ Gagal mengisi waktu penilaian efektivitas jadwal lama")
+		return
+	}
+	if res.RowsAffected > 0 {
+		logrus.WithField("jumlah", res.RowsAffected).Info("✅ Waktu penilaian efektivitas jadwal lama berhasil diisi")
+	}
+}
+
+// resetLayoutMushafTidakDikenal mengembalikan pilihan layout user yang sudah
+// tidak disediakan ke layout default agar sesi baru bisa dicatat. Sesi lama
+// tetap menyimpan kode layout aslinya dan tidak dibaca sebagai layout default:
+// halamannya tidak ditampilkan sebagai ayat, tidak dapat diperbarui, dan tidak
+// ikut rollover. Jumlahnya dilaporkan agar bisa ditindaklanjuti.
+func resetLayoutMushafTidakDikenal() {
+	var kode []string
+	for _, layout := range mushaf.All() {
+		kode = append(kode, layout.Kode)
+	}
+
+	res := DB.Model(&models.User{}).Where("mushaf_layout NOT IN ?", kode).Update("mushaf_layout", mushaf.DefaultLayout)
+	if res.Error != nil {
+		logrus.WithError(res.Error).Error("❌ Gagal mengatur ulang layout mushaf yang tidak dikenal")
+		return
+	}
+
+	var sesiLama int64
+	if err := DB.Model(&models.DetailLog{}).Where("mushaf_layout NOT IN ?", kode).Count(&sesiLama).Error; err != nil {
+		logrus.WithError(err).Error("❌ Gagal menghitung sesi dengan layout mushaf yang tidak dikenal")
+		return
+	}
+	if res.RowsAffected > 0 || sesiLama > 0 {
+		logrus.WithFields(logrus.Fields{
+			"user":      res.RowsAffected,
+			"detailLog": sesiLama,
+		}).Warn("⚠️ Layout mushaf yang tidak disediakan: pilihan user dikembalikan ke layout default, sesi lama dibiarkan dengan layout aslinya")
+	}
+}
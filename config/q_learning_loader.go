@@ -1,53 +1,311 @@
 package config
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"os"
+	"path/filepath"
 	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/habbazettt/muraja-server/qlearning"
+)
+
+const (
+	BerkasQTable   = "./q_table_model.json"
+	BerkasHistoris = "./historical_best.json"
 )
 
-type QTable map[string]map[string]float64
+// ErrManifestTidakCocok berarti pasangan berkas model tidak cocok dengan
+// manifestnya, yaitu sedang ditulis atau penulisannya terhenti di tengah.
+var ErrManifestTidakCocok = errors.New("berkas model tidak cocok dengan manifest")
+
+type QTable = qlearning.Tabel
 
 type HistoricalInfo struct {
 	Jadwal            string  `json:"-"`
+	TotalPenggunaan   int     `json:"Total Penggunaan"`
+	PenggunaanEfektif int     `json:"Penggunaan Dianggap Efektif (Skor >=4)"`
 	PersentaseEfektif float64 `json:"Persentase Efektif (%)"`
 }
 
-var (
-	QTableModel    QTable
+// Model adalah satu versi model rekomendasi: Q-table beserta data historis
+// untuk fallback. VersiID 0 berarti model belum tercatat di database. Model
+// yang sudah dipublikasikan tidak boleh diubah.
+type Model struct {
+	VersiID        uint
+	QTable         QTable
 	HistoricalBest []HistoricalInfo
-)
+}
 
-func LoadQlearningModels() error {
-	qTableFile, err := os.ReadFile("./q_table_model.json")
+var modelAktif atomic.Pointer[Model]
+
+// ActiveModel mengembalikan model yang sedang dipakai. Pemanggil sebaiknya
+// mengambil model sekali per permintaan agar Q-table dan data historis yang
+// dipakai berasal dari versi yang sama.
+func ActiveModel() *Model {
+	if m := modelAktif.Load(); m != nil {
+		return m
+	}
+	return &Model{}
+}
+
+// ActiveQTable mengembalikan Q-table yang sedang dipakai. Tabel yang
+// dikembalikan tidak boleh diubah.
+func ActiveQTable() QTable {
+	return ActiveModel().QTable
+}
+
+// SetModel mengganti model yang dipakai secara atomik, mis. setelah unggah
+// versi baru, pembaruan online atau rollback.
+func SetModel(m *Model) {
+	modelAktif.Store(m)
+}
+
+// ParseQTable membaca Q-table berformat q_table_model.json dan memastikan
+// setiap state berbentuk "<kesibukan>_<kategori>" dengan nilai Q yang hingga.
+func ParseQTable(data []byte) (QTable, error) {
+	var tabel QTable
+	if err := json.Unmarshal(data, &tabel); err != nil {
+		return nil, fmt.Errorf("format Q-table tidak valid: %w", err)
+	}
+	if len(tabel) == 0 {
+		return nil, fmt.Errorf("Q-table kosong")
+	}
+
+	for state, aksi := range tabel {
+		if kesibukan, kategori := qlearning.UraiState(state); kesibukan == "" || kategori == "" {
+			return nil, fmt.Errorf("state %q harus berbentuk <kesibukan>_<kategori hafalan>", state)
+		}
+		if len(aksi) == 0 {
+			return nil, fmt.Errorf("state %q tidak memiliki aksi", state)
+		}
+		for a, q := range aksi {
+			if a == "" {
+				return nil, fmt.Errorf("state %q memiliki aksi kosong", state)
+			}
+			if math.IsNaN(q) || math.IsInf(q, 0) {
+				return nil, fmt.Errorf("nilai Q %q pada state %q tidak valid", a, state)
+			}
+		}
+	}
+	return tabel, nil
+}
+
+// ParseHistoricalBest membaca historical_best.json dan mengurutkannya dari
+// persentase efektif tertinggi.
+func ParseHistoricalBest(data []byte) ([]HistoricalInfo, error) {
+	var peta map[string]HistoricalInfo
+	if err := json.Unmarshal(data, &peta); err != nil {
+		return nil, fmt.Errorf("format data historis tidak valid: %w", err)
+	}
+
+	historis := make([]HistoricalInfo, 0, len(peta))
+	for jadwal, info := range peta {
+		if jadwal == "" {
+			return nil, fmt.Errorf("data historis memiliki jadwal kosong")
+		}
+		if info.PersentaseEfektif < 0 || info.PersentaseEfektif > 100 {
+			return nil, fmt.Errorf("persentase efektif jadwal %q harus di antara 0 dan 100", jadwal)
+		}
+		if info.TotalPenggunaan < 0 || info.PenggunaanEfektif < 0 || info.PenggunaanEfektif > info.TotalPenggunaan {
+			return nil, fmt.Errorf("jumlah penggunaan jadwal %q tidak valid", jadwal)
+		}
+		info.Jadwal = jadwal
+		historis = append(historis, info)
+	}
+
+	SortHistoricalBest(historis)
+	return historis, nil
+}
+
+// SortHistoricalBest mengurutkan data historis dari persentase efektif
+// tertinggi; jadwal dengan persentase sama diurutkan menurut nama.
+func SortHistoricalBest(historis []HistoricalInfo) {
+	sort.Slice(historis, func(i, j int) bool {
+		if historis[i].PersentaseEfektif != historis[j].PersentaseEfektif {
+			return historis[i].PersentaseEfektif > historis[j].PersentaseEfektif
+		}
+		return historis[i].Jadwal < historis[j].Jadwal
+	})
+}
+
+// ChecksumModel menghitung sidik isi berkas model, dipakai untuk mengenali
+// berkas yang sudah pernah didaftarkan.
+func ChecksumModel(qTable, historis []byte) string {
+	h := sha256.New()
+	h.Write(qTable)
+	h.Write([]byte{0})
+	h.Write(historis)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// manifestModel mencatat sha256 kedua berkas model selama TulisBerkasModel
+// berjalan. Manifest ditulis sebelum berkasnya dan dihapus setelah keduanya
+// selesai, sehingga selama penggantian isi berkas tidak cocok dengan manifest
+// dan pasangan campuran lama-baru tidak pernah dibaca.
+type manifestModel struct {
+	QTable   string `json:"q_table_sha256"`
+	Historis string `json:"historical_best_sha256"`
+}
+
+func sidik(data []byte) string {
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
+}
+
+// berkasManifest menamai manifest menurut berkas Q-table, mis.
+// q_table_model.manifest.json, agar model kandidat di direktori yang sama
+// tidak memengaruhi model utama.
+func berkasManifest(berkasQTable string) string {
+	return strings.TrimSuffix(berkasQTable, filepath.Ext(berkasQTable)) + ".manifest.json"
+}
+
+// cocokManifest memeriksa isi berkas terhadap manifest. Tanpa manifest,
+// misalnya setelah penulisan selesai atau berkas disalin manual, berkas
+// diterima apa adanya.
+func cocokManifest(berkasQTable string, qTable, historis []byte) error {
+	path := berkasManifest(berkasQTable)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
 	if err != nil {
-		return fmt.Errorf("gagal membaca q_table_model.json: %w", err)
+		return fmt.Errorf("gagal membaca %s: %w", path, err)
 	}
-	if err := json.Unmarshal(qTableFile, &QTableModel); err != nil {
-		return fmt.Errorf("gagal unmarshal q_table_model.json: %w", err)
+
+	var manifest manifestModel
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("format %s tidak valid: %w", path, err)
 	}
-	fmt.Println("Model Q-Table berhasil dimuat.")
+	if manifest.QTable != sidik(qTable) || manifest.Historis != sidik(historis) {
+		return fmt.Errorf("%w %s; kemungkinan sedang ditulis, hapus manifest jika penulisan terhenti", ErrManifestTidakCocok, path)
+	}
+	return nil
+}
 
-	historicalFile, err := os.ReadFile("./historical_best.json")
+// BacaBerkasModel membaca dan memvalidasi q_table_model.json dan
+// historical_best.json. historical_best.json boleh tidak ada. Jika ada
+// manifest dan kedua berkas tidak cocok dengannya, model tetap dikembalikan
+// bersama error ErrManifestTidakCocok agar pemanggil dapat memutuskan.
+func BacaBerkasModel() (*Model, string, error) {
+	qTableFile, err := os.ReadFile(BerkasQTable)
 	if err != nil {
-		fmt.Println("Peringatan: file historical_best.json tidak ditemukan. Fitur fallback historis tidak akan aktif.")
-	} else {
-		var tempHistoricalMap map[string]HistoricalInfo
-		if err := json.Unmarshal(historicalFile, &tempHistoricalMap); err != nil {
-			return fmt.Errorf("gagal unmarshal historical_best.json: %w", err)
-		}
+		return nil, "", fmt.Errorf("gagal membaca q_table_model.json: %w", err)
+	}
+	tabel, err := ParseQTable(qTableFile)
+	if err != nil {
+		return nil, "", fmt.Errorf("q_table_model.json: %w", err)
+	}
 
-		for jadwal, info := range tempHistoricalMap {
-			info.Jadwal = jadwal
-			HistoricalBest = append(HistoricalBest, info)
+	model := &Model{QTable: tabel}
+	historicalFile, err := os.ReadFile(BerkasHistoris)
+	if err == nil {
+		if model.HistoricalBest, err = ParseHistoricalBest(historicalFile); err != nil {
+			return nil, "", fmt.Errorf("historical_best.json: %w", err)
 		}
+	} else if !os.IsNotExist(err) {
+		return nil, "", fmt.Errorf("gagal membaca historical_best.json: %w", err)
+	}
 
-		sort.Slice(HistoricalBest, func(i, j int) bool {
-			return HistoricalBest[i].PersentaseEfektif > HistoricalBest[j].PersentaseEfektif
-		})
+	checksum := ChecksumModel(qTableFile, historicalFile)
+	return model, checksum, cocokManifest(BerkasQTable, qTableFile, historicalFile)
+}
+
+// tulisAtomik menulis ke berkas sementara lalu mengganti namanya, sehingga
+// pemantau model tidak pernah membaca berkas yang setengah tertulis.
+func tulisAtomik(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// jsonBerindentasi seperti json.MarshalIndent tanpa meng-escape karakter HTML,
+// agar kunci "Skor >=4" tetap sama dengan berkas aslinya.
+func jsonBerindentasi(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "    ")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// TulisBerkasModel menulis model ke berkas Q-table dan data historis dengan
+// format yang sama seperti yang dibaca BacaBerkasModel. Manifest ditulis lebih
+// dulu dan dihapus setelah kedua berkas terganti, agar pembaca tidak menerima
+// pasangan berkas yang baru terganti separuh.
+func TulisBerkasModel(model *Model, berkasQTable, berkasHistoris string) error {
+	qTableFile, err := jsonBerindentasi(model.QTable)
+	if err != nil {
+		return fmt.Errorf("gagal marshal Q-table: %w", err)
+	}
+
+	peta := make(map[string]HistoricalInfo, len(model.HistoricalBest))
+	for _, info := range model.HistoricalBest {
+		peta[info.Jadwal] = info
+	}
+	historicalFile, err := jsonBerindentasi(peta)
+	if err != nil {
+		return fmt.Errorf("gagal marshal data historis: %w", err)
+	}
+
+	manifest, err := json.Marshal(manifestModel{QTable: sidik(qTableFile), Historis: sidik(historicalFile)})
+	if err != nil {
+		return err
+	}
+	manifestPath := berkasManifest(berkasQTable)
+	if err := tulisAtomik(manifestPath, manifest); err != nil {
+		return fmt.Errorf("gagal menulis %s: %w", manifestPath, err)
+	}
+	if err := tulisAtomik(berkasHistoris, historicalFile); err != nil {
+		return fmt.Errorf("gagal menulis %s: %w", berkasHistoris, err)
+	}
+	if err := tulisAtomik(berkasQTable, qTableFile); err != nil {
+		return fmt.Errorf("gagal menulis %s: %w", berkasQTable, err)
+	}
+	if err := os.Remove(manifestPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("gagal menghapus %s: %w", manifestPath, err)
+	}
+	return nil
+}
+
+// LoadQlearningModels memuat berkas model saat start. Manifest yang tidak
+// cocok hanya diperingatkan: server tetap berjalan dan versi aktif di database
+// dimuat sesudahnya.
+func LoadQlearningModels() error {
+	model, _, err := BacaBerkasModel()
+	if errors.Is(err, ErrManifestTidakCocok) {
+		fmt.Printf("Peringatan: %v\n", err)
+	} else if err != nil {
+		return err
+	}
+	fmt.Println("Model Q-Table berhasil dimuat.")
+	if len(model.HistoricalBest) == 0 {
+		fmt.Println("Peringatan: file historical_best.json tidak ditemukan. Fitur fallback historis tidak akan aktif.")
+	} else {
 		fmt.Println("Data historis untuk fallback berhasil dimuat dan diurutkan.")
 	}
 
+	SetModel(model)
 	return nil
 }
@@ -1,10 +1,12 @@
 package dto
 
+import "time"
+
 type RegisterRequest struct {
-	Nama     string `json:"nama" validate:"required"`
-	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=6"`
-	UserType string `json:"user_type" validate:"required,oneof=user admin"`
+	Nama       string `json:"nama" validate:"required"`
+	Email      string `json:"email" validate:"required,email"`
+	Password   string `json:"password" validate:"required,min=6"`
+	InviteCode string `json:"invite_code,omitempty"`
 }
 
 type LoginRequest struct {
@@ -13,13 +15,29 @@ type LoginRequest struct {
 }
 
 type ForgotPasswordRequest struct {
-	Email       string `json:"email,omitempty"`
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" validate:"required"`
 	NewPassword string `json:"new_password" validate:"required,min=6"`
 }
 
-type AuthResponse struct {
-	Token string      `json:"token"`
-	User  interface{} `json:"user"`
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
 }
 
+type TokenResponse struct {
+	Token            string    `json:"token"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
 
+type AuthResponse struct {
+	Token            string      `json:"token"`
+	ExpiresAt        time.Time   `json:"expires_at"`
+	RefreshToken     string      `json:"refresh_token"`
+	RefreshExpiresAt time.Time   `json:"refresh_expires_at"`
+	User             interface{} `json:"user"`
+}
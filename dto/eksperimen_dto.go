@@ -0,0 +1,55 @@
+package dto
+
+import "time"
+
+type VarianEksperimenRequest struct {
+	Nama string `json:"nama" validate:"required"`
+	// VersiID kosong berarti varian memakai versi model yang sedang aktif.
+	VersiID *uint `json:"versi_id"`
+	Bobot   int   `json:"bobot"`
+}
+
+type CreateEksperimenRequest struct {
+	Nama      string                    `json:"nama" validate:"required"`
+	Deskripsi string                    `json:"deskripsi"`
+	Varian    []VarianEksperimenRequest `json:"varian" validate:"required,min=2"`
+}
+
+type VarianEksperimenResponse struct {
+	ID      uint   `json:"id"`
+	Nama    string `json:"nama"`
+	VersiID *uint  `json:"versi_id"`
+	Bobot   int    `json:"bobot"`
+}
+
+type EksperimenResponse struct {
+	ID          uint                       `json:"id"`
+	Nama        string                     `json:"nama"`
+	Deskripsi   string                     `json:"deskripsi"`
+	Aktif       bool                       `json:"aktif"`
+	MulaiPada   *time.Time                 `json:"mulai_pada"`
+	SelesaiPada *time.Time                 `json:"selesai_pada"`
+	CreatedByID uint                       `json:"created_by_id"`
+	Varian      []VarianEksperimenResponse `json:"varian"`
+	CreatedAt   time.Time                  `json:"created_at"`
+}
+
+type LaporanVarianResponse struct {
+	VarianID            uint     `json:"varian_id"`
+	Nama                string   `json:"nama"`
+	VersiID             *uint    `json:"versi_id"`
+	JumlahUser          int      `json:"jumlah_user"`
+	JumlahRekomendasi   int      `json:"jumlah_rekomendasi"`
+	JumlahDiterapkan    int      `json:"jumlah_diterapkan"`
+	TingkatPenerapan    float64  `json:"tingkat_penerapan"`
+	TotalTargetHalaman  int      `json:"total_target_halaman"`
+	TotalSelesaiHalaman int      `json:"total_selesai_halaman"`
+	TingkatSelesai      float64  `json:"tingkat_selesai"`
+	JumlahPenilaian     int      `json:"jumlah_penilaian"`
+	RataRataEfektifitas *float64 `json:"rata_rata_efektifitas"`
+}
+
+type LaporanEksperimenResponse struct {
+	Eksperimen EksperimenResponse      `json:"eksperimen"`
+	Varian     []LaporanVarianResponse `json:"varian"`
+}
@@ -0,0 +1,51 @@
+package dto
+
+type CakupanStateResponse struct {
+	StateDiData     int     `json:"state_di_data"`
+	StateTercakup   int     `json:"state_tercakup"`
+	PersentaseState float64 `json:"persentase_state"`
+	DataTercakup    int     `json:"data_tercakup"`
+	PersentaseData  float64 `json:"persentase_data"`
+	StateTanpaData  int     `json:"state_tanpa_data"`
+	StateModel      int     `json:"state_model"`
+}
+
+// EstimasiIPSResponse adalah estimasi nilai kebijakan dari rekomendasi yang
+// tercatat, dengan bobot peluang kebijakan yang dievaluasi dibagi propensitas
+// kebijakan yang dulu memilihnya.
+type EstimasiIPSResponse struct {
+	JumlahSampel  int      `json:"jumlah_sampel"`
+	NilaiLogging  *float64 `json:"nilai_logging"`
+	EstimasiIPS   *float64 `json:"estimasi_ips"`
+	EstimasiSNIPS *float64 `json:"estimasi_snips"`
+}
+
+type DistribusiAksiResponse struct {
+	Aksi          string   `json:"aksi"`
+	QValue        *float64 `json:"q_value"`
+	Peluang       float64  `json:"peluang"`
+	JumlahDipakai int      `json:"jumlah_dipakai"`
+	JumlahEfektif int      `json:"jumlah_efektif"`
+}
+
+type DistribusiStateResponse struct {
+	State        string                   `json:"state"`
+	DikenalModel bool                     `json:"dikenal_model"`
+	JumlahData   int                      `json:"jumlah_data"`
+	Aksi         []DistribusiAksiResponse `json:"aksi"`
+}
+
+type EvaluasiModelResponse struct {
+	VersiID            uint    `json:"versi_id"`
+	Kebijakan          string  `json:"kebijakan"`
+	ParameterKebijakan float64 `json:"parameter_kebijakan"`
+	JumlahData         int     `json:"jumlah_data"`
+	Dilewati           int     `json:"dilewati"`
+	// HitRate adalah peluang rata-rata kebijakan merekomendasikan jadwal yang
+	// dinilai efektif (>= 4), dihitung pada data yang state-nya dikenal model.
+	HitRate       *float64                  `json:"hit_rate"`
+	JumlahEfektif int                       `json:"jumlah_efektif"`
+	Cakupan       CakupanStateResponse      `json:"cakupan"`
+	IPS           EstimasiIPSResponse       `json:"ips"`
+	Distribusi    []DistribusiStateResponse `json:"distribusi"`
+}
@@ -0,0 +1,30 @@
+package dto
+
+import "time"
+
+type KekuatanHalamanResponse struct {
+	Halaman          int        `json:"halaman"`
+	HalamanDiJuz     int        `json:"halaman_di_juz"`
+	Kekuatan         float64    `json:"kekuatan"`
+	Level            string     `json:"level"`
+	JumlahMurojaah   int        `json:"jumlah_murojaah"`
+	RatingTerakhir   string     `json:"rating_terakhir,omitempty"`
+	TerakhirMurojaah *time.Time `json:"terakhir_murojaah,omitempty"`
+}
+
+type PetaHafalanJuz struct {
+	Juz               int                       `json:"juz"`
+	JumlahHalaman     int                       `json:"jumlah_halaman"`
+	HalamanDimurojaah int                       `json:"halaman_dimurojaah"`
+	RataRataKekuatan  float64                   `json:"rata_rata_kekuatan"`
+	Halaman           []KekuatanHalamanResponse `json:"halaman"`
+}
+
+type PetaHafalanResponse struct {
+	UserID            uint             `json:"user_id"`
+	MushafLayout      string           `json:"mushaf_layout"`
+	TotalHalaman      int              `json:"total_halaman"`
+	HalamanDimurojaah int              `json:"halaman_dimurojaah"`
+	JumlahPerLevel    map[string]int   `json:"jumlah_per_level"`
+	Juz               []PetaHafalanJuz `json:"juz"`
+}
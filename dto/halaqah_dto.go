@@ -0,0 +1,78 @@
+package dto
+
+import "time"
+
+type CreateHalaqahRequest struct {
+	Nama      string `json:"nama" validate:"required"`
+	Deskripsi string `json:"deskripsi"`
+}
+
+type UpdateHalaqahRequest struct {
+	Nama      *string `json:"nama,omitempty"`
+	Deskripsi *string `json:"deskripsi,omitempty"`
+}
+
+type AddAnggotaHalaqahRequest struct {
+	UserID uint   `json:"user_id" validate:"required"`
+	Peran  string `json:"peran" validate:"required,oneof=santri mentor"`
+}
+
+// AnggotaHalaqahResponse tanpa UserID berarti nama anggota disamarkan.
+type AnggotaHalaqahResponse struct {
+	UserID          *uint     `json:"user_id,omitempty"`
+	Nama            string    `json:"nama"`
+	Email           string    `json:"email,omitempty"`
+	Peran           string    `json:"peran"`
+	SembunyikanNama bool      `json:"sembunyikan_nama"`
+	Sejak           time.Time `json:"sejak"`
+}
+
+type HalaqahResponse struct {
+	ID           uint                     `json:"id"`
+	Nama         string                   `json:"nama"`
+	Deskripsi    string                   `json:"deskripsi"`
+	CreatedByID  uint                     `json:"created_by_id"`
+	JumlahSantri int                      `json:"jumlah_santri"`
+	JumlahMentor int                      `json:"jumlah_mentor"`
+	Anggota      []AnggotaHalaqahResponse `json:"anggota,omitempty"`
+	CreatedAt    time.Time                `json:"created_at"`
+}
+
+type UpdatePrivasiHalaqahRequest struct {
+	SembunyikanNama bool `json:"sembunyikan_nama"`
+}
+
+type LeaderboardEntry struct {
+	Peringkat           int     `json:"peringkat"`
+	UserID              *uint   `json:"user_id,omitempty"`
+	Nama                string  `json:"nama"`
+	Tersembunyi         bool    `json:"tersembunyi"`
+	TotalSelesaiHalaman int     `json:"total_selesai_halaman"`
+	TotalHariAktif      int     `json:"total_hari_aktif"`
+	RataRataPerHari     float64 `json:"rata_rata_halaman_per_hari"`
+	StreakSaatIni       int     `json:"streak_saat_ini"`
+	StreakTerpanjang    int     `json:"streak_terpanjang"`
+}
+
+type LeaderboardResponse struct {
+	HalaqahID    uint               `json:"halaqah_id"`
+	TanggalMulai string             `json:"tanggal_mulai"`
+	TanggalAkhir string             `json:"tanggal_akhir"`
+	Peringkat    []LeaderboardEntry `json:"peringkat"`
+}
+
+type ProgressHarianHalaqah struct {
+	Tanggal             string `json:"tanggal"`
+	TotalTargetHalaman  int    `json:"total_target_halaman"`
+	TotalSelesaiHalaman int    `json:"total_selesai_halaman"`
+	JumlahAnggotaAktif  int    `json:"jumlah_anggota_aktif"`
+}
+
+type ProgressHalaqahResponse struct {
+	HalaqahID           uint                    `json:"halaqah_id"`
+	TanggalMulai        string                  `json:"tanggal_mulai"`
+	TanggalAkhir        string                  `json:"tanggal_akhir"`
+	JumlahSantri        int                     `json:"jumlah_santri"`
+	TotalSelesaiHalaman int                     `json:"total_selesai_halaman"`
+	PerHari             []ProgressHarianHalaqah `json:"per_hari"`
+}
@@ -0,0 +1,21 @@
+package dto
+
+import "time"
+
+type CreateInvitationRequest struct {
+	Role         string `json:"role" validate:"required,oneof=user ustadz admin"`
+	Email        string `json:"email,omitempty" validate:"omitempty,email"`
+	ExpiresInJam int    `json:"expires_in_jam,omitempty" validate:"omitempty,min=1"`
+}
+
+type InvitationResponse struct {
+	ID          uint       `json:"id"`
+	Code        string     `json:"code,omitempty"`
+	Role        string     `json:"role"`
+	Email       *string    `json:"email"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	UsedAt      *time.Time `json:"used_at"`
+	UsedByID    *uint      `json:"used_by_id"`
+	CreatedByID uint       `json:"created_by_id"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
@@ -0,0 +1,24 @@
+package dto
+
+import "time"
+
+type JobRunResponse struct {
+	ID          uint       `json:"id"`
+	JobName     string     `json:"job_name"`
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty"`
+	Pemicu      string     `json:"pemicu"`
+	Status      string     `json:"status"`
+	Host        string     `json:"host"`
+	Hasil       string     `json:"hasil,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	StartedAt   time.Time  `json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at"`
+	DurasiMs    int64      `json:"durasi_ms"`
+}
+
+type JobResponse struct {
+	Name    string          `json:"name"`
+	Spec    string          `json:"spec"`
+	NextRun *time.Time      `json:"next_run"`
+	LastRun *JobRunResponse `json:"last_run"`
+}
@@ -2,31 +2,58 @@ package dto
 
 import "time"
 
+// RentangTargetRequest menerima awal dan akhir target dalam salah satu dari tiga
+// bentuk: juz + halaman di dalam juz, halaman mutlak mushaf, atau surah:ayat.
+type RentangTargetRequest struct {
+	TargetStartJuz     int    `json:"target_start_juz,omitempty" validate:"omitempty,min=1,max=30"`
+	TargetStartHalaman int    `json:"target_start_halaman,omitempty" validate:"omitempty,min=1"`
+	TargetEndJuz       int    `json:"target_end_juz,omitempty" validate:"omitempty,min=1,max=30"`
+	TargetEndHalaman   int    `json:"target_end_halaman,omitempty" validate:"omitempty,min=1"`
+	TargetStartPage    int    `json:"target_start_page,omitempty" validate:"omitempty,min=1"`
+	TargetEndPage      int    `json:"target_end_page,omitempty" validate:"omitempty,min=1"`
+	TargetStartAyat    string `json:"target_start_ayat,omitempty"`
+	TargetEndAyat      string `json:"target_end_ayat,omitempty"`
+}
+
 type AddDetailLogRequest struct {
-	WaktuMurojaah      string `json:"waktu_murojaah" validate:"required"`
-	TargetStartJuz     int    `json:"target_start_juz" validate:"required,min=1,max=30"`
-	TargetStartHalaman int    `json:"target_start_halaman" validate:"required,min=1,max=20"`
-	TargetEndJuz       int    `json:"target_end_juz" validate:"required,min=1,max=30"`
-	TargetEndHalaman   int    `json:"target_end_halaman" validate:"required,min=1,max=20"`
-	Catatan            string `json:"catatan"`
+	WaktuMurojaah string `json:"waktu_murojaah" validate:"required"`
+	RentangTargetRequest
+	Catatan string `json:"catatan"`
+}
+
+// RatingHalamanRequest memberi penilaian untuk satu halaman mutlak mushaf:
+// "lancar", "kurang_lancar", atau "banyak_salah".
+type RatingHalamanRequest struct {
+	Halaman int    `json:"halaman" validate:"required,min=1"`
+	Rating  string `json:"rating" validate:"required"`
 }
 
 type UpdateDetailLogRequest struct {
-	SelesaiEndJuz     int    `json:"selesai_end_juz" validate:"required,min=1,max=30"`
-	SelesaiEndHalaman int    `json:"selesai_end_halaman" validate:"required,min=1,max=20"`
-	Catatan           string `json:"catatan"`
+	SelesaiEndJuz     int                    `json:"selesai_end_juz,omitempty" validate:"omitempty,min=1,max=30"`
+	SelesaiEndHalaman int                    `json:"selesai_end_halaman,omitempty" validate:"omitempty,min=1"`
+	SelesaiEndPage    int                    `json:"selesai_end_page,omitempty" validate:"omitempty,min=1"`
+	SelesaiEndAyat    string                 `json:"selesai_end_ayat,omitempty"`
+	RatingHalaman     []RatingHalamanRequest `json:"rating_halaman,omitempty"`
+	Catatan           string                 `json:"catatan"`
 }
 
 type DetailLogResponse struct {
 	ID                  uint      `json:"id"`
 	WaktuMurojaah       string    `json:"waktu_murojaah"`
+	Slot                string    `json:"slot"`
+	MushafLayout        string    `json:"mushaf_layout"`
 	TargetStartJuz      int       `json:"target_start_juz"`
 	TargetStartHalaman  int       `json:"target_start_halaman"`
 	TargetEndJuz        int       `json:"target_end_juz"`
 	TargetEndHalaman    int       `json:"target_end_halaman"`
+	TargetStartPage     int       `json:"target_start_page"`
+	TargetEndPage       int       `json:"target_end_page"`
+	TargetStartAyat     string    `json:"target_start_ayat"`
+	TargetEndAyat       string    `json:"target_end_ayat"`
 	TotalTargetHalaman  int       `json:"total_target_halaman"`
 	SelesaiEndJuz       int       `json:"selesai_end_juz"`
 	SelesaiEndHalaman   int       `json:"selesai_end_halaman"`
+	SelesaiEndPage      int       `json:"selesai_end_page"`
 	TotalSelesaiHalaman int       `json:"total_selesai_halaman"`
 	Status              string    `json:"status"`
 	Catatan             string    `json:"catatan"`
@@ -43,10 +70,43 @@ type LogHarianResponse struct {
 }
 
 type ApplyAIRekomendasiRequest struct {
-	RekomendasiID      uint   `json:"rekomendasi_id" validate:"required"`
-	TargetStartJuz     int    `json:"target_start_juz" validate:"required,min=1,max=30"`
-	TargetStartHalaman int    `json:"target_start_halaman" validate:"required,min=1,max=20"`
-	TargetEndJuz       int    `json:"target_end_juz" validate:"required,min=1,max=30"`
-	TargetEndHalaman   int    `json:"target_end_halaman" validate:"required,min=1,max=20"`
-	Catatan            string `json:"catatan"`
+	RekomendasiID uint `json:"rekomendasi_id" validate:"required"`
+	// WaktuMurojaah harus salah satu slot dari rekomendasi; kosong berarti slot pertama.
+	WaktuMurojaah string `json:"waktu_murojaah,omitempty"`
+	RentangTargetRequest
+	Catatan string `json:"catatan"`
+}
+
+type RencanaDetailLog struct {
+	WaktuMurojaah      string  `json:"waktu_murojaah"`
+	TargetStartJuz     int     `json:"target_start_juz"`
+	TargetStartHalaman int     `json:"target_start_halaman"`
+	TargetEndJuz       int     `json:"target_end_juz"`
+	TargetEndHalaman   int     `json:"target_end_halaman"`
+	TargetStartPage    int     `json:"target_start_page"`
+	TargetEndPage      int     `json:"target_end_page"`
+	TotalTargetHalaman int     `json:"total_target_halaman"`
+	RataRataKekuatan   float64 `json:"rata_rata_kekuatan"`
+}
+
+type RencanaMurojaahResponse struct {
+	Tanggal            string             `json:"tanggal"`
+	MushafLayout       string             `json:"mushaf_layout"`
+	TotalJatuhTempo    int                `json:"total_jatuh_tempo"`
+	TotalTargetHalaman int                `json:"total_target_halaman"`
+	Rencana            []RencanaDetailLog `json:"rencana"`
+}
+
+type RolloverLogResponse struct {
+	ID              uint      `json:"id"`
+	DetailLogAsalID uint      `json:"detail_log_asal_id"`
+	DetailLogBaruID *uint     `json:"detail_log_baru_id"`
+	TanggalAsal     string    `json:"tanggal_asal"`
+	TanggalTujuan   string    `json:"tanggal_tujuan"`
+	WaktuMurojaah   string    `json:"waktu_murojaah"`
+	MushafLayout    string    `json:"mushaf_layout"`
+	StartPage       int       `json:"start_page"`
+	EndPage         int       `json:"end_page"`
+	TotalHalaman    int       `json:"total_halaman"`
+	CreatedAt       time.Time `json:"created_at"`
 }
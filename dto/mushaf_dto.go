@@ -0,0 +1,26 @@
+package dto
+
+type JuzMushafResponse struct {
+	Juz           int    `json:"juz"`
+	HalamanAwal   int    `json:"halaman_awal"`
+	JumlahHalaman int    `json:"jumlah_halaman"`
+	AyatAwal      string `json:"ayat_awal"`
+}
+
+type LayoutMushafResponse struct {
+	Kode         string              `json:"kode"`
+	Nama         string              `json:"nama"`
+	TotalHalaman int                 `json:"total_halaman"`
+	Catatan      string              `json:"catatan,omitempty"`
+	Juz          []JuzMushafResponse `json:"juz,omitempty"`
+}
+
+type HalamanMushafResponse struct {
+	Halaman      int    `json:"halaman"`
+	Juz          int    `json:"juz"`
+	HalamanDiJuz int    `json:"halaman_di_juz"`
+	AyatAwal     string `json:"ayat_awal"`
+	AyatAkhir    string `json:"ayat_akhir"`
+	SurahAwal    string `json:"surah_awal"`
+	SurahAkhir   string `json:"surah_akhir"`
+}
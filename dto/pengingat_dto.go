@@ -0,0 +1,43 @@
+package dto
+
+import "time"
+
+type CreateKanalNotifikasiRequest struct {
+	Jenis  string `json:"jenis" validate:"required"`
+	Tujuan string `json:"tujuan" validate:"required"`
+}
+
+type KanalNotifikasiResponse struct {
+	ID        uint      `json:"id"`
+	Jenis     string    `json:"jenis"`
+	Tujuan    string    `json:"tujuan"`
+	Aktif     bool      `json:"aktif"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type UpdatePengaturanSlotRequest struct {
+	Slot      string `json:"slot" validate:"required"`
+	Dibisukan bool   `json:"dibisukan"`
+}
+
+type TundaSlotRequest struct {
+	Slot  string `json:"slot" validate:"required"`
+	Menit int    `json:"menit" validate:"required,min=1,max=240"`
+}
+
+type SlotPengingatResponse struct {
+	Slot        string     `json:"slot"`
+	WaktuAcuan  string     `json:"waktu_acuan,omitempty"`
+	JadwalAt    *time.Time `json:"jadwal_at"`
+	Dibisukan   bool       `json:"dibisukan"`
+	TundaSampai *time.Time `json:"tunda_sampai,omitempty"`
+	TerkirimAt  *time.Time `json:"terkirim_at,omitempty"`
+	Dikenali    bool       `json:"dikenali"`
+}
+
+type HasilKirimKanal struct {
+	KanalID uint   `json:"kanal_id"`
+	Jenis   string `json:"jenis"`
+	Sukses  bool   `json:"sukses"`
+	Error   string `json:"error,omitempty"`
+}
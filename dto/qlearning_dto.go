@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type QTableVersiResponse struct {
+	ID           uint      `json:"id"`
+	Sumber       string    `json:"sumber"`
+	IndukID      *uint     `json:"induk_id"`
+	Alpha        float64   `json:"alpha"`
+	Gamma        float64   `json:"gamma"`
+	JumlahUpdate int       `json:"jumlah_update"`
+	Aktif        bool      `json:"aktif"`
+	Catatan      string    `json:"catatan,omitempty"`
+	Checksum     string    `json:"checksum,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type QTableVersiDetailResponse struct {
+	QTableVersiResponse
+	QTable         map[string]map[string]float64 `json:"q_table"`
+	HistoricalBest []HistorisJadwalResponse      `json:"historical_best"`
+}
+
+type HistorisJadwalResponse struct {
+	Jadwal            string  `json:"jadwal"`
+	TotalPenggunaan   int     `json:"total_penggunaan"`
+	PenggunaanEfektif int     `json:"penggunaan_efektif"`
+	PersentaseEfektif float64 `json:"persentase_efektif"`
+}
+
+// UnggahModelRequest berisi isi q_table_model.json dan historical_best.json.
+// Jika historical_best kosong, data historis versi aktif dipakai.
+type UnggahModelRequest struct {
+	QTable         json.RawMessage `json:"q_table"`
+	HistoricalBest json.RawMessage `json:"historical_best"`
+	Catatan        string          `json:"catatan"`
+	// Aktifkan menentukan apakah versi baru langsung dipakai; default true.
+	Aktifkan *bool `json:"aktifkan"`
+}
+
+type UmpanBalikRekomendasiResponse struct {
+	ID             uint      `json:"id"`
+	RekomendasiID  uint      `json:"rekomendasi_id"`
+	UserID         uint      `json:"user_id"`
+	VersiID        uint      `json:"versi_id"`
+	State          string    `json:"state"`
+	Aksi           string    `json:"aksi"`
+	TanggalMulai   string    `json:"tanggal_mulai"`
+	TanggalSelesai string    `json:"tanggal_selesai"`
+	TingkatSelesai float64   `json:"tingkat_selesai"`
+	Efektifitas    int       `json:"efektifitas"`
+	Reward         float64   `json:"reward"`
+	QLama          float64   `json:"q_lama"`
+	QBaru          float64   `json:"q_baru"`
+	CreatedAt      time.Time `json:"created_at"`
+}
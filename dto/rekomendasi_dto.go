@@ -1,8 +1,26 @@
 package dto
 
+// RecommendationRequest memakai kesibukan dan total hafalan dari jadwal
+// personal user jika Kesibukan atau KategoriHafalan kosong.
 type RecommendationRequest struct {
 	Kesibukan       string `json:"kesibukan"`
 	KategoriHafalan string `json:"kategori_hafalan"`
+	// TopN meminta hingga N alternatif jadwal berperingkat; 0 atau 1 berarti
+	// hanya rekomendasi utama.
+	TopN int `json:"top_n"`
+}
+
+type PilihRekomendasiRequest struct {
+	Jadwal string `json:"jadwal" validate:"required"`
+}
+
+type AlternatifRekomendasi struct {
+	Peringkat                 int      `json:"peringkat"`
+	RekomendasiJadwal         string   `json:"rekomendasi_jadwal"`
+	EstimasiQValue            *float64 `json:"estimasi_q_value,omitempty"`
+	PersentaseEfektifHistoris *float64 `json:"persentase_efektif_historis,omitempty"`
+	SelisihDariTerbaik        *float64 `json:"selisih_dari_terbaik,omitempty"`
+	Alasan                    string   `json:"alasan"`
 }
 
 type RecommendationResponse struct {
@@ -13,4 +31,19 @@ type RecommendationResponse struct {
 	TipeRekomendasi           string   `json:"tipe_rekomendasi"`
 	EstimasiQValue            *float64 `json:"estimasi_q_value,omitempty"`
 	PersentaseEfektifHistoris *float64 `json:"persentase_efektif_historis,omitempty"`
+	Kebijakan                 string   `json:"kebijakan,omitempty"`
+	Propensitas               *float64 `json:"propensitas,omitempty"`
+	Alasan                    string   `json:"alasan,omitempty"`
+	JadwalDipilih             *string  `json:"jadwal_dipilih,omitempty"`
+	ModelVersiID              *uint    `json:"model_versi_id,omitempty"`
+	Kesibukan                 string   `json:"kesibukan,omitempty"`
+	KategoriHafalan           string   `json:"kategori_hafalan,omitempty"`
+
+	Alternatif  []AlternatifRekomendasi `json:"alternatif,omitempty"`
+	StateSumber []StateMiripResponse    `json:"state_sumber,omitempty"`
+}
+
+type StateMiripResponse struct {
+	State     string  `json:"state"`
+	Kemiripan float64 `json:"kemiripan"`
 }
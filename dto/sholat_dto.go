@@ -0,0 +1,20 @@
+package dto
+
+import "time"
+
+type WaktuSholatResponse struct {
+	Waktu string    `json:"waktu"`
+	Jam   string    `json:"jam"`
+	Pada  time.Time `json:"pada"`
+}
+
+type JadwalSholatResponse struct {
+	Tanggal   string                `json:"tanggal"`
+	Latitude  float64               `json:"latitude"`
+	Longitude float64               `json:"longitude"`
+	Elevasi   float64               `json:"elevasi"`
+	ZonaWaktu string                `json:"zona_waktu"`
+	Metode    string                `json:"metode"`
+	Madhab    string                `json:"madhab"`
+	Jadwal    []WaktuSholatResponse `json:"jadwal"`
+}
@@ -5,12 +5,35 @@ type UserResponse struct {
 	Nama                 string                  `json:"nama"`
 	Email                string                  `json:"email"`
 	UserType             string                  `json:"user_type"`
+	UstadzID             *uint                   `json:"ustadz_id,omitempty"`
+	MushafLayout         string                  `json:"mushaf_layout"`
+	RolloverOtomatis     bool                    `json:"rollover_otomatis"`
+	ZonaWaktu            string                  `json:"zona_waktu"`
+	Lokasi               LokasiUserResponse      `json:"lokasi"`
 	IsDataMurojaahFilled bool                    `json:"is_data_murojaah_filled"`
 	JadwalPersonal       *JadwalPersonalResponse `json:"jadwal_personal,omitempty"`
 }
 
 type UpdateUserRequest struct {
-	Nama     *string `json:"nama,omitempty"`
-	Email    *string `json:"email,omitempty"`
-	UserType *string `json:"user_type,omitempty"`
+	Nama             *string `json:"nama,omitempty"`
+	Email            *string `json:"email,omitempty"`
+	UserType         *string `json:"user_type,omitempty"`
+	UstadzID         *uint   `json:"ustadz_id,omitempty"`
+	MushafLayout     *string `json:"mushaf_layout,omitempty"`
+	RolloverOtomatis *bool   `json:"rollover_otomatis,omitempty"`
+	ZonaWaktu        *string `json:"zona_waktu,omitempty"`
+
+	Latitude     *float64 `json:"latitude,omitempty"`
+	Longitude    *float64 `json:"longitude,omitempty"`
+	Elevasi      *float64 `json:"elevasi,omitempty"`
+	MetodeSholat *string  `json:"metode_sholat,omitempty"`
+	Madhab       *string  `json:"madhab,omitempty"`
+}
+
+type LokasiUserResponse struct {
+	Latitude     *float64 `json:"latitude"`
+	Longitude    *float64 `json:"longitude"`
+	Elevasi      float64  `json:"elevasi"`
+	MetodeSholat string   `json:"metode_sholat"`
+	Madhab       string   `json:"madhab"`
 }
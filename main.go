@@ -1,9 +1,11 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"time"
+	_ "time/tzdata"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -11,6 +13,8 @@ import (
 	"github.com/habbazettt/muraja-server/config"
 	"github.com/habbazettt/muraja-server/middlewares"
 	"github.com/habbazettt/muraja-server/routes"
+	"github.com/habbazettt/muraja-server/scheduler"
+	"github.com/habbazettt/muraja-server/services"
 	"github.com/sirupsen/logrus"
 )
 
@@ -18,12 +22,31 @@ func main() {
 	config.LoadEnv()
 	config.InitLogger()
 
+	if runCommand(os.Args[1:]) {
+		return
+	}
+
 	db := config.ConnectDB()
 	config.MigrateDB()
+	config.BootstrapAdminFromEnv()
 
 	if err := config.LoadQlearningModels(); err != nil {
 		log.Fatalf("Gagal memuat model Q-Learning: %v", err)
 	}
+	if err := services.SinkronkanModel(db); err != nil {
+		logrus.WithError(err).Error("Gagal memuat model dari database, memakai q_table_model.json")
+	}
+	go services.PantauModel(context.Background(), db)
+
+	pengingatService := services.NewPengingatService(db)
+
+	jobScheduler := scheduler.New(db)
+	if err := services.RegisterJobs(jobScheduler, db, pengingatService); err != nil {
+		log.Fatalf("Gagal mendaftarkan job: %v", err)
+	}
+	if os.Getenv("SCHEDULER_ENABLED") != "false" {
+		jobScheduler.Start(context.Background())
+	}
 
 	app := fiber.New()
 
@@ -55,10 +78,18 @@ func main() {
 	})
 
 	routes.SetupAuthRoutes(app, db)
+	routes.SetupInvitationRoutes(app, db)
 	routes.SetupUserRoutes(app, db)
 	routes.SetupJadwalPersonalRoutes(app, db)
 	routes.SetupRekomendasiRoutes(app, db)
 	routes.SetupLogMurojaahRoutes(app, db)
+	routes.SetupHalaqahRoutes(app, db)
+	routes.SetupMushafRoutes(app, db)
+	routes.SetupJobRoutes(app, db, jobScheduler)
+	routes.SetupPengingatRoutes(app, pengingatService)
+	routes.SetupSholatRoutes(app, db)
+	routes.SetupQLearningRoutes(app, db)
+	routes.SetupEksperimenRoutes(app, db)
 
 	port := os.Getenv("PORT")
 	if port == "" {
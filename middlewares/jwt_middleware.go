@@ -1,11 +1,16 @@
 package middlewares
 
 import (
+	"errors"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/habbazettt/muraja-server/config"
+	"github.com/habbazettt/muraja-server/models"
 	"github.com/habbazettt/muraja-server/utils"
 	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
 func JWTMiddleware(c *fiber.Ctx) error {
@@ -27,6 +32,41 @@ func JWTMiddleware(c *fiber.Ctx) error {
 		return utils.ResponseError(c, fiber.StatusUnauthorized, "Invalid token", err.Error())
 	}
 
+	if claims.RegisteredClaims.ID == "" {
+		logrus.WithField("user_id", claims.ID).Warn("Unauthorized access attempt: Token without jti")
+		return utils.ResponseError(c, fiber.StatusUnauthorized, "Invalid token", "token tidak memiliki jti")
+	}
+
+	var activeSessions int64
+	if err := config.DB.Model(&models.Session{}).
+		Where("access_token_jti = ? AND revoked_at IS NULL", claims.RegisteredClaims.ID).
+		Count(&activeSessions).Error; err != nil {
+		logrus.WithError(err).Error("Failed to check token revocation")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Failed to verify token", nil)
+	}
+	if activeSessions == 0 {
+		logrus.WithField("user_id", claims.ID).Warn("Unauthorized access attempt: Revoked token")
+		return utils.ResponseError(c, fiber.StatusUnauthorized, "Invalid token", "token telah dicabut")
+	}
+
+	currentUser, err := resolveCurrentUser(claims.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logrus.WithField("user_id", claims.ID).Warn("Unauthorized access attempt: User no longer exists")
+			return utils.ResponseError(c, fiber.StatusUnauthorized, "Invalid token", "user tidak ditemukan")
+		}
+		logrus.WithError(err).Error("Failed to resolve current user")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Failed to verify token", nil)
+	}
+
+	if currentUser.TokenVersion != claims.Version {
+		logrus.WithField("user_id", claims.ID).Warn("Unauthorized access attempt: Outdated token version")
+		return utils.ResponseError(c, fiber.StatusUnauthorized, "Invalid token", "token sudah tidak berlaku, silakan login kembali")
+	}
+
+	// Role selalu diambil dari data terbaru, bukan dari isi token.
+	claims.Role = currentUser.Role
+
 	logrus.WithFields(logrus.Fields{
 		"user_id": claims.ID,
 		"role":    claims.Role,
@@ -36,6 +76,21 @@ func JWTMiddleware(c *fiber.Ctx) error {
 	return c.Next()
 }
 
+func resolveCurrentUser(userID uint) (utils.CachedUser, error) {
+	if cached, ok := utils.GetCachedUser(userID); ok {
+		return cached, nil
+	}
+
+	var user models.User
+	if err := config.DB.Select("id", "user_type", "token_version").First(&user, userID).Error; err != nil {
+		return utils.CachedUser{}, err
+	}
+
+	current := utils.CachedUser{Role: user.UserType, TokenVersion: user.TokenVersion}
+	utils.SetCachedUser(userID, current, config.GetEnvDuration("USER_CACHE_TTL", 30*time.Second))
+	return current, nil
+}
+
 func RoleMiddleware(allowedRoles ...string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		user := c.Locals("user").(*utils.Claims)
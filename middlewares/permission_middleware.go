@@ -0,0 +1,32 @@
+package middlewares
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/habbazettt/muraja-server/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// RequirePermission hanya meneruskan request jika role user memiliki semua
+// permission yang diminta. Harus dipasang setelah JWTMiddleware.
+func RequirePermission(permissions ...utils.Permission) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user, ok := c.Locals("user").(*utils.Claims)
+		if !ok || user == nil {
+			return utils.ResponseError(c, fiber.StatusUnauthorized, "Unauthorized", nil)
+		}
+
+		for _, permission := range permissions {
+			if !utils.HasPermission(user.Role, permission) {
+				logrus.WithFields(logrus.Fields{
+					"user_id":    user.ID,
+					"role":       user.Role,
+					"permission": permission,
+				}).Warn("Unauthorized access attempt: Missing permission")
+
+				return utils.ResponseError(c, fiber.StatusForbidden, "You are not authorized to access this resource", nil)
+			}
+		}
+
+		return c.Next()
+	}
+}
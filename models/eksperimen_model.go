@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// Eksperimen membandingkan beberapa versi model rekomendasi. Hanya satu
+// eksperimen yang boleh berjalan; user dibagi ke varian secara deterministik
+// dari ID-nya.
+type Eksperimen struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	Nama        string     `gorm:"type:varchar(100);not null;uniqueIndex" json:"nama"`
+	Deskripsi   string     `gorm:"type:text" json:"deskripsi"`
+	Aktif       bool       `gorm:"not null;default:false;index" json:"aktif"`
+	MulaiPada   *time.Time `json:"mulai_pada"`
+	SelesaiPada *time.Time `json:"selesai_pada"`
+	CreatedByID uint       `gorm:"not null" json:"created_by_id"`
+
+	Varian []VarianEksperimen `gorm:"foreignKey:EksperimenID;constraint:OnDelete:CASCADE;" json:"varian"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// VarianEksperimen memakai versi model VersiID, atau versi yang sedang aktif
+// jika nil (kelompok kontrol). Bobot menentukan proporsi user yang masuk.
+type VarianEksperimen struct {
+	ID           uint   `gorm:"primaryKey" json:"id"`
+	EksperimenID uint   `gorm:"not null;uniqueIndex:idx_varian_eksperimen_nama" json:"eksperimen_id"`
+	Nama         string `gorm:"type:varchar(50);not null;uniqueIndex:idx_varian_eksperimen_nama" json:"nama"`
+	VersiID      *uint  `json:"versi_id"`
+	Bobot        int    `gorm:"not null;default:1" json:"bobot"`
+
+	Versi *QTableVersi `gorm:"foreignKey:VersiID;constraint:OnDelete:RESTRICT;" json:"-"`
+}
+
+// PenugasanEksperimen mencatat varian seorang user agar tetap sama meskipun
+// bobot varian berubah.
+type PenugasanEksperimen struct {
+	ID           uint `gorm:"primaryKey" json:"id"`
+	EksperimenID uint `gorm:"not null;uniqueIndex:idx_penugasan_eksperimen_user" json:"eksperimen_id"`
+	UserID       uint `gorm:"not null;uniqueIndex:idx_penugasan_eksperimen_user;index" json:"user_id"`
+	VarianID     uint `gorm:"not null;index" json:"varian_id"`
+
+	Eksperimen *Eksperimen       `gorm:"foreignKey:EksperimenID;constraint:OnDelete:CASCADE;" json:"-"`
+	Varian     *VarianEksperimen `gorm:"foreignKey:VarianID;constraint:OnDelete:CASCADE;" json:"-"`
+	User       *User             `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;" json:"-"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
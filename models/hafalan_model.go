@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+type RatingHalaman string
+
+const (
+	RatingLancar       RatingHalaman = "lancar"
+	RatingKurangLancar RatingHalaman = "kurang_lancar"
+	RatingBanyakSalah  RatingHalaman = "banyak_salah"
+)
+
+func IsValidRatingHalaman(rating string) bool {
+	switch RatingHalaman(rating) {
+	case RatingLancar, RatingKurangLancar, RatingBanyakSalah:
+		return true
+	}
+	return false
+}
+
+// KekuatanHalaman menyimpan kekuatan hafalan satu halaman milik user. Nomor
+// halaman mengikuti layout mushaf default.
+type KekuatanHalaman struct {
+	ID               uint          `gorm:"primaryKey" json:"id"`
+	UserID           uint          `gorm:"not null;uniqueIndex:idx_kekuatan_user_halaman" json:"user_id"`
+	Halaman          int           `gorm:"not null;uniqueIndex:idx_kekuatan_user_halaman" json:"halaman"`
+	Kekuatan         float64       `gorm:"not null;default:0" json:"kekuatan"`
+	JumlahMurojaah   int           `gorm:"not null;default:0" json:"jumlah_murojaah"`
+	RatingTerakhir   RatingHalaman `gorm:"type:varchar(50)" json:"rating_terakhir"`
+	TerakhirMurojaah time.Time     `json:"terakhir_murojaah"`
+
+	// Status penjadwalan spaced-repetition (SM-2).
+	EaseFactor   float64    `gorm:"not null;default:2.5" json:"ease_factor"`
+	IntervalHari int        `gorm:"not null;default:0" json:"interval_hari"`
+	Repetisi     int        `gorm:"not null;default:0" json:"repetisi"`
+	JatuhTempo   *time.Time `gorm:"type:date;index" json:"jatuh_tempo"`
+
+	User *User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;" json:"-"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+type PeranHalaqah string
+
+const (
+	PeranSantri PeranHalaqah = "santri"
+	PeranMentor PeranHalaqah = "mentor"
+)
+
+type Halaqah struct {
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	Nama        string `gorm:"type:varchar(255);not null" json:"nama"`
+	Deskripsi   string `gorm:"type:text" json:"deskripsi"`
+	CreatedByID uint   `gorm:"not null" json:"created_by_id"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Anggota []HalaqahAnggota `gorm:"foreignKey:HalaqahID;constraint:OnDelete:CASCADE;" json:"anggota,omitempty"`
+}
+
+type HalaqahAnggota struct {
+	ID        uint         `gorm:"primaryKey" json:"id"`
+	HalaqahID uint         `gorm:"not null;uniqueIndex:idx_halaqah_user" json:"halaqah_id"`
+	UserID    uint         `gorm:"not null;uniqueIndex:idx_halaqah_user;index" json:"user_id"`
+	Peran     PeranHalaqah `gorm:"type:varchar(20);not null" json:"peran"`
+	// SembunyikanNama membuat nama anggota tidak ditampilkan di leaderboard
+	// maupun daftar anggota halaqah kepada sesama santri.
+	SembunyikanNama bool `gorm:"not null;default:false" json:"sembunyikan_nama"`
+
+	CreatedAt time.Time `json:"created_at"`
+
+	User *User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;" json:"user,omitempty"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+type Invitation struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	CodeHash    string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	Role        string     `gorm:"type:varchar(255);not null" json:"role"`
+	Email       *string    `gorm:"type:varchar(255)" json:"email"`
+	ExpiresAt   time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt      *time.Time `json:"used_at"`
+	UsedByID    *uint      `json:"used_by_id"`
+	CreatedByID uint       `gorm:"not null" json:"created_by_id"`
+
+	CreatedAt time.Time `json:"created_at"`
+
+	CreatedBy *User `gorm:"foreignKey:CreatedByID;constraint:OnDelete:CASCADE;" json:"-"`
+	UsedBy    *User `gorm:"foreignKey:UsedByID;constraint:OnDelete:SET NULL;" json:"-"`
+}
@@ -2,15 +2,20 @@ package models
 
 import (
 	"time"
+
+	"github.com/habbazettt/muraja-server/slot"
 )
 
 type JadwalPersonal struct {
-	ID                uint      `gorm:"primaryKey" json:"id"`
-	UserID            uint      `gorm:"unique;not null" json:"user_id"`
-	TotalHafalan      int       `gorm:"not null" json:"total_hafalan"`
-	Kesibukan         string    `gorm:"type:varchar(255);not null" json:"kesibukan"`
-	Jadwal            string    `gorm:"type:varchar(255)" json:"jadwal"`
-	EfektifitasJadwal int       `gorm:"not null" json:"efektifitas_jadwal"`
+	ID                uint        `gorm:"primaryKey" json:"id"`
+	UserID            uint        `gorm:"unique;not null" json:"user_id"`
+	TotalHafalan      int         `gorm:"not null" json:"total_hafalan"`
+	Kesibukan         string      `gorm:"type:varchar(255);not null" json:"kesibukan"`
+	Jadwal            slot.Daftar `gorm:"type:jsonb;not null;default:'[]'" json:"jadwal"`
+	EfektifitasJadwal int         `gorm:"not null" json:"efektifitas_jadwal"`
+	// EfektifitasDinilaiPada hanya berubah saat EfektifitasJadwal berubah,
+	// tidak seperti UpdatedAt yang ikut berubah oleh kolom lain.
+	EfektifitasDinilaiPada *time.Time `json:"efektifitas_dinilai_pada"`
 
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+type StatusJobRun string
+
+const (
+	StatusJobBerjalan StatusJobRun = "berjalan"
+	StatusJobSukses   StatusJobRun = "sukses"
+	StatusJobGagal    StatusJobRun = "gagal"
+)
+
+const (
+	PemicuJadwal = "jadwal"
+	PemicuManual = "manual"
+)
+
+// JobRun mencatat satu kali eksekusi job terjadwal. ScheduledAt hanya diisi
+// untuk eksekusi terjadwal; unique index-nya memastikan satu jadwal hanya
+// dijalankan sekali meskipun ada beberapa replika.
+type JobRun struct {
+	ID          uint         `gorm:"primaryKey" json:"id"`
+	JobName     string       `gorm:"type:varchar(100);not null;index;uniqueIndex:idx_job_run_jadwal" json:"job_name"`
+	ScheduledAt *time.Time   `gorm:"uniqueIndex:idx_job_run_jadwal" json:"scheduled_at"`
+	Pemicu      string       `gorm:"type:varchar(20);not null" json:"pemicu"`
+	Status      StatusJobRun `gorm:"type:varchar(20);not null" json:"status"`
+	Host        string       `gorm:"type:varchar(255)" json:"host"`
+	Hasil       string       `gorm:"type:text" json:"hasil"`
+	Error       string       `gorm:"type:text" json:"error"`
+	StartedAt   time.Time    `gorm:"not null;index" json:"started_at"`
+	FinishedAt  *time.Time   `json:"finished_at"`
+	DurasiMs    int64        `json:"durasi_ms"`
+}
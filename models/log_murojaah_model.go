@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"github.com/habbazettt/muraja-server/slot"
+)
 
 type StatusDetailLog string
 
@@ -24,17 +28,28 @@ type DetailLog struct {
 	ID                  uint            `gorm:"primaryKey"`
 	LogHarianID         uint            `gorm:"not null"`
 	WaktuMurojaah       string          `gorm:"not null"`
+	Slot                slot.Slot       `gorm:"type:varchar(50);not null;default:'';index"`
+	MushafLayout        string          `gorm:"type:varchar(50);not null;default:'madinah'"`
 	TargetStartJuz      int             `gorm:"not null"`
 	TargetStartHalaman  int             `gorm:"not null"`
 	TargetEndJuz        int             `gorm:"not null"`
 	TargetEndHalaman    int             `gorm:"not null"`
+	TargetStartPage     int             `gorm:"not null;default:0"`
+	TargetEndPage       int             `gorm:"not null;default:0"`
 	SelesaiEndJuz       int             `gorm:"default:0"`
 	SelesaiEndHalaman   int             `gorm:"default:0"`
+	SelesaiEndPage      int             `gorm:"default:0"`
 	TotalTargetHalaman  int             `gorm:"default:0"`
 	TotalSelesaiHalaman int             `gorm:"default:0"`
+	HalamanTercatat     int             `gorm:"default:0"`
+	RolloverDariID      *uint           `gorm:"index"`
+	RekomendasiID       *uint           `gorm:"index"`
 	Status              StatusDetailLog `gorm:"type:varchar(50);default:'Belum Selesai'"`
 	Catatan             string          `gorm:"type:text"`
 
+	RolloverDari *DetailLog         `gorm:"foreignKey:RolloverDariID;constraint:OnDelete:SET NULL;"`
+	Rekomendasi  *JadwalRekomendasi `gorm:"foreignKey:RekomendasiID;constraint:OnDelete:SET NULL;"`
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// KanalNotifikasi adalah tujuan pengiriman pengingat milik user. Isi Tujuan
+// bergantung pada Jenis: alamat email, URL webhook, atau JSON push subscription.
+type KanalNotifikasi struct {
+	ID     uint   `gorm:"primaryKey" json:"id"`
+	UserID uint   `gorm:"not null;index" json:"user_id"`
+	Jenis  string `gorm:"type:varchar(20);not null" json:"jenis"`
+	Tujuan string `gorm:"type:text;not null" json:"-"`
+	Aktif  bool   `gorm:"not null;default:true" json:"aktif"`
+
+	User *User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;" json:"-"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PengaturanSlot menyimpan status bisu atau tunda pengingat untuk satu slot.
+type PengaturanSlot struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	UserID      uint       `gorm:"not null;uniqueIndex:idx_pengaturan_slot" json:"user_id"`
+	Slot        string     `gorm:"type:varchar(100);not null;uniqueIndex:idx_pengaturan_slot" json:"slot"`
+	Dibisukan   bool       `gorm:"not null;default:false" json:"dibisukan"`
+	TundaSampai *time.Time `json:"tunda_sampai"`
+
+	User *User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;" json:"-"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PengingatTerkirim mencegah pengingat yang sama dikirim dua kali dalam sehari.
+type PengingatTerkirim struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	UserID      uint      `gorm:"not null;uniqueIndex:idx_pengingat_harian" json:"user_id"`
+	Slot        string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_pengingat_harian" json:"slot"`
+	Tanggal     time.Time `gorm:"type:date;not null;uniqueIndex:idx_pengingat_harian" json:"tanggal"`
+	JadwalAt    time.Time `gorm:"not null" json:"jadwal_at"`
+	DikirimAt   time.Time `gorm:"not null" json:"dikirim_at"`
+	JumlahKanal int       `gorm:"not null;default:0" json:"jumlah_kanal"`
+	Error       string    `gorm:"type:text" json:"error"`
+
+	User *User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;" json:"-"`
+}
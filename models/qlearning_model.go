@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+const (
+	SumberQTableFile   = "file"
+	SumberQTableOnline = "online"
+	SumberQTableUpload = "upload"
+)
+
+// QTableVersi adalah satu versi model rekomendasi: Q-table beserta data
+// historis fallback-nya. Hanya satu versi yang aktif; versi lama disimpan agar
+// bisa dikembalikan (rollback).
+type QTableVersi struct {
+	ID           uint    `gorm:"primaryKey" json:"id"`
+	Sumber       string  `gorm:"type:varchar(20);not null" json:"sumber"`
+	IndukID      *uint   `gorm:"index" json:"induk_id"`
+	Alpha        float64 `json:"alpha"`
+	Gamma        float64 `json:"gamma"`
+	JumlahUpdate int     `gorm:"default:0" json:"jumlah_update"`
+	Aktif        bool    `gorm:"not null;default:false;index" json:"aktif"`
+	Catatan      string  `gorm:"type:text" json:"catatan"`
+	// Checksum adalah sidik berkas model untuk versi dari berkas atau unggahan;
+	// kosong untuk versi hasil pembaruan online.
+	Checksum string `gorm:"type:varchar(64);index" json:"checksum"`
+
+	Nilai    []QValue        `gorm:"foreignKey:VersiID;constraint:OnDelete:CASCADE;" json:"-"`
+	Historis []HistorisVersi `gorm:"foreignKey:VersiID;constraint:OnDelete:CASCADE;" json:"-"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type QValue struct {
+	ID      uint    `gorm:"primaryKey" json:"id"`
+	VersiID uint    `gorm:"not null;uniqueIndex:idx_qvalue_versi_state_aksi" json:"versi_id"`
+	State   string  `gorm:"type:varchar(255);not null;uniqueIndex:idx_qvalue_versi_state_aksi" json:"state"`
+	Aksi    string  `gorm:"type:varchar(255);not null;uniqueIndex:idx_qvalue_versi_state_aksi" json:"aksi"`
+	Nilai   float64 `gorm:"not null" json:"nilai"`
+}
+
+// HistorisVersi adalah satu baris data historis (historical_best.json) milik
+// sebuah versi model.
+type HistorisVersi struct {
+	ID                uint    `gorm:"primaryKey" json:"id"`
+	VersiID           uint    `gorm:"not null;uniqueIndex:idx_historis_versi_jadwal" json:"versi_id"`
+	Jadwal            string  `gorm:"type:varchar(255);not null;uniqueIndex:idx_historis_versi_jadwal" json:"jadwal"`
+	TotalPenggunaan   int     `gorm:"not null;default:0" json:"total_penggunaan"`
+	PenggunaanEfektif int     `gorm:"not null;default:0" json:"penggunaan_efektif"`
+	PersentaseEfektif float64 `gorm:"not null;default:0" json:"persentase_efektif"`
+}
+
+// UmpanBalikRekomendasi mencatat reward sebuah rekomendasi yang diterapkan
+// beserta pembaruan Q yang dihasilkannya. Satu rekomendasi hanya diproses sekali.
+type UmpanBalikRekomendasi struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	RekomendasiID  uint      `gorm:"not null;uniqueIndex" json:"rekomendasi_id"`
+	UserID         uint      `gorm:"not null;index" json:"user_id"`
+	VersiID        uint      `gorm:"not null;index" json:"versi_id"`
+	State          string    `gorm:"type:varchar(255);not null" json:"state"`
+	Aksi           string    `gorm:"type:varchar(255);not null" json:"aksi"`
+	TanggalMulai   time.Time `gorm:"type:date;not null" json:"tanggal_mulai"`
+	TanggalSelesai time.Time `gorm:"type:date;not null" json:"tanggal_selesai"`
+	TingkatSelesai float64   `json:"tingkat_selesai"`
+	Efektifitas    int       `json:"efektifitas"`
+	Reward         float64   `json:"reward"`
+	QLama          float64   `json:"q_lama"`
+	QBaru          float64   `json:"q_baru"`
+
+	Rekomendasi *JadwalRekomendasi `gorm:"foreignKey:RekomendasiID;constraint:OnDelete:CASCADE;" json:"-"`
+	Versi       *QTableVersi       `gorm:"foreignKey:VersiID;constraint:OnDelete:CASCADE;" json:"-"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -5,14 +5,66 @@ import "time"
 type JadwalRekomendasi struct {
 	ID                        uint     `gorm:"primaryKey" json:"id"`
 	UserID                    uint     `gorm:"not null" json:"user_id"`
-	State                     string   `gorm:"not null" json:"state"`
+	State                     string   `gorm:"not null;index" json:"state"`
 	RekomendasiJadwal         string   `gorm:"not null" json:"rekomendasi_jadwal"`
 	TipeRekomendasi           string   `gorm:"not null" json:"tipe_rekomendasi"`
 	EstimasiQValue            *float64 `gorm:"null" json:"estimasi_q_value"`
 	PersentaseEfektifHistoris *float64 `gorm:"null" json:"persentase_efektif_historis"`
+	// Kebijakan dan Propensitas mencatat kebijakan yang memilih aksi ini dan
+	// peluang aksi tersebut terpilih, untuk evaluasi kebijakan secara offline.
+	Kebijakan          string   `gorm:"type:varchar(30)" json:"kebijakan"`
+	ParameterKebijakan float64  `gorm:"default:0" json:"parameter_kebijakan"`
+	Propensitas        *float64 `json:"propensitas"`
+	// ModelVersiID adalah versi model yang menghasilkan rekomendasi ini.
+	ModelVersiID *uint `gorm:"index" json:"model_versi_id"`
+	// EksperimenID dan VarianID diisi jika rekomendasi dibuat selama user
+	// mengikuti eksperimen model.
+	EksperimenID *uint `gorm:"index" json:"eksperimen_id"`
+	VarianID     *uint `gorm:"index" json:"varian_id"`
+	// JadwalDipilih diisi jika user memilih salah satu alternatif, bukan
+	// rekomendasi utama.
+	JadwalDipilih *string    `json:"jadwal_dipilih"`
+	DipilihPada   *time.Time `json:"dipilih_pada"`
+	// DiterapkanPada adalah tanggal rekomendasi pertama kali dipakai di log harian.
+	DiterapkanPada *time.Time `gorm:"type:date;index" json:"diterapkan_pada"`
+	// DiprosesPada diisi saat PerbaruiQTable selesai mengevaluasi rekomendasi
+	// ini, termasuk yang dilewati tanpa umpan balik, agar tidak dipilih lagi.
+	DiprosesPada *time.Time `json:"diproses_pada"`
 
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 
-	User *User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;" json:"user"`
+	User     *User                 `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;" json:"user"`
+	Kandidat []KandidatRekomendasi `gorm:"foreignKey:RekomendasiID;constraint:OnDelete:CASCADE;" json:"kandidat,omitempty"`
+	// StateSumber diisi untuk rekomendasi bertipe Mirip: state yang nilai
+	// Q-nya digabung karena state user tidak ada di model.
+	StateSumber []StateSumberRekomendasi `gorm:"foreignKey:RekomendasiID;constraint:OnDelete:CASCADE;" json:"state_sumber,omitempty"`
+}
+
+// JadwalDiterapkan mengembalikan jadwal yang benar-benar dipakai user: pilihan
+// alternatifnya jika ada, selain itu rekomendasi utama.
+func (r JadwalRekomendasi) JadwalDiterapkan() string {
+	if r.JadwalDipilih != nil {
+		return *r.JadwalDipilih
+	}
+	return r.RekomendasiJadwal
+}
+
+// KandidatRekomendasi adalah alternatif yang ditawarkan bersama sebuah
+// rekomendasi, urut menurut nilai Q.
+type KandidatRekomendasi struct {
+	ID                        uint     `gorm:"primaryKey" json:"id"`
+	RekomendasiID             uint     `gorm:"not null;index" json:"rekomendasi_id"`
+	Peringkat                 int      `gorm:"not null" json:"peringkat"`
+	Jadwal                    string   `gorm:"not null" json:"jadwal"`
+	EstimasiQValue            *float64 `json:"estimasi_q_value"`
+	PersentaseEfektifHistoris *float64 `json:"persentase_efektif_historis"`
+	Alasan                    string   `gorm:"type:text" json:"alasan"`
+}
+
+type StateSumberRekomendasi struct {
+	ID            uint    `gorm:"primaryKey" json:"id"`
+	RekomendasiID uint    `gorm:"not null;index" json:"rekomendasi_id"`
+	State         string  `gorm:"not null" json:"state"`
+	Kemiripan     float64 `gorm:"not null" json:"kemiripan"`
 }
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// RolloverLog mencatat sisa target DetailLog yang dipindahkan ke hari berikutnya.
+type RolloverLog struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	UserID          uint      `gorm:"not null;index" json:"user_id"`
+	DetailLogAsalID uint      `gorm:"not null;uniqueIndex" json:"detail_log_asal_id"`
+	DetailLogBaruID *uint     `gorm:"index" json:"detail_log_baru_id"`
+	TanggalAsal     time.Time `gorm:"type:date;not null" json:"tanggal_asal"`
+	TanggalTujuan   time.Time `gorm:"type:date;not null" json:"tanggal_tujuan"`
+	WaktuMurojaah   string    `gorm:"not null" json:"waktu_murojaah"`
+	MushafLayout    string    `gorm:"type:varchar(50);not null" json:"mushaf_layout"`
+	StartPage       int       `gorm:"not null" json:"start_page"`
+	EndPage         int       `gorm:"not null" json:"end_page"`
+	TotalHalaman    int       `gorm:"not null" json:"total_halaman"`
+
+	User *User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;" json:"-"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Session menyimpan satu refresh token. Setiap rotasi membuat baris baru dengan
+// FamilyID yang sama, sehingga seluruh rantai login dapat dicabut sekaligus.
+type Session struct {
+	ID               uint       `gorm:"primaryKey" json:"id"`
+	UserID           uint       `gorm:"not null;index" json:"user_id"`
+	FamilyID         string     `gorm:"type:varchar(64);not null;index" json:"family_id"`
+	RefreshTokenHash string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	AccessTokenJTI   string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	UserAgent        string     `gorm:"type:varchar(255)" json:"user_agent"`
+	IPAddress        string     `gorm:"type:varchar(64)" json:"ip_address"`
+	ExpiresAt        time.Time  `gorm:"not null" json:"expires_at"`
+	RotatedAt        *time.Time `json:"rotated_at"`
+	RevokedAt        *time.Time `json:"revoked_at"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	User *User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;" json:"user,omitempty"`
+}
@@ -2,6 +2,16 @@ package models
 
 import "time"
 
+const (
+	RoleUser   = "user"
+	RoleUstadz = "ustadz"
+	RoleAdmin  = "admin"
+)
+
+func IsValidRole(role string) bool {
+	return role == RoleUser || role == RoleUstadz || role == RoleAdmin
+}
+
 type User struct {
 	ID                   uint   `gorm:"primaryKey" json:"id"`
 	Nama                 string `gorm:"type:varchar(255);not null" json:"nama"`
@@ -9,7 +19,19 @@ type User struct {
 	Password             string `gorm:"not null" json:"-"`
 	IsDataMurojaahFilled bool   `gorm:"default:false" json:"is_data_murojaah_filled"`
 	UserType             string `gorm:"type:varchar(255);not null" json:"user_type"`
+	TokenVersion         int    `gorm:"not null;default:0" json:"-"`
+	UstadzID             *uint  `gorm:"index" json:"ustadz_id"`
+	MushafLayout         string `gorm:"type:varchar(50);not null;default:'madinah'" json:"mushaf_layout"`
+	RolloverOtomatis     bool   `gorm:"not null;default:true" json:"rollover_otomatis"`
+	ZonaWaktu            string `gorm:"type:varchar(64);not null;default:'Asia/Jakarta'" json:"zona_waktu"`
+
+	Latitude     *float64 `json:"latitude"`
+	Longitude    *float64 `json:"longitude"`
+	Elevasi      float64  `gorm:"not null;default:0" json:"elevasi"`
+	MetodeSholat string   `gorm:"type:varchar(20);not null;default:'kemenag'" json:"metode_sholat"`
+	Madhab       string   `gorm:"type:varchar(20);not null;default:'syafii'" json:"madhab"`
 
+	Ustadz             *User               `gorm:"foreignKey:UstadzID;constraint:OnDelete:SET NULL;" json:"-"`
 	JadwalPersonal     *JadwalPersonal     `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;" json:"jadwal_personal,omitempty"`
 	LogHarians         []LogHarian         `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;" json:"log_harians,omitempty"`
 	JadwalRekomendasis []JadwalRekomendasi `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;" json:"jadwal_rekomendasi,omitempty"`
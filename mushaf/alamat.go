@@ -0,0 +1,60 @@
+package mushaf
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Alamat adalah satu titik di mushaf yang dapat ditulis dengan tiga cara:
+// juz + halaman di dalam juz, halaman mutlak, atau "surah:ayat". Tepat satu
+// cara harus diisi.
+type Alamat struct {
+	Juz          int
+	HalamanDiJuz int
+	Halaman      int
+	Ayat         string
+}
+
+var ErrAlamatKosong = errors.New("alamat mushaf wajib diisi (juz+halaman, halaman, atau surah:ayat)")
+
+// Resolve mengubah alamat menjadi nomor halaman mutlak pada layout.
+func (l *Layout) Resolve(a Alamat) (int, error) {
+	filled := 0
+	if a.Juz != 0 || a.HalamanDiJuz != 0 {
+		filled++
+	}
+	if a.Halaman != 0 {
+		filled++
+	}
+	if a.Ayat != "" {
+		filled++
+	}
+
+	switch {
+	case filled == 0:
+		return 0, ErrAlamatKosong
+	case filled > 1:
+		return 0, errors.New("gunakan salah satu saja: juz+halaman, halaman, atau surah:ayat")
+	case a.Ayat != "":
+		ayat, err := ParseAyat(a.Ayat)
+		if err != nil {
+			return 0, err
+		}
+		return l.HalamanOfAyat(ayat), nil
+	case a.Halaman != 0:
+		if err := l.validHalaman(a.Halaman); err != nil {
+			return 0, err
+		}
+		return a.Halaman, nil
+	default:
+		return l.HalamanFromJuz(a.Juz, a.HalamanDiJuz)
+	}
+}
+
+// JumlahHalaman menghitung banyaknya halaman dari awal sampai akhir (inklusif).
+func JumlahHalaman(awal, akhir int) (int, error) {
+	if akhir < awal {
+		return 0, fmt.Errorf("target/progres akhir tidak boleh lebih kecil dari awal")
+	}
+	return akhir - awal + 1, nil
+}
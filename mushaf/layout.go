@@ -0,0 +1,194 @@
+package mushaf
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+)
+
+//go:embed data/*.json
+var layoutFiles embed.FS
+
+const DefaultLayout = "madinah"
+
+// Layout memetakan ayat ke nomor halaman untuk satu cetakan mushaf.
+//
+// Layout disusun dari tabel ayat pertama di setiap halaman, sehingga rentang
+// ayat per halaman tepat sesuai cetakannya tanpa perkiraan.
+type Layout struct {
+	Kode         string   `json:"kode"`
+	Nama         string   `json:"nama"`
+	TotalHalaman int      `json:"total_halaman"`
+	Catatan      string   `json:"catatan"`
+	AwalHalaman  []string `json:"awal_halaman"`
+
+	// awal berisi indeks mutlak ayat pertama setiap halaman; awal[0] untuk halaman 1.
+	awal        []int
+	juzStartHal [JumlahJuz + 1]int
+}
+
+var layouts = map[string]*Layout{}
+
+func init() {
+	entries, err := layoutFiles.ReadDir("data")
+	if err != nil {
+		panic(err)
+	}
+
+	for _, entry := range entries {
+		raw, err := layoutFiles.ReadFile(path.Join("data", entry.Name()))
+		if err != nil {
+			panic(err)
+		}
+
+		layout, err := ParseLayout(raw)
+		if err != nil {
+			panic(fmt.Sprintf("layout mushaf %s tidak valid: %v", entry.Name(), err))
+		}
+		layouts[layout.Kode] = layout
+	}
+
+	if _, ok := layouts[DefaultLayout]; !ok {
+		panic("layout mushaf default tidak ditemukan")
+	}
+}
+
+// ParseLayout membaca dan memvalidasi definisi layout dalam format JSON.
+func ParseLayout(raw []byte) (*Layout, error) {
+	var layout Layout
+	if err := json.Unmarshal(raw, &layout); err != nil {
+		return nil, err
+	}
+	if layout.Kode == "" || layout.TotalHalaman <= 0 {
+		return nil, fmt.Errorf("kode dan total_halaman wajib diisi")
+	}
+
+	if len(layout.AwalHalaman) != layout.TotalHalaman {
+		return nil, fmt.Errorf("awal_halaman harus berisi %d halaman, ada %d", layout.TotalHalaman, len(layout.AwalHalaman))
+	}
+
+	layout.awal = make([]int, len(layout.AwalHalaman))
+	for i, teks := range layout.AwalHalaman {
+		ayat, err := ParseAyat(teks)
+		if err != nil {
+			return nil, fmt.Errorf("awal halaman %d: %w", i+1, err)
+		}
+		layout.awal[i] = ayat.Index()
+		if i == 0 && layout.awal[i] != 1 {
+			return nil, fmt.Errorf("halaman 1 harus diawali ayat 1:1")
+		}
+		if i > 0 && layout.awal[i] <= layout.awal[i-1] {
+			return nil, fmt.Errorf("awal halaman %d tidak berurutan", i+1)
+		}
+	}
+
+	for juz := 1; juz <= JumlahJuz; juz++ {
+		layout.juzStartHal[juz] = layout.HalamanOfAyat(AwalJuz(juz))
+	}
+
+	return &layout, nil
+}
+
+// Get mengembalikan layout berdasarkan kode. Kode kosong berarti layout default.
+func Get(kode string) (*Layout, error) {
+	if kode == "" {
+		kode = DefaultLayout
+	}
+	layout, ok := layouts[kode]
+	if !ok {
+		return nil, fmt.Errorf("layout mushaf %q tidak dikenal", kode)
+	}
+	return layout, nil
+}
+
+// Default mengembalikan layout Mushaf Madinah.
+func Default() *Layout {
+	return layouts[DefaultLayout]
+}
+
+// All mengembalikan semua layout yang tersedia, diurutkan berdasarkan kode.
+func All() []*Layout {
+	all := make([]*Layout, 0, len(layouts))
+	for _, layout := range layouts {
+		all = append(all, layout)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Kode < all[j].Kode })
+	return all
+}
+
+// IsValid memeriksa apakah kode layout dikenal.
+func IsValid(kode string) bool {
+	_, ok := layouts[kode]
+	return ok
+}
+
+// HalamanOfAyat mengembalikan nomor halaman tempat ayat berada.
+func (l *Layout) HalamanOfAyat(a Ayat) int {
+	return l.halamanOfIndex(a.Index())
+}
+
+func (l *Layout) halamanOfIndex(index int) int {
+	halaman := sort.Search(len(l.awal), func(i int) bool { return l.awal[i] > index })
+	return max(halaman, 1)
+}
+
+// RentangAyatHalaman mengembalikan ayat pertama dan terakhir yang tercetak di halaman.
+func (l *Layout) RentangAyatHalaman(halaman int) (Ayat, Ayat, error) {
+	if err := l.validHalaman(halaman); err != nil {
+		return Ayat{}, Ayat{}, err
+	}
+
+	last := JumlahAyat
+	if halaman < l.TotalHalaman {
+		last = l.awal[halaman] - 1
+	}
+	return AyatFromIndex(l.awal[halaman-1]), AyatFromIndex(last), nil
+}
+
+// JuzOfHalaman mengembalikan juz tempat halaman dimulai.
+func (l *Layout) JuzOfHalaman(halaman int) int {
+	juz := 1
+	for juz < JumlahJuz && l.juzStartHal[juz+1] <= halaman {
+		juz++
+	}
+	return juz
+}
+
+// AwalHalamanJuz mengembalikan halaman pertama dari juz.
+func (l *Layout) AwalHalamanJuz(juz int) int {
+	return l.juzStartHal[juz]
+}
+
+// JumlahHalamanJuz mengembalikan banyaknya halaman di dalam juz.
+func (l *Layout) JumlahHalamanJuz(juz int) int {
+	if juz == JumlahJuz {
+		return l.TotalHalaman - l.juzStartHal[juz] + 1
+	}
+	return l.juzStartHal[juz+1] - l.juzStartHal[juz]
+}
+
+// HalamanFromJuz mengubah alamat "juz ke-j halaman ke-h" menjadi nomor halaman mutlak.
+func (l *Layout) HalamanFromJuz(juz, halamanDiJuz int) (int, error) {
+	if juz < 1 || juz > JumlahJuz {
+		return 0, fmt.Errorf("juz %d tidak valid, harus 1-%d", juz, JumlahJuz)
+	}
+	if max := l.JumlahHalamanJuz(juz); halamanDiJuz < 1 || halamanDiJuz > max {
+		return 0, fmt.Errorf("halaman %d tidak valid untuk juz %d, harus 1-%d", halamanDiJuz, juz, max)
+	}
+	return l.juzStartHal[juz] + halamanDiJuz - 1, nil
+}
+
+// JuzHalamanOf mengubah nomor halaman mutlak menjadi pasangan juz dan halaman di dalam juz.
+func (l *Layout) JuzHalamanOf(halaman int) (int, int) {
+	juz := l.JuzOfHalaman(halaman)
+	return juz, halaman - l.juzStartHal[juz] + 1
+}
+
+func (l *Layout) validHalaman(halaman int) error {
+	if halaman < 1 || halaman > l.TotalHalaman {
+		return fmt.Errorf("halaman %d tidak valid, harus 1-%d", halaman, l.TotalHalaman)
+	}
+	return nil
+}
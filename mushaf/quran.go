@@ -0,0 +1,104 @@
+// Package mushaf menyediakan metadata Al-Qur'an (surah, ayat, juz) dan
+// pemetaan ke nomor halaman untuk layout cetakan mushaf tertentu.
+package mushaf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Ayat menunjuk satu ayat dengan nomor surah (1-114) dan nomor ayat di dalamnya.
+type Ayat struct {
+	Surah int `json:"surah"`
+	Ayat  int `json:"ayat"`
+}
+
+func (a Ayat) String() string {
+	return fmt.Sprintf("%d:%d", a.Surah, a.Ayat)
+}
+
+func (a Ayat) Valid() bool {
+	return a.Surah >= 1 && a.Surah <= JumlahSurah && a.Ayat >= 1 && a.Ayat <= jumlahAyatSurah[a.Surah-1]
+}
+
+// ParseAyat membaca format "surah:ayat", misalnya "2:255".
+func ParseAyat(s string) (Ayat, error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) != 2 {
+		return Ayat{}, fmt.Errorf("format ayat %q tidak valid, gunakan surah:ayat", s)
+	}
+
+	surah, err1 := strconv.Atoi(strings.TrimSpace(parts[0]))
+	ayat, err2 := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err1 != nil || err2 != nil {
+		return Ayat{}, fmt.Errorf("format ayat %q tidak valid, gunakan surah:ayat", s)
+	}
+
+	a := Ayat{Surah: surah, Ayat: ayat}
+	if !a.Valid() {
+		return Ayat{}, fmt.Errorf("ayat %s tidak ada di dalam Al-Qur'an", a)
+	}
+	return a, nil
+}
+
+// Index mengembalikan urutan mutlak ayat di dalam mushaf (1-6236).
+func (a Ayat) Index() int {
+	return surahOffset[a.Surah-1] + a.Ayat
+}
+
+// AyatFromIndex adalah kebalikan dari Ayat.Index.
+func AyatFromIndex(index int) Ayat {
+	if index < 1 {
+		index = 1
+	}
+	if index > JumlahAyat {
+		index = JumlahAyat
+	}
+
+	surah := 1
+	for surah < JumlahSurah && surahOffset[surah] < index {
+		surah++
+	}
+	return Ayat{Surah: surah, Ayat: index - surahOffset[surah-1]}
+}
+
+func JumlahAyatSurah(surah int) int {
+	if surah < 1 || surah > JumlahSurah {
+		return 0
+	}
+	return jumlahAyatSurah[surah-1]
+}
+
+func NamaSurah(surah int) string {
+	if surah < 1 || surah > JumlahSurah {
+		return ""
+	}
+	return namaSurah[surah-1]
+}
+
+// AwalJuz mengembalikan ayat pertama dari juz (1-30).
+func AwalJuz(juz int) Ayat {
+	return awalJuz[juz-1]
+}
+
+// JuzOfAyat mengembalikan nomor juz tempat ayat berada.
+func JuzOfAyat(a Ayat) int {
+	index := a.Index()
+	juz := 1
+	for juz < JumlahJuz && awalJuz[juz].Index() <= index {
+		juz++
+	}
+	return juz
+}
+
+// surahOffset[i] adalah jumlah ayat sebelum surah ke-(i+1).
+var surahOffset = func() [JumlahSurah]int {
+	var offsets [JumlahSurah]int
+	total := 0
+	for i, n := range jumlahAyatSurah {
+		offsets[i] = total
+		total += n
+	}
+	return offsets
+}()
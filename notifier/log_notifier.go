@@ -0,0 +1,45 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LogNotifier tidak mengirim pesan ke mana pun, hanya mencatatnya ke logger
+// atau ke sebuah file. Dipakai untuk pengembangan lokal tanpa mail server.
+type LogNotifier struct {
+	FilePath string
+
+	mu sync.Mutex
+}
+
+func NewLogNotifier(filePath string) *LogNotifier {
+	return &LogNotifier{FilePath: filePath}
+}
+
+func (n *LogNotifier) Send(ctx context.Context, msg Message) error {
+	if n.FilePath == "" {
+		logrus.WithFields(logrus.Fields{
+			"to":      msg.To,
+			"subject": msg.Subject,
+		}).Info(msg.Body)
+		return nil
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	f, err := os.OpenFile(n.FilePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("gagal membuka file notifikasi: %w", err)
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "[%s] to=%s subject=%q\n%s\n\n", time.Now().Format(time.RFC3339), msg.To, msg.Subject, msg.Body)
+	return err
+}
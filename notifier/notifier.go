@@ -0,0 +1,77 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+	// Data adalah payload terstruktur opsional untuk kanal yang mendukungnya
+	// (webhook dan web push).
+	Data any
+}
+
+type Notifier interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Jenis kanal notifikasi yang dikenal.
+const (
+	KanalLog     = "log"
+	KanalEmail   = "email"
+	KanalWebhook = "webhook"
+	KanalWebPush = "webpush"
+)
+
+// ErrTidakDikonfigurasi dikembalikan jika tidak ada kanal yang boleh dipakai
+// untuk mengirim pesan.
+var ErrTidakDikonfigurasi = errors.New("notifier belum dikonfigurasi: atur SMTP_HOST")
+
+// NewFromEnv mengembalikan notifier email jika SMTP dikonfigurasi. Tanpa SMTP,
+// notifier log hanya dipakai jika APP_ENV=development karena pesan seperti
+// token reset password akan tertulis apa adanya di log; di luar itu pesan
+// ditolak dengan ErrTidakDikonfigurasi.
+func NewFromEnv() Notifier {
+	if email := NewEmailNotifierFromEnv(); email != nil {
+		return email
+	}
+	if os.Getenv("APP_ENV") == "development" {
+		return NewLogNotifier(os.Getenv("NOTIFIER_LOG_FILE"))
+	}
+	logrus.Warn("SMTP_HOST belum diatur dan APP_ENV bukan development, pesan seperti reset password tidak akan dikirim")
+	return notifierNonaktif{}
+}
+
+type notifierNonaktif struct{}
+
+func (notifierNonaktif) Send(context.Context, Message) error {
+	return ErrTidakDikonfigurasi
+}
+
+// KanalFromEnv membangun semua kanal yang tersedia berdasarkan konfigurasi
+// environment. Kanal log selalu tersedia.
+func KanalFromEnv() map[string]Notifier {
+	kanal := map[string]Notifier{
+		KanalLog:     NewLogNotifier(os.Getenv("NOTIFIER_LOG_FILE")),
+		KanalWebhook: NewWebhookNotifier(os.Getenv("WEBHOOK_SECRET")),
+	}
+
+	if email := NewEmailNotifierFromEnv(); email != nil {
+		kanal[KanalEmail] = email
+	}
+
+	push, err := NewWebPushNotifierFromEnv()
+	if err != nil {
+		logrus.WithError(err).Warn("Konfigurasi VAPID tidak valid, kanal web push dinonaktifkan")
+	} else if push != nil {
+		kanal[KanalWebPush] = push
+	}
+
+	return kanal
+}
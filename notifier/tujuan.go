@@ -0,0 +1,101 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrAlamatTerlarang dikembalikan jika URL tujuan mengarah ke jaringan
+// internal (loopback, privat, link-local, dan sejenisnya).
+var ErrAlamatTerlarang = errors.New("alamat tujuan tidak boleh mengarah ke jaringan internal")
+
+// rentangTerlarang melengkapi pengecekan netip untuk rentang yang tidak
+// boleh dituju dari server: shared address space (CGNAT) dan benchmarking.
+var rentangTerlarang = []netip.Prefix{
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+func alamatTerlarang(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsValid() || ip.IsUnspecified() || ip.IsLoopback() || ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() {
+		return true
+	}
+	for _, p := range rentangTerlarang {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidasiURLPublik memastikan URL memakai https dan host-nya hanya
+// me-resolve ke alamat publik. Dipakai saat kanal didaftarkan; pengecekan
+// yang sama diulang saat koneksi dibuka agar DNS rebinding tidak lolos.
+func ValidasiURLPublik(ctx context.Context, raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" || u.User != nil {
+		return nil, errors.New("URL harus memakai https dan menyertakan host")
+	}
+
+	host := u.Hostname()
+	if ip, err := netip.ParseAddr(host); err == nil {
+		if alamatTerlarang(ip) {
+			return nil, ErrAlamatTerlarang
+		}
+		return u, nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil || len(addrs) == 0 {
+		return nil, fmt.Errorf("host %s tidak dapat di-resolve", host)
+	}
+	for _, ip := range addrs {
+		if alamatTerlarang(ip) {
+			return nil, ErrAlamatTerlarang
+		}
+	}
+	return u, nil
+}
+
+// kontrolDial menolak koneksi ke alamat terlarang setelah DNS di-resolve,
+// tepat sebelum socket tersambung.
+func kontrolDial(network, address string, _ syscall.RawConn) error {
+	ap, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if alamatTerlarang(ap.Addr()) {
+		return ErrAlamatTerlarang
+	}
+	return nil
+}
+
+// newClientPublik membuat HTTP client untuk URL milik user: hanya tersambung
+// ke alamat publik, tidak memakai proxy environment, dan tidak mengikuti
+// redirect.
+func newClientPublik(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: kontrolDial}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,75 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// WebhookNotifier mengirim pesan sebagai JSON ke URL pada field To. Jika Secret
+// diisi, body ditandatangani HMAC-SHA256 di header X-Muraja-Signature. URL
+// berasal dari user, jadi hanya https ke alamat publik yang dilayani.
+type WebhookNotifier struct {
+	Secret string
+	Client *http.Client
+}
+
+func NewWebhookNotifier(secret string) *WebhookNotifier {
+	return &WebhookNotifier{
+		Secret: secret,
+		Client: newClientPublik(10 * time.Second),
+	}
+}
+
+type webhookPayload struct {
+	Subject string    `json:"subject"`
+	Body    string    `json:"body"`
+	Data    any       `json:"data,omitempty"`
+	SentAt  time.Time `json:"sent_at"`
+}
+
+func (n *WebhookNotifier) Send(ctx context.Context, msg Message) error {
+	target, err := url.Parse(msg.To)
+	if err != nil || target.Scheme != "https" || target.Host == "" {
+		return fmt.Errorf("URL webhook tidak valid")
+	}
+
+	payload, err := json.Marshal(webhookPayload{
+		Subject: msg.Subject,
+		Body:    msg.Body,
+		Data:    msg.Data,
+		SentAt:  time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.String(), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(n.Secret))
+		mac.Write(payload)
+		req.Header.Set("X-Muraja-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("gagal mengirim webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook membalas status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// PushSubscription adalah objek PushSubscription dari browser (hasil
+// pushManager.subscribe), disimpan apa adanya sebagai JSON di field To.
+type PushSubscription struct {
+	Endpoint string `json:"endpoint"`
+	Keys     struct {
+		P256dh string `json:"p256dh"`
+		Auth   string `json:"auth"`
+	} `json:"keys"`
+}
+
+// WebPushNotifier mengirim notifikasi Web Push terenkripsi (RFC 8291) dengan
+// autentikasi VAPID (RFC 8292).
+type WebPushNotifier struct {
+	PublicKey  string
+	privateKey *ecdsa.PrivateKey
+	Subject    string
+	Client     *http.Client
+}
+
+var b64 = base64.RawURLEncoding
+
+// ErrLanggananBerakhir dikembalikan jika push service membalas 404 atau 410:
+// subscription sudah kedaluwarsa atau dicabut dan kanal perlu dinonaktifkan.
+var ErrLanggananBerakhir = errors.New("push subscription sudah tidak berlaku")
+
+// NewWebPushNotifier membuat notifier dari pasangan kunci VAPID dalam format
+// base64url tanpa padding: kunci publik 65 byte dan kunci privat 32 byte.
+func NewWebPushNotifier(publicKey, privateKey, subject string) (*WebPushNotifier, error) {
+	d, err := b64.DecodeString(privateKey)
+	if err != nil || len(d) != 32 {
+		return nil, errors.New("VAPID private key tidak valid")
+	}
+	pub, err := b64.DecodeString(publicKey)
+	if err != nil || len(pub) != 65 {
+		return nil, errors.New("VAPID public key tidak valid")
+	}
+
+	curve := elliptic.P256()
+	priv := &ecdsa.PrivateKey{D: new(big.Int).SetBytes(d)}
+	priv.Curve = curve
+	priv.X, priv.Y = curve.ScalarBaseMult(d)
+
+	if !bytes.Equal(pub[1:33], priv.X.FillBytes(make([]byte, 32))) {
+		return nil, errors.New("VAPID public key tidak cocok dengan private key")
+	}
+
+	return &WebPushNotifier{
+		PublicKey:  publicKey,
+		privateKey: priv,
+		Subject:    subject,
+		Client:     newClientPublik(10 * time.Second),
+	}, nil
+}
+
+// NewWebPushNotifierFromEnv membaca VAPID_PUBLIC_KEY, VAPID_PRIVATE_KEY dan
+// VAPID_SUBJECT. Mengembalikan nil jika kunci belum dikonfigurasi.
+func NewWebPushNotifierFromEnv() (*WebPushNotifier, error) {
+	publicKey, privateKey := os.Getenv("VAPID_PUBLIC_KEY"), os.Getenv("VAPID_PRIVATE_KEY")
+	if publicKey == "" || privateKey == "" {
+		return nil, nil
+	}
+	return NewWebPushNotifier(publicKey, privateKey, os.Getenv("VAPID_SUBJECT"))
+}
+
+// GenerateVAPIDKeys membuat pasangan kunci VAPID baru (base64url).
+func GenerateVAPIDKeys() (publicKey, privateKey string, err error) {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	return b64.EncodeToString(key.PublicKey().Bytes()), b64.EncodeToString(key.Bytes()), nil
+}
+
+func (n *WebPushNotifier) Send(ctx context.Context, msg Message) error {
+	var sub PushSubscription
+	if err := json.Unmarshal([]byte(msg.To), &sub); err != nil || sub.Endpoint == "" {
+		return errors.New("push subscription tidak valid")
+	}
+	endpoint, err := url.Parse(sub.Endpoint)
+	if err != nil || endpoint.Scheme != "https" {
+		return errors.New("endpoint push subscription tidak valid")
+	}
+
+	payload, err := json.Marshal(map[string]any{
+		"title": msg.Subject,
+		"body":  msg.Body,
+		"data":  msg.Data,
+	})
+	if err != nil {
+		return err
+	}
+
+	body, err := encryptPushPayload(sub, payload)
+	if err != nil {
+		return err
+	}
+
+	token, err := n.vapidToken(endpoint.Scheme + "://" + endpoint.Host)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("TTL", "86400")
+	req.Header.Set("Urgency", "normal")
+	req.Header.Set("Authorization", fmt.Sprintf("vapid t=%s, k=%s", token, n.PublicKey))
+
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("gagal mengirim web push: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		return fmt.Errorf("%w: push service membalas status %d", ErrLanggananBerakhir, resp.StatusCode)
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("push service membalas status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (n *WebPushNotifier) vapidToken(audience string) (string, error) {
+	subject := n.Subject
+	if subject == "" {
+		subject = "mailto:admin@localhost"
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"aud": audience,
+		"exp": time.Now().Add(12 * time.Hour).Unix(),
+		"sub": subject,
+	})
+	return token.SignedString(n.privateKey)
+}
+
+// encryptPushPayload mengenkripsi payload dengan skema aes128gcm (RFC 8291)
+// dalam satu record.
+func encryptPushPayload(sub PushSubscription, payload []byte) ([]byte, error) {
+	uaPublicBytes, err := b64.DecodeString(sub.Keys.P256dh)
+	if err != nil {
+		return nil, errors.New("kunci p256dh tidak valid")
+	}
+	authSecret, err := b64.DecodeString(sub.Keys.Auth)
+	if err != nil || len(authSecret) == 0 {
+		return nil, errors.New("kunci auth tidak valid")
+	}
+
+	asPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return sealAes128gcm(uaPublicBytes, authSecret, asPrivate, salt, payload)
+}
+
+// sealAes128gcm adalah bagian deterministik dari encryptPushPayload: kunci
+// sementara server dan salt diberikan oleh pemanggil.
+func sealAes128gcm(uaPublicBytes, authSecret []byte, asPrivate *ecdh.PrivateKey, salt, payload []byte) ([]byte, error) {
+	uaPublic, err := ecdh.P256().NewPublicKey(uaPublicBytes)
+	if err != nil {
+		return nil, errors.New("kunci p256dh tidak valid")
+	}
+	asPublicBytes := asPrivate.PublicKey().Bytes()
+
+	ecdhSecret, err := asPrivate.ECDH(uaPublic)
+	if err != nil {
+		return nil, err
+	}
+
+	keyInfo := append([]byte("WebPush: info\x00"), uaPublicBytes...)
+	keyInfo = append(keyInfo, asPublicBytes...)
+	ikm, err := hkdf.Key(sha256.New, ecdhSecret, authSecret, string(keyInfo), 32)
+	if err != nil {
+		return nil, err
+	}
+
+	cek, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// 0x02 menandai record terakhir.
+	plaintext := append(append([]byte{}, payload...), 0x02)
+	ciphertext := gcm.Seal(nil, nonce, plaintext, nil)
+
+	header := make([]byte, 0, 16+4+1+len(asPublicBytes))
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, 4096)
+	header = append(header, byte(len(asPublicBytes)))
+	header = append(header, asPublicBytes...)
+
+	return append(header, ciphertext...), nil
+}
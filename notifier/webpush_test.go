@@ -0,0 +1,79 @@
+package notifier
+
+import (
+	"crypto/ecdh"
+	"testing"
+)
+
+func mustB64(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := b64.DecodeString(s)
+	if err != nil {
+		t.Fatalf("base64 %q tidak valid: %v", s, err)
+	}
+	return b
+}
+
+// Vektor uji dari RFC 8291 Appendix A.
+func TestSealAes128gcmRFC8291(t *testing.T) {
+	tests := []struct {
+		name       string
+		plaintext  string
+		asPrivate  string
+		uaPublic   string
+		authSecret string
+		salt       string
+		want       string
+	}{
+		{
+			name:       "RFC 8291 Appendix A",
+			plaintext:  "When I grow up, I want to be a watermelon",
+			asPrivate:  "yfWPiYE-n46HLnH0KqZOF1fJJU3MYrct3AELtAQ-oRw",
+			uaPublic:   "BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4",
+			authSecret: "BTBZMqHH6r4Tts7J_aSIgg",
+			salt:       "DGv6ra1nlYgDCS1FRnbzlw",
+			want: "DGv6ra1nlYgDCS1FRnbzlwAAEABBBP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK6PBru3jl7A" +
+				"_yl95bQpu6cVPTpK4Mqgkf1CXztLVBSt2Ks3oZwbuwXPXLWyouBWLVWGNWQexSgSxsj_Qulcy4a-fN",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			asPrivate, err := ecdh.P256().NewPrivateKey(mustB64(t, tt.asPrivate))
+			if err != nil {
+				t.Fatalf("kunci privat server tidak valid: %v", err)
+			}
+
+			got, err := sealAes128gcm(mustB64(t, tt.uaPublic), mustB64(t, tt.authSecret), asPrivate, mustB64(t, tt.salt), []byte(tt.plaintext))
+			if err != nil {
+				t.Fatalf("sealAes128gcm error: %v", err)
+			}
+			if b64.EncodeToString(got) != tt.want {
+				t.Errorf("hasil enkripsi\n got  %s\n want %s", b64.EncodeToString(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestEncryptPushPayloadKunciTidakValid(t *testing.T) {
+	tests := []struct {
+		name   string
+		p256dh string
+		auth   string
+	}{
+		{"p256dh bukan base64url", "bukan base64!", "BTBZMqHH6r4Tts7J_aSIgg"},
+		{"p256dh bukan titik kurva", "BCVxsr7N", "BTBZMqHH6r4Tts7J_aSIgg"},
+		{"auth kosong", "BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sub PushSubscription
+			sub.Endpoint = "https://push.example.com/x"
+			sub.Keys.P256dh, sub.Keys.Auth = tt.p256dh, tt.auth
+			if _, err := encryptPushPayload(sub, []byte("halo")); err == nil {
+				t.Error("diharapkan error untuk kunci tidak valid")
+			}
+		})
+	}
+}
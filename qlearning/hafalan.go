@@ -0,0 +1,56 @@
+package qlearning
+
+import (
+	"fmt"
+	"sort"
+)
+
+// RentangHafalan adalah satu kategori hafalan pada state, dalam juz.
+type RentangHafalan struct {
+	Bawah int
+	Atas  int
+}
+
+func (r RentangHafalan) String() string {
+	return fmt.Sprintf("%d-%d Juz", r.Bawah, r.Atas)
+}
+
+// KategoriHafalan adalah pembagian total hafalan bawaan, dipakai saat melatih
+// model baru atau jika kategori di model tidak terbaca.
+var KategoriHafalan = []RentangHafalan{
+	{Bawah: 1, Atas: 10},
+	{Bawah: 11, Atas: 20},
+	{Bawah: 21, Atas: 30},
+}
+
+// RentangHafalan mengembalikan kategori hafalan yang dipakai state di tabel,
+// terurut dari juz terkecil, sehingga pembagiannya selalu mengikuti model.
+func (t Tabel) RentangHafalan() []RentangHafalan {
+	ada := make(map[RentangHafalan]bool)
+	for state := range t {
+		_, kategori := UraiState(state)
+		if bawah, atas, ok := rentangJuz(kategori); ok {
+			ada[RentangHafalan{Bawah: bawah, Atas: atas}] = true
+		}
+	}
+	if len(ada) == 0 {
+		return KategoriHafalan
+	}
+
+	rentang := make([]RentangHafalan, 0, len(ada))
+	for r := range ada {
+		rentang = append(rentang, r)
+	}
+	sort.Slice(rentang, func(i, j int) bool { return rentang[i].Bawah < rentang[j].Bawah })
+	return rentang
+}
+
+// KategoriDariJuz mengembalikan kategori hafalan untuk total hafalan dalam juz.
+func KategoriDariJuz(rentang []RentangHafalan, totalJuz int) (string, bool) {
+	for _, r := range rentang {
+		if totalJuz >= r.Bawah && totalJuz <= r.Atas {
+			return r.String(), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,194 @@
+package qlearning
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"os"
+	"sort"
+	"strconv"
+)
+
+const (
+	KebijakanGreedy        = "greedy"
+	KebijakanEpsilonGreedy = "epsilon-greedy"
+	KebijakanSoftmax       = "softmax"
+	KebijakanUCB           = "ucb"
+)
+
+// parameterDefault adalah ε untuk epsilon-greedy, suhu τ untuk softmax, dan
+// koefisien eksplorasi c untuk UCB.
+var parameterDefault = map[string]float64{
+	KebijakanGreedy:        0,
+	KebijakanEpsilonGreedy: 0.1,
+	KebijakanSoftmax:       1.0,
+	KebijakanUCB:           1.0,
+}
+
+// Kebijakan menentukan cara memilih aksi dari nilai Q sebuah state.
+type Kebijakan struct {
+	Nama      string  `json:"nama"`
+	Parameter float64 `json:"parameter"`
+}
+
+var DefaultKebijakan = Kebijakan{Nama: KebijakanGreedy}
+
+// NewKebijakan membuat kebijakan dengan parameter default jika parameter nil.
+func NewKebijakan(nama string, parameter *float64) (Kebijakan, error) {
+	def, ok := parameterDefault[nama]
+	if !ok {
+		return Kebijakan{}, fmt.Errorf("kebijakan %q tidak dikenal", nama)
+	}
+	k := Kebijakan{Nama: nama, Parameter: def}
+	if parameter != nil {
+		k.Parameter = *parameter
+	}
+	return k, k.Validate()
+}
+
+func (k Kebijakan) Validate() error {
+	switch k.Nama {
+	case KebijakanGreedy:
+		return nil
+	case KebijakanEpsilonGreedy:
+		if k.Parameter < 0 || k.Parameter > 1 {
+			return fmt.Errorf("epsilon harus di antara 0 dan 1, didapat %v", k.Parameter)
+		}
+	case KebijakanSoftmax:
+		if k.Parameter <= 0 {
+			return fmt.Errorf("suhu softmax harus lebih dari 0, didapat %v", k.Parameter)
+		}
+	case KebijakanUCB:
+		if k.Parameter < 0 {
+			return fmt.Errorf("koefisien UCB tidak boleh negatif, didapat %v", k.Parameter)
+		}
+	default:
+		return fmt.Errorf("kebijakan %q tidak dikenal", k.Nama)
+	}
+	return nil
+}
+
+// KebijakanFromEnv membaca REKOMENDASI_KEBIJAKAN dan
+// REKOMENDASI_PARAMETER_KEBIJAKAN. Default-nya greedy.
+func KebijakanFromEnv() (Kebijakan, error) {
+	nama := os.Getenv("REKOMENDASI_KEBIJAKAN")
+	if nama == "" {
+		return DefaultKebijakan, nil
+	}
+
+	var parameter *float64
+	if v := os.Getenv("REKOMENDASI_PARAMETER_KEBIJAKAN"); v != "" {
+		p, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return DefaultKebijakan, fmt.Errorf("REKOMENDASI_PARAMETER_KEBIJAKAN tidak valid: %w", err)
+		}
+		parameter = &p
+	}
+
+	k, err := NewKebijakan(nama, parameter)
+	if err != nil {
+		return DefaultKebijakan, err
+	}
+	return k, nil
+}
+
+// urutkanAksi mengembalikan nama aksi terurut agar hasil pemilihan tidak
+// bergantung pada urutan iterasi map.
+func urutkanAksi(nilaiQ map[string]float64) []string {
+	aksi := make([]string, 0, len(nilaiQ))
+	for a := range nilaiQ {
+		aksi = append(aksi, a)
+	}
+	sort.Strings(aksi)
+	return aksi
+}
+
+// Distribusi mengembalikan peluang setiap aksi dipilih oleh kebijakan.
+// kunjungan adalah berapa kali setiap aksi sudah direkomendasikan pada state
+// ini, dipakai oleh UCB.
+func (k Kebijakan) Distribusi(nilaiQ map[string]float64, kunjungan map[string]int) map[string]float64 {
+	aksi := urutkanAksi(nilaiQ)
+	peluang := make(map[string]float64, len(aksi))
+	if len(aksi) == 0 {
+		return peluang
+	}
+
+	terbaik, _, _ := aksiTerbaik(nilaiQ)
+
+	switch k.Nama {
+	case KebijakanEpsilonGreedy:
+		for _, a := range aksi {
+			peluang[a] = k.Parameter / float64(len(aksi))
+		}
+		peluang[terbaik] += 1 - k.Parameter
+
+	case KebijakanSoftmax:
+		// Dikurangi nilai maksimum agar exp tidak overflow.
+		maks := nilaiQ[terbaik]
+		var total float64
+		for _, a := range aksi {
+			peluang[a] = math.Exp((nilaiQ[a] - maks) / k.Parameter)
+			total += peluang[a]
+		}
+		for _, a := range aksi {
+			peluang[a] /= total
+		}
+
+	case KebijakanUCB:
+		// UCB1: aksi yang belum pernah dicoba selalu didahulukan.
+		var totalKunjungan int
+		for _, a := range aksi {
+			totalKunjungan += kunjungan[a]
+		}
+		pilihan, skorMaks := "", math.Inf(-1)
+		for _, a := range aksi {
+			skor := math.Inf(1)
+			if n := kunjungan[a]; n > 0 {
+				skor = nilaiQ[a] + k.Parameter*math.Sqrt(math.Log(float64(totalKunjungan))/float64(n))
+			}
+			if skor > skorMaks {
+				pilihan, skorMaks = a, skor
+			}
+		}
+		peluang[pilihan] = 1
+
+	default:
+		peluang[terbaik] = 1
+	}
+
+	return peluang
+}
+
+// Pilih mengambil sampel aksi dari Distribusi dan mengembalikan aksi beserta
+// propensitasnya (peluang aksi tersebut dipilih). rng nil berarti memakai
+// sumber acak global.
+func (k Kebijakan) Pilih(nilaiQ map[string]float64, kunjungan map[string]int, rng *rand.Rand) (string, float64) {
+	peluang := k.Distribusi(nilaiQ, kunjungan)
+	aksi := urutkanAksi(nilaiQ)
+	if len(aksi) == 0 {
+		return "", 0
+	}
+
+	var u float64
+	if rng != nil {
+		u = rng.Float64()
+	} else {
+		u = rand.Float64()
+	}
+
+	var kumulatif float64
+	for _, a := range aksi {
+		kumulatif += peluang[a]
+		if u < kumulatif {
+			return a, peluang[a]
+		}
+	}
+
+	// Sisa pembulatan floating point: ambil aksi terakhir yang berpeluang.
+	for i := len(aksi) - 1; i >= 0; i-- {
+		if peluang[aksi[i]] > 0 {
+			return aksi[i], peluang[aksi[i]]
+		}
+	}
+	return aksi[len(aksi)-1], peluang[aksi[len(aksi)-1]]
+}
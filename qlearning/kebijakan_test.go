@@ -0,0 +1,157 @@
+package qlearning
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+const toleransiPeluang = 1e-6
+
+func TestDistribusi(t *testing.T) {
+	nilaiQ := map[string]float64{"bada isya": 1, "bada maghrib": 2, "bada shubuh": 3}
+
+	tests := []struct {
+		name      string
+		kebijakan Kebijakan
+		kunjungan map[string]int
+		want      map[string]float64
+	}{
+		{
+			name:      "greedy",
+			kebijakan: Kebijakan{Nama: KebijakanGreedy},
+			want:      map[string]float64{"bada shubuh": 1},
+		},
+		{
+			// Aksi terbaik mendapat 1-ε+ε/n, aksi lain ε/n.
+			name:      "epsilon-greedy",
+			kebijakan: Kebijakan{Nama: KebijakanEpsilonGreedy, Parameter: 0.3},
+			want:      map[string]float64{"bada isya": 0.1, "bada maghrib": 0.1, "bada shubuh": 0.8},
+		},
+		{
+			name:      "epsilon-greedy dengan epsilon nol",
+			kebijakan: Kebijakan{Nama: KebijakanEpsilonGreedy, Parameter: 0},
+			want:      map[string]float64{"bada isya": 0, "bada maghrib": 0, "bada shubuh": 1},
+		},
+		{
+			// exp(Q/τ) dinormalkan: e^-2, e^-1, dan 1 dibagi jumlahnya.
+			name:      "softmax",
+			kebijakan: Kebijakan{Nama: KebijakanSoftmax, Parameter: 1},
+			want:      map[string]float64{"bada isya": 0.0900306, "bada maghrib": 0.2447285, "bada shubuh": 0.6652410},
+		},
+		{
+			name:      "softmax suhu tinggi mendekati seragam",
+			kebijakan: Kebijakan{Nama: KebijakanSoftmax, Parameter: 1e9},
+			want:      map[string]float64{"bada isya": 1.0 / 3, "bada maghrib": 1.0 / 3, "bada shubuh": 1.0 / 3},
+		},
+		{
+			name:      "ucb mendahulukan aksi yang belum dicoba",
+			kebijakan: Kebijakan{Nama: KebijakanUCB, Parameter: 1},
+			kunjungan: map[string]int{"bada maghrib": 3, "bada shubuh": 5},
+			want:      map[string]float64{"bada isya": 1},
+		},
+		{
+			// Bonus eksplorasi aksi yang jarang dicoba mengalahkan nilai Q
+			// tertinggi yang sudah sering dicoba.
+			name:      "ucb dengan semua aksi sudah dicoba",
+			kebijakan: Kebijakan{Nama: KebijakanUCB, Parameter: 2},
+			kunjungan: map[string]int{"bada isya": 1, "bada maghrib": 20, "bada shubuh": 20},
+			want:      map[string]float64{"bada isya": 1},
+		},
+		{
+			name:      "ucb tanpa eksplorasi sama dengan greedy",
+			kebijakan: Kebijakan{Nama: KebijakanUCB, Parameter: 0},
+			kunjungan: map[string]int{"bada isya": 1, "bada maghrib": 20, "bada shubuh": 20},
+			want:      map[string]float64{"bada shubuh": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.kebijakan.Distribusi(nilaiQ, tt.kunjungan)
+
+			var total float64
+			for a, p := range got {
+				total += p
+				if math.Abs(p-tt.want[a]) > toleransiPeluang {
+					t.Errorf("peluang %q = %v, want %v", a, p, tt.want[a])
+				}
+			}
+			if math.Abs(total-1) > toleransiPeluang {
+				t.Errorf("jumlah peluang = %v, want 1", total)
+			}
+		})
+	}
+}
+
+func TestDistribusiTanpaAksi(t *testing.T) {
+	if got := (Kebijakan{Nama: KebijakanSoftmax, Parameter: 1}).Distribusi(nil, nil); len(got) != 0 {
+		t.Errorf("Distribusi(nil) = %v, want kosong", got)
+	}
+	if aksi, p := (Kebijakan{Nama: KebijakanGreedy}).Pilih(nil, nil, nil); aksi != "" || p != 0 {
+		t.Errorf("Pilih(nil) = %q, %v, want kosong", aksi, p)
+	}
+}
+
+func TestPilih(t *testing.T) {
+	nilaiQ := map[string]float64{"bada isya": 1, "bada maghrib": 2, "bada shubuh": 3}
+
+	tests := []struct {
+		name      string
+		kebijakan Kebijakan
+	}{
+		{"greedy", Kebijakan{Nama: KebijakanGreedy}},
+		{"epsilon-greedy", Kebijakan{Nama: KebijakanEpsilonGreedy, Parameter: 0.3}},
+		{"softmax", Kebijakan{Nama: KebijakanSoftmax, Parameter: 1}},
+	}
+
+	const sampel = 20000
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rng := rand.New(rand.NewPCG(1, 1))
+			want := tt.kebijakan.Distribusi(nilaiQ, nil)
+
+			frekuensi := make(map[string]int)
+			for range sampel {
+				aksi, propensitas := tt.kebijakan.Pilih(nilaiQ, nil, rng)
+				if propensitas != want[aksi] {
+					t.Fatalf("propensitas %q = %v, want %v", aksi, propensitas, want[aksi])
+				}
+				frekuensi[aksi]++
+			}
+			for a, p := range want {
+				if got := float64(frekuensi[a]) / sampel; math.Abs(got-p) > 0.02 {
+					t.Errorf("frekuensi %q = %.3f, want %.3f", a, got, p)
+				}
+			}
+		})
+	}
+}
+
+func TestNewKebijakan(t *testing.T) {
+	negatif := -0.5
+	tests := []struct {
+		name      string
+		nama      string
+		parameter *float64
+		want      Kebijakan
+		wantErr   bool
+	}{
+		{"default epsilon", KebijakanEpsilonGreedy, nil, Kebijakan{Nama: KebijakanEpsilonGreedy, Parameter: 0.1}, false},
+		{"default softmax", KebijakanSoftmax, nil, Kebijakan{Nama: KebijakanSoftmax, Parameter: 1}, false},
+		{"ucb negatif", KebijakanUCB, &negatif, Kebijakan{}, true},
+		{"tidak dikenal", "acak", nil, Kebijakan{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewKebijakan(tt.nama, tt.parameter)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewKebijakan error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("NewKebijakan = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
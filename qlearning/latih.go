@@ -0,0 +1,88 @@
+package qlearning
+
+import (
+	"math"
+	"math/rand/v2"
+)
+
+// Episode adalah satu pengalaman pelatihan: aksi yang dijalankan pada sebuah
+// state dan reward yang diterimanya.
+type Episode struct {
+	State  string
+	Aksi   string
+	Reward float64
+}
+
+// OpsiLatih mengatur pelatihan offline. Params.Alpha tidak dipakai: laju
+// belajar Latih menurun per kunjungan, lihat Latih.
+type OpsiLatih struct {
+	Params
+	Epoch int
+	Seed  uint64
+	// Toleransi menghentikan pelatihan lebih awal jika perubahan terbesar
+	// nilai Q antara dua epoch berurutan di bawah nilai ini.
+	Toleransi float64
+}
+
+type RingkasanLatih struct {
+	Epoch     int
+	Konvergen bool
+	// PerubahanMaks adalah |ΔQ| terbesar antara tabel sebelum dan sesudah
+	// setiap epoch.
+	PerubahanMaks []float64
+}
+
+// Latih menjalankan Q-learning tabular atas episode secara berulang dengan
+// urutan acak dari Seed, sehingga hasilnya dapat direproduksi. Seperti pada
+// pembaruan online, state berikutnya dianggap sama dengan state episode.
+//
+// Laju belajar untuk (s,a) adalah 1/n(s,a), dengan n jumlah kunjungannya.
+// Dengan alpha konstan, episode yang reward-nya saling bertentangan membuat
+// nilai Q terus berayun mengikuti urutan episode sehingga tidak pernah
+// konvergen; dengan 1/n nilai Q menjadi rata-rata target yang stabil.
+func Latih(episode []Episode, opsi OpsiLatih) (Tabel, RingkasanLatih) {
+	tabel := make(Tabel)
+	ringkasan := RingkasanLatih{}
+	rng := rand.New(rand.NewPCG(opsi.Seed, opsi.Seed))
+	kunjungan := make(map[[2]string]int)
+
+	urutan := make([]int, len(episode))
+	for i := range urutan {
+		urutan[i] = i
+	}
+
+	for epoch := 1; epoch <= opsi.Epoch; epoch++ {
+		rng.Shuffle(len(urutan), func(i, j int) { urutan[i], urutan[j] = urutan[j], urutan[i] })
+
+		sebelum := tabel.Salin()
+		for _, i := range urutan {
+			e := episode[i]
+			kunci := [2]string{e.State, e.Aksi}
+			kunjungan[kunci]++
+			p := Params{Alpha: 1 / float64(kunjungan[kunci]), Gamma: opsi.Gamma}
+			tabel.Update(e.State, e.Aksi, e.Reward, e.State, p)
+		}
+
+		maks := perubahanMaks(sebelum, tabel)
+		ringkasan.Epoch = epoch
+		ringkasan.PerubahanMaks = append(ringkasan.PerubahanMaks, maks)
+		if maks < opsi.Toleransi {
+			ringkasan.Konvergen = true
+			break
+		}
+	}
+
+	return tabel, ringkasan
+}
+
+// perubahanMaks mengembalikan selisih nilai Q terbesar antara dua tabel. Aksi
+// yang belum ada di tabel lama dianggap bernilai nol.
+func perubahanMaks(lama, baru Tabel) float64 {
+	var maks float64
+	for state, aksi := range baru {
+		for a, q := range aksi {
+			maks = math.Max(maks, math.Abs(q-lama[state][a]))
+		}
+	}
+	return maks
+}
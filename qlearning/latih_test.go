@@ -0,0 +1,54 @@
+package qlearning
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLatihKonvergen(t *testing.T) {
+	tests := []struct {
+		name    string
+		episode []Episode
+		gamma   float64
+		want    float64
+	}{
+		{
+			name:    "reward identik",
+			episode: []Episode{{"kuliah_1-5 Juz", "bada shubuh", 4}, {"kuliah_1-5 Juz", "bada shubuh", 4}},
+			gamma:   0,
+			want:    4,
+		},
+		{
+			name:    "reward identik dengan gamma",
+			episode: []Episode{{"kuliah_1-5 Juz", "bada shubuh", 4}, {"kuliah_1-5 Juz", "bada shubuh", 4}},
+			gamma:   0.3,
+			want:    4 / 0.7,
+		},
+		{
+			name:    "reward bertentangan",
+			episode: []Episode{{"kuliah_1-5 Juz", "bada shubuh", 1}, {"kuliah_1-5 Juz", "bada shubuh", 5}, {"kuliah_1-5 Juz", "bada shubuh", 3}},
+			gamma:   0,
+			want:    3,
+		},
+		{
+			name:    "reward bertentangan dengan gamma",
+			episode: []Episode{{"kuliah_1-5 Juz", "bada shubuh", 1}, {"kuliah_1-5 Juz", "bada shubuh", 5}, {"kuliah_1-5 Juz", "bada shubuh", 3}},
+			gamma:   0.3,
+			want:    3 / 0.7,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opsi := OpsiLatih{Params: Params{Alpha: 0.1, Gamma: tt.gamma}, Epoch: 20000, Seed: 1, Toleransi: 1e-6}
+			tabel, ringkasan := Latih(tt.episode, opsi)
+			if !ringkasan.Konvergen {
+				t.Fatalf("tidak konvergen setelah %d epoch, |dQ| terakhir %g", ringkasan.Epoch, ringkasan.PerubahanMaks[len(ringkasan.PerubahanMaks)-1])
+			}
+			got := tabel["kuliah_1-5 Juz"]["bada shubuh"]
+			if math.Abs(got-tt.want) > 1e-2 {
+				t.Errorf("Q = %v, want %v (epoch %d)", got, tt.want, ringkasan.Epoch)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package qlearning
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// StateMirip adalah state yang dikenal model beserta bobot kemiripannya
+// terhadap state yang dicari (0–1).
+type StateMirip struct {
+	State     string
+	Kemiripan float64
+}
+
+// Aktivitas memecah kesibukan menjadi aktivitas penyusunnya, mis.
+// "kuliah + organisasi" menjadi ["kuliah", "organisasi"].
+func Aktivitas(kesibukan string) []string {
+	var hasil []string
+	for _, a := range strings.Split(kesibukan, "+") {
+		if a = strings.ToLower(strings.TrimSpace(a)); a != "" {
+			hasil = append(hasil, a)
+		}
+	}
+	return hasil
+}
+
+// NormalisasiKesibukan menyeragamkan penulisan kesibukan agar sesuai dengan
+// state di model, mis. "Kuliah+Organisasi" menjadi "kuliah + organisasi".
+func NormalisasiKesibukan(kesibukan string) string {
+	return strings.Join(Aktivitas(kesibukan), " + ")
+}
+
+// Kesibukan mengembalikan semua kesibukan yang ada di state tabel, terurut.
+func (t Tabel) Kesibukan() []string {
+	return t.unikState(func(kesibukan string) []string { return []string{kesibukan} })
+}
+
+// Aktivitas mengembalikan semua aktivitas penyusun kesibukan di tabel, terurut.
+func (t Tabel) Aktivitas() []string {
+	return t.unikState(Aktivitas)
+}
+
+func (t Tabel) unikState(urai func(kesibukan string) []string) []string {
+	ada := make(map[string]bool)
+	for state := range t {
+		kesibukan, _ := UraiState(state)
+		for _, k := range urai(kesibukan) {
+			ada[k] = true
+		}
+	}
+
+	hasil := make([]string, 0, len(ada))
+	for k := range ada {
+		hasil = append(hasil, k)
+	}
+	sort.Strings(hasil)
+	return hasil
+}
+
+// kemiripanAktivitas adalah indeks Jaccard dua kumpulan aktivitas.
+func kemiripanAktivitas(a, b []string) float64 {
+	himpunan := make(map[string]bool, len(a))
+	for _, x := range a {
+		himpunan[x] = true
+	}
+
+	var irisan int
+	gabungan := len(himpunan)
+	sudah := make(map[string]bool, len(b))
+	for _, x := range b {
+		if sudah[x] {
+			continue
+		}
+		sudah[x] = true
+		if himpunan[x] {
+			irisan++
+		} else {
+			gabungan++
+		}
+	}
+	if gabungan == 0 {
+		return 0
+	}
+	return float64(irisan) / float64(gabungan)
+}
+
+// rentangJuz membaca kategori hafalan berbentuk "11-20 Juz".
+func rentangJuz(kategori string) (int, int, bool) {
+	var bawah, atas int
+	if _, err := fmt.Sscanf(kategori, "%d-%d", &bawah, &atas); err != nil {
+		return 0, 0, false
+	}
+	return bawah, atas, true
+}
+
+// kemiripanHafalan bernilai 1 untuk kategori yang sama, 0,5 untuk kategori
+// yang bersebelahan, dan 0 selain itu.
+func kemiripanHafalan(a, b string) float64 {
+	if strings.EqualFold(a, b) {
+		return 1
+	}
+	bawahA, atasA, okA := rentangJuz(a)
+	bawahB, atasB, okB := rentangJuz(b)
+	if okA && okB && (atasA+1 == bawahB || atasB+1 == bawahA) {
+		return 0.5
+	}
+	return 0
+}
+
+// Kemiripan membandingkan dua state dari aktivitas kesibukan dan kategori
+// hafalannya. Hasilnya 0 jika tidak ada aktivitas yang sama atau kategori
+// hafalannya berjauhan.
+func Kemiripan(stateA, stateB string) float64 {
+	kesibukanA, kategoriA := UraiState(stateA)
+	kesibukanB, kategoriB := UraiState(stateB)
+	return kemiripanAktivitas(Aktivitas(kesibukanA), Aktivitas(kesibukanB)) *
+		kemiripanHafalan(kategoriA, kategoriB)
+}
+
+// Mirip mencari hingga maks state paling mirip di tabel lalu menggabungkan
+// nilai Q-nya sebagai rata-rata berbobot kemiripan. Aksi yang tidak ada pada
+// sebuah state dihitung bernilai 0, sama seperti nilai awal Q-learning.
+func (t Tabel) Mirip(state string, maks int) ([]StateMirip, map[string]float64) {
+	var kandidat []StateMirip
+	for s, aksi := range t {
+		if s == state || len(aksi) == 0 {
+			continue
+		}
+		if k := Kemiripan(state, s); k > 0 {
+			kandidat = append(kandidat, StateMirip{State: s, Kemiripan: k})
+		}
+	}
+	if len(kandidat) == 0 {
+		return nil, nil
+	}
+
+	sort.Slice(kandidat, func(i, j int) bool {
+		if kandidat[i].Kemiripan != kandidat[j].Kemiripan {
+			return kandidat[i].Kemiripan > kandidat[j].Kemiripan
+		}
+		return kandidat[i].State < kandidat[j].State
+	})
+	if maks > 0 && len(kandidat) > maks {
+		kandidat = kandidat[:maks]
+	}
+
+	var totalBobot float64
+	nilaiQ := make(map[string]float64)
+	for _, k := range kandidat {
+		totalBobot += k.Kemiripan
+		for a, q := range t[k.State] {
+			nilaiQ[a] += k.Kemiripan * q
+		}
+	}
+	for a := range nilaiQ {
+		nilaiQ[a] /= totalBobot
+	}
+	return kandidat, nilaiQ
+}
@@ -0,0 +1,114 @@
+package qlearning
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestKemiripan(t *testing.T) {
+	tests := []struct {
+		name   string
+		stateA string
+		stateB string
+		want   float64
+	}{
+		{"identik", "kuliah_11-20 Juz", "kuliah_11-20 Juz", 1},
+		{"sebagian aktivitas sama", "kuliah + organisasi_11-20 Juz", "kuliah_11-20 Juz", 0.5},
+		{"penulisan berbeda", "Kuliah+Organisasi_11-20 Juz", "organisasi + kuliah_11-20 Juz", 1},
+		{"juz bersebelahan", "kuliah_11-20 Juz", "kuliah_21-30 Juz", 0.5},
+		{"juz bersebelahan ke bawah", "kuliah_11-20 Juz", "kuliah_1-10 Juz", 0.5},
+		{"sebagian aktivitas dan juz bersebelahan", "kuliah + organisasi_11-20 Juz", "kuliah_21-30 Juz", 0.25},
+		{"juz berjauhan", "kuliah_1-10 Juz", "kuliah_21-30 Juz", 0},
+		{"aktivitas berbeda", "kuliah_11-20 Juz", "kerja_11-20 Juz", 0},
+		{"kategori bukan rentang", "kuliah_hafal semua", "kuliah_21-30 Juz", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Kemiripan(tt.stateA, tt.stateB); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Kemiripan(%q, %q) = %v, want %v", tt.stateA, tt.stateB, got, tt.want)
+			}
+			if got := Kemiripan(tt.stateB, tt.stateA); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Kemiripan tidak simetris: (%q, %q) = %v, want %v", tt.stateB, tt.stateA, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMirip(t *testing.T) {
+	tabel := Tabel{
+		"kuliah_11-20 Juz":              {"bada shubuh": 4, "bada isya": 2},
+		"kuliah + organisasi_11-20 Juz": {"bada isya": 4},
+		"kuliah_21-30 Juz":              {"bada maghrib": 3},
+		"kuliah_1-5 Juz":                {"bada shubuh": 10},
+		"kerja_11-20 Juz":               {"bada dzuhur": 5},
+		"kuliah + kerja_11-20 Juz":      {},
+	}
+
+	tests := []struct {
+		name       string
+		state      string
+		maks       int
+		wantStates []StateMirip
+		wantQ      map[string]float64
+	}{
+		{
+			// Bobot 1 dan 0,5: (1*4 + 0,5*0) / 1,5 untuk bada shubuh, dan
+			// seterusnya. Aksi yang tidak ada pada sebuah state bernilai 0.
+			name:  "dua state termirip",
+			state: "kuliah + organisasi + kerja_11-20 Juz",
+			maks:  2,
+			wantStates: []StateMirip{
+				{State: "kuliah + organisasi_11-20 Juz", Kemiripan: 2.0 / 3},
+				{State: "kerja_11-20 Juz", Kemiripan: 1.0 / 3},
+			},
+			wantQ: map[string]float64{"bada isya": 4 * 2.0 / 3, "bada dzuhur": 5 * 1.0 / 3},
+		},
+		{
+			name:  "maks memotong kandidat dengan kemiripan sama menurut nama",
+			state: "kuliah + organisasi_21-30 Juz",
+			maks:  1,
+			wantStates: []StateMirip{
+				{State: "kuliah + organisasi_11-20 Juz", Kemiripan: 0.5},
+			},
+			wantQ: map[string]float64{"bada isya": 4},
+		},
+		{
+			name:  "maks nol berarti semua kandidat",
+			state: "organisasi_11-20 Juz",
+			maks:  0,
+			wantStates: []StateMirip{
+				{State: "kuliah + organisasi_11-20 Juz", Kemiripan: 0.5},
+			},
+			wantQ: map[string]float64{"bada isya": 4},
+		},
+		{
+			name:  "tidak ada state mirip",
+			state: "mondok_11-20 Juz",
+			maks:  3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			states, nilaiQ := tabel.Mirip(tt.state, tt.maks)
+			if len(states) != len(tt.wantStates) {
+				t.Fatalf("Mirip states = %+v, want %+v", states, tt.wantStates)
+			}
+			for i, s := range states {
+				if s.State != tt.wantStates[i].State || math.Abs(s.Kemiripan-tt.wantStates[i].Kemiripan) > 1e-9 {
+					t.Errorf("state %d = %+v, want %+v", i, s, tt.wantStates[i])
+				}
+			}
+			if !reflect.DeepEqual(urutkanAksi(nilaiQ), urutkanAksi(tt.wantQ)) {
+				t.Fatalf("aksi = %v, want %v", urutkanAksi(nilaiQ), urutkanAksi(tt.wantQ))
+			}
+			for a, q := range tt.wantQ {
+				if math.Abs(nilaiQ[a]-q) > 1e-9 {
+					t.Errorf("Q %q = %v, want %v", a, nilaiQ[a], q)
+				}
+			}
+		})
+	}
+}
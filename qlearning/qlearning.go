@@ -0,0 +1,166 @@
+// Package qlearning berisi Q-learning tabular untuk rekomendasi jadwal
+// murojaah. State berbentuk "<kesibukan>_<kategori hafalan>" dan aksi adalah
+// daftar slot jadwal ("bada shubuh, bada isya").
+package qlearning
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// State menyusun state dari kesibukan dan kategori hafalan, mis.
+// "kuliah + organisasi_21-30 Juz".
+func State(kesibukan, kategoriHafalan string) string {
+	return kesibukan + "_" + kategoriHafalan
+}
+
+// UraiState memecah state menjadi kesibukan dan kategori hafalan.
+func UraiState(state string) (string, string) {
+	i := strings.LastIndex(state, "_")
+	if i < 0 {
+		return state, ""
+	}
+	return state[:i], state[i+1:]
+}
+
+// Tabel memetakan state ke nilai Q setiap aksi. Tabel yang sudah dipublikasikan
+// tidak boleh diubah; gunakan Salin sebelum memperbarui.
+type Tabel map[string]map[string]float64
+
+func (t Tabel) Salin() Tabel {
+	salinan := make(Tabel, len(t))
+	for state, aksi := range t {
+		salinan[state] = make(map[string]float64, len(aksi))
+		for a, q := range aksi {
+			salinan[state][a] = q
+		}
+	}
+	return salinan
+}
+
+// Terbaik mengembalikan aksi dengan nilai Q tertinggi pada state.
+func (t Tabel) Terbaik(state string) (string, float64, bool) {
+	return aksiTerbaik(t[state])
+}
+
+// aksiTerbaik memilih aksi dengan nilai Q tertinggi. Jika nilainya sama, aksi
+// yang urutannya lebih kecil dipilih agar hasilnya deterministik.
+func aksiTerbaik(nilaiQ map[string]float64) (string, float64, bool) {
+	if len(nilaiQ) == 0 {
+		return "", 0, false
+	}
+
+	var terbaik string
+	maks := math.Inf(-1)
+	for a, q := range nilaiQ {
+		if q > maks || (q == maks && a < terbaik) {
+			terbaik, maks = a, q
+		}
+	}
+	return terbaik, maks, true
+}
+
+// AksiQ adalah satu aksi beserta nilai Q-nya.
+type AksiQ struct {
+	Aksi  string
+	Nilai float64
+}
+
+// Peringkat mengurutkan aksi dari nilai Q tertinggi. Nilai yang sama diurutkan
+// menurut nama aksi, konsisten dengan Terbaik.
+func Peringkat(nilaiQ map[string]float64) []AksiQ {
+	hasil := make([]AksiQ, 0, len(nilaiQ))
+	for a, q := range nilaiQ {
+		hasil = append(hasil, AksiQ{Aksi: a, Nilai: q})
+	}
+	sort.Slice(hasil, func(i, j int) bool {
+		if hasil[i].Nilai != hasil[j].Nilai {
+			return hasil[i].Nilai > hasil[j].Nilai
+		}
+		return hasil[i].Aksi < hasil[j].Aksi
+	})
+	return hasil
+}
+
+// Params adalah hyperparameter pembaruan Q-learning.
+type Params struct {
+	Alpha float64 `json:"alpha"`
+	Gamma float64 `json:"gamma"`
+}
+
+var DefaultParams = Params{Alpha: 0.1, Gamma: 0.3}
+
+func (p Params) Validate() error {
+	if p.Alpha <= 0 || p.Alpha > 1 {
+		return fmt.Errorf("alpha harus di antara 0 dan 1, didapat %v", p.Alpha)
+	}
+	if p.Gamma < 0 || p.Gamma >= 1 {
+		return fmt.Errorf("gamma harus di antara 0 dan kurang dari 1, didapat %v", p.Gamma)
+	}
+	return nil
+}
+
+// ParamsFromEnv membaca QLEARNING_ALPHA dan QLEARNING_GAMMA. Nilai yang kosong
+// memakai DefaultParams.
+func ParamsFromEnv() (Params, error) {
+	p := DefaultParams
+	if v := os.Getenv("QLEARNING_ALPHA"); v != "" {
+		alpha, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return DefaultParams, fmt.Errorf("QLEARNING_ALPHA tidak valid: %w", err)
+		}
+		p.Alpha = alpha
+	}
+	if v := os.Getenv("QLEARNING_GAMMA"); v != "" {
+		gamma, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return DefaultParams, fmt.Errorf("QLEARNING_GAMMA tidak valid: %w", err)
+		}
+		p.Gamma = gamma
+	}
+	if err := p.Validate(); err != nil {
+		return DefaultParams, err
+	}
+	return p, nil
+}
+
+// Update menerapkan satu langkah Q-learning
+//
+//	Q(s,a) ← Q(s,a) + α [r + γ max Q(s',·) − Q(s,a)]
+//
+// langsung pada tabel, lalu mengembalikan nilai Q sebelum dan sesudahnya.
+// State atau aksi yang belum ada dimulai dari nol.
+func (t Tabel) Update(state, aksi string, reward float64, stateBerikut string, p Params) (float64, float64) {
+	if t[state] == nil {
+		t[state] = make(map[string]float64)
+	}
+	lama := t[state][aksi]
+
+	var maksBerikut float64
+	if _, q, ok := t.Terbaik(stateBerikut); ok {
+		maksBerikut = q
+	}
+
+	baru := lama + p.Alpha*(reward+p.Gamma*maksBerikut-lama)
+	t[state][aksi] = baru
+	return lama, baru
+}
+
+// RewardMaks adalah reward tertinggi, sama dengan skala EfektifitasJadwal.
+const RewardMaks = 5.0
+
+// Reward menggabungkan penilaian efektivitas jadwal dari user (1–5) dan
+// tingkat penyelesaian target murojaah (0–1) dengan bobot yang sama. Jika user
+// belum menilai (efektifitas 0), hanya tingkat penyelesaian yang dipakai.
+func Reward(efektifitas int, tingkatSelesai float64) float64 {
+	tingkatSelesai = math.Max(0, math.Min(1, tingkatSelesai))
+	if efektifitas <= 0 {
+		return tingkatSelesai * RewardMaks
+	}
+	efektifitas = min(efektifitas, 5)
+	return 0.5*float64(efektifitas) + 0.5*tingkatSelesai*RewardMaks
+}
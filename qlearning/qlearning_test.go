@@ -0,0 +1,82 @@
+package qlearning
+
+import (
+	"math"
+	"testing"
+)
+
+func TestUpdate(t *testing.T) {
+	p := Params{Alpha: 0.1, Gamma: 0.3}
+
+	tests := []struct {
+		name         string
+		tabel        Tabel
+		stateBerikut string
+		reward       float64
+		wantLama     float64
+		wantBaru     float64
+	}{
+		{
+			name:         "state baru dimulai dari nol",
+			tabel:        Tabel{},
+			stateBerikut: "kuliah_1-5 Juz",
+			reward:       5,
+			wantLama:     0,
+			wantBaru:     0.5,
+		},
+		{
+			// 0.5 + 0.1 * (5 + 0.3*0.5 - 0.5)
+			name:         "state berikutnya sama",
+			tabel:        Tabel{"kuliah_1-5 Juz": {"bada shubuh": 0.5}},
+			stateBerikut: "kuliah_1-5 Juz",
+			reward:       5,
+			wantLama:     0.5,
+			wantBaru:     0.965,
+		},
+		{
+			// 1 + 0.1 * (2 + 0.3*4 - 1)
+			name:         "memakai nilai Q terbaik state berikutnya",
+			tabel:        Tabel{"kuliah_1-5 Juz": {"bada shubuh": 1}, "kerja_1-5 Juz": {"bada isya": 4, "bada maghrib": -1}},
+			stateBerikut: "kerja_1-5 Juz",
+			reward:       2,
+			wantLama:     1,
+			wantBaru:     1.22,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lama, baru := tt.tabel.Update("kuliah_1-5 Juz", "bada shubuh", tt.reward, tt.stateBerikut, p)
+			if math.Abs(lama-tt.wantLama) > 1e-9 || math.Abs(baru-tt.wantBaru) > 1e-9 {
+				t.Errorf("Update = (%v, %v), want (%v, %v)", lama, baru, tt.wantLama, tt.wantBaru)
+			}
+			if got := tt.tabel["kuliah_1-5 Juz"]["bada shubuh"]; got != baru {
+				t.Errorf("nilai di tabel = %v, want %v", got, baru)
+			}
+		})
+	}
+}
+
+func TestReward(t *testing.T) {
+	tests := []struct {
+		name           string
+		efektifitas    int
+		tingkatSelesai float64
+		want           float64
+	}{
+		{"belum dinilai", 0, 0.5, 2.5},
+		{"nilai dan penyelesaian sama bobot", 4, 1, 4.5},
+		{"maksimal", 5, 1, RewardMaks},
+		{"nilai terendah tanpa penyelesaian", 1, 0, 0.5},
+		{"di atas skala dipotong", 7, 1.5, RewardMaks},
+		{"negatif dipotong", -1, -0.2, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Reward(tt.efektifitas, tt.tingkatSelesai); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Reward(%d, %v) = %v, want %v", tt.efektifitas, tt.tingkatSelesai, got, tt.want)
+			}
+		})
+	}
+}
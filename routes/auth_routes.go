@@ -6,12 +6,13 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/limiter"
 	"github.com/habbazettt/muraja-server/middlewares"
+	"github.com/habbazettt/muraja-server/notifier"
 	"github.com/habbazettt/muraja-server/services"
 	"gorm.io/gorm"
 )
 
 func SetupAuthRoutes(app *fiber.App, db *gorm.DB) {
-	services := services.AuthService{DB: db}
+	services := services.AuthService{DB: db, Notifier: notifier.NewFromEnv()}
 
 	authLimiter := limiter.New(limiter.Config{
 		Max:        10,
@@ -33,7 +34,10 @@ func SetupAuthRoutes(app *fiber.App, db *gorm.DB) {
 	{
 		auth.Post("/register", services.Register)
 		auth.Post("/login", services.Login)
+		auth.Post("/refresh", services.RefreshToken)
+		auth.Post("/logout", middlewares.JWTMiddleware, services.Logout)
 		auth.Post("/forget-password", services.ForgotPassword)
+		auth.Post("/reset-password", services.ResetPassword)
 		auth.Get("/me", middlewares.JWTMiddleware, services.GetCurrentUser)
 	}
 }
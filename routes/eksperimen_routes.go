@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/habbazettt/muraja-server/middlewares"
+	"github.com/habbazettt/muraja-server/services"
+	"github.com/habbazettt/muraja-server/utils"
+	"gorm.io/gorm"
+)
+
+func SetupEksperimenRoutes(app *fiber.App, db *gorm.DB) {
+	service := services.EksperimenService{DB: db}
+
+	eksperimenRoutes := app.Group("/api/v1/admin/eksperimen", middlewares.JWTMiddleware, middlewares.RequirePermission(utils.PermModelManage))
+	{
+		eksperimenRoutes.Get("/", service.GetAllEksperimen)
+		eksperimenRoutes.Post("/", service.CreateEksperimen)
+		eksperimenRoutes.Get("/:id", service.GetEksperimenByID)
+		eksperimenRoutes.Post("/:id/mulai", service.MulaiEksperimen)
+		eksperimenRoutes.Post("/:id/hentikan", service.HentikanEksperimen)
+		eksperimenRoutes.Get("/:id/laporan", service.GetLaporanEksperimen)
+	}
+}
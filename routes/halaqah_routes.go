@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/habbazettt/muraja-server/middlewares"
+	"github.com/habbazettt/muraja-server/services"
+	"github.com/habbazettt/muraja-server/utils"
+	"gorm.io/gorm"
+)
+
+func SetupHalaqahRoutes(app *fiber.App, db *gorm.DB) {
+	service := services.HalaqahService{DB: db}
+
+	halaqahRoutes := app.Group("/api/v1/halaqah", middlewares.JWTMiddleware)
+	{
+		halaqahRoutes.Get("/", service.GetAllHalaqah)
+		halaqahRoutes.Post("/", middlewares.RequirePermission(utils.PermHalaqahCreate), service.CreateHalaqah)
+		halaqahRoutes.Get("/:id", service.GetHalaqahByID)
+		halaqahRoutes.Put("/:id", service.UpdateHalaqah)
+		halaqahRoutes.Delete("/:id", middlewares.RequirePermission(utils.PermHalaqahManage), service.DeleteHalaqah)
+		halaqahRoutes.Post("/:id/anggota", middlewares.RequirePermission(utils.PermHalaqahManage), service.AddAnggota)
+		halaqahRoutes.Delete("/:id/anggota/:userID", service.RemoveAnggota)
+		halaqahRoutes.Put("/:id/privasi", service.UpdatePrivasi)
+		halaqahRoutes.Get("/:id/leaderboard", service.GetLeaderboard)
+		halaqahRoutes.Get("/:id/progress", service.GetProgressHalaqah)
+	}
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/habbazettt/muraja-server/middlewares"
+	"github.com/habbazettt/muraja-server/services"
+	"github.com/habbazettt/muraja-server/utils"
+	"gorm.io/gorm"
+)
+
+func SetupInvitationRoutes(app *fiber.App, db *gorm.DB) {
+	service := services.InvitationService{DB: db}
+
+	invitationRoutes := app.Group("/api/v1/invitation", middlewares.JWTMiddleware, middlewares.RequirePermission(utils.PermInvitationManage))
+	{
+		invitationRoutes.Get("/", service.GetAllInvitations)
+		invitationRoutes.Post("/", service.CreateInvitation)
+		invitationRoutes.Delete("/:id", service.RevokeInvitation)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/habbazettt/muraja-server/middlewares"
 	"github.com/habbazettt/muraja-server/services"
+	"github.com/habbazettt/muraja-server/utils"
 	"gorm.io/gorm"
 )
 
@@ -12,7 +13,7 @@ func SetupJadwalPersonalRoutes(app *fiber.App, db *gorm.DB) {
 
 	jadwalRoutes := app.Group("/api/v1/jadwal-personal", middlewares.JWTMiddleware)
 	{
-		jadwalRoutes.Get("/all", middlewares.RoleMiddleware("admin"), service.GetAllJadwalPersonal)
+		jadwalRoutes.Get("/all", middlewares.RequirePermission(utils.PermJadwalReadAll), service.GetAllJadwalPersonal)
 		jadwalRoutes.Get("/", service.GetJadwalPersonal)
 		jadwalRoutes.Post("/", service.CreateJadwalPersonal)
 		jadwalRoutes.Put("/", service.UpdateJadwalPersonal)
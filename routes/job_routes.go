@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/habbazettt/muraja-server/middlewares"
+	"github.com/habbazettt/muraja-server/scheduler"
+	"github.com/habbazettt/muraja-server/services"
+	"github.com/habbazettt/muraja-server/utils"
+	"gorm.io/gorm"
+)
+
+func SetupJobRoutes(app *fiber.App, db *gorm.DB, jobScheduler *scheduler.Scheduler) {
+	service := services.JobService{DB: db, Scheduler: jobScheduler}
+
+	jobRoutes := app.Group("/api/v1/admin/jobs", middlewares.JWTMiddleware, middlewares.RequirePermission(utils.PermJobManage))
+	{
+		jobRoutes.Get("/", service.GetAllJobs)
+		jobRoutes.Get("/:name/runs", service.GetJobRuns)
+		jobRoutes.Post("/:name/run", service.TriggerJob)
+	}
+}
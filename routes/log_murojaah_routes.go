@@ -13,11 +13,16 @@ func SetupLogMurojaahRoutes(app *fiber.App, db *gorm.DB) {
 	LogRoutes := app.Group("/api/v1/log-harian", middlewares.JWTMiddleware)
 	{
 		LogRoutes.Get("/", service.GetOrCreateLogHarian)
+		LogRoutes.Get("/slot", service.GetSlotMurojaah)
 		LogRoutes.Post("/detail", service.AddDetailToLog)
 		LogRoutes.Put("/detail/:detailID", service.UpdateDetailLog)
 		LogRoutes.Delete("/detail/:detailID", service.DeleteDetailLog)
 		LogRoutes.Get("/rekap/mingguan", service.GetRecapMingguan)
 		LogRoutes.Get("/statistik", service.GetStatistikMurojaah)
+		LogRoutes.Get("/hafalan", service.GetPetaHafalan)
+		LogRoutes.Get("/rencana", service.GetRencanaHarian)
+		LogRoutes.Post("/rencana/terima", service.TerimaRencanaHarian)
+		LogRoutes.Get("/rollover", service.GetRiwayatRollover)
 		LogRoutes.Post("/detail/dari-rekomendasi", service.ApplyAIRekomendasi)
 	}
 }
\ No newline at end of file
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/habbazettt/muraja-server/middlewares"
+	"github.com/habbazettt/muraja-server/services"
+	"gorm.io/gorm"
+)
+
+func SetupMushafRoutes(app *fiber.App, db *gorm.DB) {
+	service := services.MushafService{DB: db}
+
+	mushafRoutes := app.Group("/api/v1/mushaf", middlewares.JWTMiddleware)
+	{
+		mushafRoutes.Get("/", service.GetLayout)
+		mushafRoutes.Get("/layouts", service.GetAllLayouts)
+		mushafRoutes.Get("/halaman/:halaman", service.GetHalaman)
+	}
+}
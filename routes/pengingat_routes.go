@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/habbazettt/muraja-server/middlewares"
+	"github.com/habbazettt/muraja-server/services"
+)
+
+func SetupPengingatRoutes(app *fiber.App, service *services.PengingatService) {
+	pengingatRoutes := app.Group("/api/v1/pengingat", middlewares.JWTMiddleware)
+	{
+		pengingatRoutes.Get("/kanal", service.GetAllKanal)
+		pengingatRoutes.Post("/kanal", service.CreateKanal)
+		pengingatRoutes.Delete("/kanal/:id", service.DeleteKanal)
+		pengingatRoutes.Post("/tes", service.KirimTes)
+		pengingatRoutes.Get("/vapid-public-key", service.GetVapidPublicKey)
+		pengingatRoutes.Get("/slot", service.GetSlotHariIni)
+		pengingatRoutes.Put("/slot", service.UpdatePengaturanSlot)
+		pengingatRoutes.Post("/slot/tunda", service.TundaSlot)
+	}
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/habbazettt/muraja-server/middlewares"
+	"github.com/habbazettt/muraja-server/services"
+	"github.com/habbazettt/muraja-server/utils"
+	"gorm.io/gorm"
+)
+
+func SetupQLearningRoutes(app *fiber.App, db *gorm.DB) {
+	service := services.QLearningService{DB: db}
+
+	qTableRoutes := app.Group("/api/v1/admin/qtable", middlewares.JWTMiddleware, middlewares.RequirePermission(utils.PermModelManage))
+	{
+		qTableRoutes.Get("/versi", service.GetAllVersiQTable)
+		qTableRoutes.Post("/versi", service.UnggahModel)
+		qTableRoutes.Get("/versi/:id", service.GetVersiQTable)
+		qTableRoutes.Post("/versi/:id/rollback", service.RollbackQTable)
+		qTableRoutes.Get("/umpan-balik", service.GetAllUmpanBalik)
+		qTableRoutes.Get("/evaluasi", service.EvaluasiModel)
+	}
+}
@@ -7,11 +7,12 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/limiter"
 	"github.com/habbazettt/muraja-server/middlewares"
 	"github.com/habbazettt/muraja-server/services"
+	"github.com/habbazettt/muraja-server/utils"
 	"gorm.io/gorm"
 )
 
 func SetupRekomendasiRoutes(app *fiber.App, db *gorm.DB) {
-	service := services.RekomendasiService{DB: db}
+	service := services.NewRekomendasiService(db)
 
 	rekomendasiLimiter := limiter.New(limiter.Config{
 		Max:        5,
@@ -38,6 +39,7 @@ func SetupRekomendasiRoutes(app *fiber.App, db *gorm.DB) {
 	{
 		rekomendasiRoutes.Post("/", service.GetRecommendation)
 		rekomendasiRoutes.Get("/", service.GetAllRekomendasi)
-		rekomendasiRoutes.Get("/kesibukan", middlewares.RoleMiddleware("admin"), service.GetAllKesibukan)
+		rekomendasiRoutes.Post("/:id/pilih", service.PilihRekomendasi)
+		rekomendasiRoutes.Get("/kesibukan", middlewares.RequirePermission(utils.PermKesibukanRead), service.GetAllKesibukan)
 	}
 }
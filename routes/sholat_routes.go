@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/habbazettt/muraja-server/middlewares"
+	"github.com/habbazettt/muraja-server/services"
+	"gorm.io/gorm"
+)
+
+func SetupSholatRoutes(app *fiber.App, db *gorm.DB) {
+	service := services.SholatService{DB: db}
+
+	sholatRoutes := app.Group("/api/v1/sholat", middlewares.JWTMiddleware)
+	{
+		sholatRoutes.Get("/", service.GetJadwalSholat)
+		sholatRoutes.Get("/metode", service.GetAllMetode)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/limiter"
 	"github.com/habbazettt/muraja-server/middlewares"
 	"github.com/habbazettt/muraja-server/services"
+	"github.com/habbazettt/muraja-server/utils"
 	"gorm.io/gorm"
 )
 
@@ -35,9 +36,9 @@ func SetupUserRoutes(app *fiber.App, db *gorm.DB) {
 
 	mahasantriRoutes := app.Group("/api/v1/user", methodLimiter)
 	{
-		mahasantriRoutes.Get("/", middlewares.JWTMiddleware, middlewares.RoleMiddleware("admin"), service.GetAllUsers)
+		mahasantriRoutes.Get("/", middlewares.JWTMiddleware, middlewares.RequirePermission(utils.PermUserList), service.GetAllUsers)
 		mahasantriRoutes.Get("/:id", middlewares.JWTMiddleware, service.GetUserById)
 		mahasantriRoutes.Put("/:id", middlewares.JWTMiddleware, service.UpdateUser)
-		mahasantriRoutes.Delete("/:id", middlewares.JWTMiddleware, middlewares.RoleMiddleware("admin"), service.DeleteUser)
+		mahasantriRoutes.Delete("/:id", middlewares.JWTMiddleware, middlewares.RequirePermission(utils.PermUserDelete), service.DeleteUser)
 	}
 }
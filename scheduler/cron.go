@@ -0,0 +1,175 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule menentukan kapan sebuah job berjalan berikutnya.
+type Schedule interface {
+	Next(t time.Time) time.Time
+}
+
+type field uint64
+
+func (f field) has(v int) bool {
+	return f&(1<<uint(v)) != 0
+}
+
+// cronSchedule adalah spesifikasi cron lima kolom: menit jam tanggal bulan hari.
+type cronSchedule struct {
+	minute, hour, dom, month, dow field
+	domAny, dowAny                bool
+}
+
+type everySchedule struct {
+	interval time.Duration
+}
+
+// Next dibulatkan ke kelipatan interval agar semua replika menghitung jadwal
+// yang sama untuk satu periode.
+func (e everySchedule) Next(t time.Time) time.Time {
+	return t.Truncate(e.interval).Add(e.interval)
+}
+
+var aliases = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse membaca spesifikasi cron lima kolom ("*/15 * * * *"), alias seperti
+// "@daily", atau interval tetap "@every 10m".
+func Parse(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "@every ") {
+		d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every ")))
+		if err != nil || d < time.Second {
+			return nil, fmt.Errorf("interval @every tidak valid: %q", spec)
+		}
+		return everySchedule{interval: d}, nil
+	}
+	if alias, ok := aliases[spec]; ok {
+		spec = alias
+	}
+
+	parts := strings.Fields(spec)
+	if len(parts) != 5 {
+		return nil, fmt.Errorf("spesifikasi cron harus terdiri dari 5 kolom: %q", spec)
+	}
+
+	var s cronSchedule
+	var err error
+	if s.minute, err = parseField(parts[0], 0, 59); err != nil {
+		return nil, fmt.Errorf("kolom menit: %w", err)
+	}
+	if s.hour, err = parseField(parts[1], 0, 23); err != nil {
+		return nil, fmt.Errorf("kolom jam: %w", err)
+	}
+	if s.dom, err = parseField(parts[2], 1, 31); err != nil {
+		return nil, fmt.Errorf("kolom tanggal: %w", err)
+	}
+	if s.month, err = parseField(parts[3], 1, 12); err != nil {
+		return nil, fmt.Errorf("kolom bulan: %w", err)
+	}
+	if s.dow, err = parseField(parts[4], 0, 7); err != nil {
+		return nil, fmt.Errorf("kolom hari: %w", err)
+	}
+	// Minggu boleh ditulis 0 atau 7.
+	if s.dow.has(7) {
+		s.dow |= 1
+	}
+	s.domAny = parts[2] == "*" || parts[2] == "?"
+	s.dowAny = parts[4] == "*" || parts[4] == "?"
+
+	return &s, nil
+}
+
+func parseField(expr string, min, max int) (field, error) {
+	var f field
+	for _, part := range strings.Split(expr, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("step tidak valid: %q", part)
+			}
+			step = n
+			part = part[:i]
+		}
+
+		lo, hi := min, max
+		switch {
+		case part == "*" || part == "?":
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			a, errA := strconv.Atoi(bounds[0])
+			b, errB := strconv.Atoi(bounds[1])
+			if errA != nil || errB != nil || a > b {
+				return 0, fmt.Errorf("rentang tidak valid: %q", part)
+			}
+			lo, hi = a, b
+		default:
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return 0, fmt.Errorf("nilai tidak valid: %q", part)
+			}
+			lo = n
+			if step == 1 {
+				hi = n
+			}
+		}
+
+		if lo < min || hi > max {
+			return 0, fmt.Errorf("nilai di luar rentang %d-%d: %q", min, max, part)
+		}
+		for v := lo; v <= hi; v += step {
+			f |= 1 << uint(v)
+		}
+	}
+	return f, nil
+}
+
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom.has(t.Day())
+	dowMatch := s.dow.has(int(t.Weekday()))
+	// Seperti cron standar: jika tanggal dan hari sama-sama dibatasi, cukup salah satu cocok.
+	if !s.domAny && !s.dowAny {
+		return domMatch || dowMatch
+	}
+	return domMatch && dowMatch
+}
+
+// Next mengembalikan menit berikutnya setelah t yang cocok dengan spesifikasi,
+// atau waktu nol jika tidak ada dalam lima tahun ke depan.
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if !s.month.has(int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.hour.has(t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.minute.has(t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
@@ -0,0 +1,333 @@
+// Package scheduler menjalankan job periodik di dalam proses server. Setiap
+// eksekusi dilindungi advisory lock Postgres sehingga beberapa replika tidak
+// menjalankan job yang sama bersamaan, dan riwayatnya disimpan di tabel job_runs.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/habbazettt/muraja-server/models"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrJobNotFound = errors.New("job tidak ditemukan")
+	ErrJobLocked   = errors.New("job sedang berjalan di instance lain")
+	// ErrJobSudahDijalankan berarti jadwal ini sudah dijalankan instance lain.
+	ErrJobSudahDijalankan = errors.New("jadwal job sudah dijalankan")
+)
+
+// JobFunc adalah pekerjaan yang dijalankan; string yang dikembalikan disimpan
+// sebagai ringkasan hasil.
+type JobFunc func(ctx context.Context) (string, error)
+
+type Job struct {
+	Name     string
+	Spec     string
+	Schedule Schedule
+	Run      JobFunc
+}
+
+// JobInfo adalah status job untuk ditampilkan ke admin.
+type JobInfo struct {
+	Name    string
+	Spec    string
+	NextRun time.Time
+	LastRun *models.JobRun
+}
+
+type Scheduler struct {
+	db   *gorm.DB
+	host string
+
+	mu      sync.RWMutex
+	jobs    map[string]*Job
+	nextRun map[string]time.Time
+	started bool
+}
+
+func New(db *gorm.DB) *Scheduler {
+	host, _ := os.Hostname()
+	return &Scheduler{
+		db:      db,
+		host:    host,
+		jobs:    make(map[string]*Job),
+		nextRun: make(map[string]time.Time),
+	}
+}
+
+// Register menambahkan job. Harus dipanggil sebelum Start.
+func (s *Scheduler) Register(name, spec string, run JobFunc) error {
+	schedule, err := Parse(spec)
+	if err != nil {
+		return fmt.Errorf("job %s: %w", name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return fmt.Errorf("job %s: scheduler sudah berjalan", name)
+	}
+	if _, ok := s.jobs[name]; ok {
+		return fmt.Errorf("job %s sudah terdaftar", name)
+	}
+	s.jobs[name] = &Job{Name: name, Spec: spec, Schedule: schedule, Run: run}
+	return nil
+}
+
+// Start menjalankan semua job sesuai jadwalnya sampai ctx dibatalkan.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	s.started = true
+	jobs := make([]*Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, job)
+	}
+	s.mu.Unlock()
+
+	s.tutupRunYatim(ctx)
+
+	for _, job := range jobs {
+		go s.loop(ctx, job)
+	}
+	logrus.WithField("jumlahJob", len(jobs)).Info("Scheduler job berjalan")
+}
+
+func (s *Scheduler) loop(ctx context.Context, job *Job) {
+	for {
+		next := job.Schedule.Next(time.Now().UTC())
+		if next.IsZero() {
+			logrus.WithField("job", job.Name).Warn("Job tidak punya jadwal berikutnya, dihentikan")
+			return
+		}
+
+		s.mu.Lock()
+		s.nextRun[job.Name] = next
+		s.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		_, err := s.execute(ctx, job, models.PemicuJadwal, &next, nil)
+		if err != nil && !errors.Is(err, ErrJobLocked) && !errors.Is(err, ErrJobSudahDijalankan) {
+			logrus.WithError(err).WithField("job", job.Name).Error("Gagal menjalankan job terjadwal")
+		}
+	}
+}
+
+// Trigger menjalankan job secara manual di background. Pemanggil menerima
+// catatan JobRun segera setelah lock didapat, tanpa menunggu job selesai.
+func (s *Scheduler) Trigger(name string) (*models.JobRun, error) {
+	s.mu.RLock()
+	job, ok := s.jobs[name]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+
+	type hasilMulai struct {
+		run *models.JobRun
+		err error
+	}
+	mulai := make(chan hasilMulai, 1)
+
+	go func() {
+		_, err := s.execute(context.Background(), job, models.PemicuManual, nil, func(run *models.JobRun) {
+			mulai <- hasilMulai{run: run}
+		})
+		if err != nil {
+			// Jika job sudah dimulai, kanal sudah terisi dan error ini hanya dicatat.
+			select {
+			case mulai <- hasilMulai{err: err}:
+			default:
+				logrus.WithError(err).WithField("job", name).Error("Job manual gagal")
+			}
+		}
+	}()
+
+	res := <-mulai
+	return res.run, res.err
+}
+
+// execute mengambil advisory lock pada satu koneksi, mencatat JobRun, lalu
+// menjalankan job. Lock hanya mencegah eksekusi yang tumpang tindih; untuk
+// eksekusi terjadwal, scheduledAt dicatat dengan unique index sehingga replika
+// yang timer-nya berbunyi setelah lock dilepas tidak menjalankan jadwal yang
+// sama lagi. onStart dipanggil setelah catatan JobRun dibuat.
+func (s *Scheduler) execute(ctx context.Context, job *Job, pemicu string, scheduledAt *time.Time, onStart func(*models.JobRun)) (*models.JobRun, error) {
+	var run *models.JobRun
+
+	err := s.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		key := lockKey(job.Name)
+
+		var locked bool
+		if err := conn.Raw("SELECT pg_try_advisory_lock(?)", key).Scan(&locked).Error; err != nil {
+			return err
+		}
+		if !locked {
+			return ErrJobLocked
+		}
+		defer unlock(conn, key)
+
+		run = &models.JobRun{
+			JobName:     job.Name,
+			ScheduledAt: scheduledAt,
+			Pemicu:      pemicu,
+			Status:      models.StatusJobBerjalan,
+			Host:        s.host,
+			StartedAt:   time.Now().UTC(),
+		}
+		res := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(run)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			run = nil
+			return ErrJobSudahDijalankan
+		}
+		if onStart != nil {
+			started := *run
+			onStart(&started)
+		}
+
+		log := logrus.WithFields(logrus.Fields{"job": job.Name, "runID": run.ID, "pemicu": pemicu})
+		log.Info("Job mulai dijalankan")
+
+		hasil, runErr := runSafely(ctx, job.Run)
+
+		finished := time.Now().UTC()
+		run.FinishedAt = &finished
+		run.DurasiMs = finished.Sub(run.StartedAt).Milliseconds()
+		run.Hasil = hasil
+		run.Status = models.StatusJobSukses
+		if runErr != nil {
+			run.Status = models.StatusJobGagal
+			run.Error = runErr.Error()
+			log.WithError(runErr).Error("Job gagal")
+		} else {
+			log.WithField("durasiMs", run.DurasiMs).Info("Job selesai")
+		}
+
+		return s.db.Save(run).Error
+	})
+
+	return run, err
+}
+
+// unlock melepas advisory lock dengan context tersendiri. Lock ini melekat pada
+// sesi koneksi, sehingga jika gagal dilepas karena ctx job sudah dibatalkan,
+// koneksi yang kembali ke pool akan terus memegang lock tersebut.
+func unlock(conn *gorm.DB, key int64) {
+	if err := conn.WithContext(context.Background()).Exec("SELECT pg_advisory_unlock(?)", key).Error; err != nil {
+		logrus.WithError(err).WithField("lockKey", key).Error("Gagal melepas advisory lock job")
+	}
+}
+
+// tutupRunYatim menandai catatan JobRun yang masih "berjalan" sebagai gagal
+// jika tidak ada instance yang memegang lock job tersebut, mis. karena proses
+// berhenti di tengah eksekusi.
+func (s *Scheduler) tutupRunYatim(ctx context.Context) {
+	var names []string
+	if err := s.db.Model(&models.JobRun{}).Where("status = ?", models.StatusJobBerjalan).
+		Distinct().Pluck("job_name", &names).Error; err != nil {
+		logrus.WithError(err).Error("Gagal memeriksa job yang masih berjalan")
+		return
+	}
+
+	for _, name := range names {
+		err := s.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+			key := lockKey(name)
+
+			var locked bool
+			if err := conn.Raw("SELECT pg_try_advisory_lock(?)", key).Scan(&locked).Error; err != nil {
+				return err
+			}
+			if !locked {
+				return nil
+			}
+			defer unlock(conn, key)
+
+			now := time.Now().UTC()
+			res := conn.Model(&models.JobRun{}).
+				Where("job_name = ? AND status = ?", name, models.StatusJobBerjalan).
+				Updates(map[string]interface{}{
+					"status":      models.StatusJobGagal,
+					"error":       "proses berhenti sebelum job selesai",
+					"finished_at": now,
+				})
+			if res.Error == nil && res.RowsAffected > 0 {
+				logrus.WithFields(logrus.Fields{"job": name, "jumlah": res.RowsAffected}).Warn("Catatan job yang terhenti ditandai gagal")
+			}
+			return res.Error
+		})
+		if err != nil {
+			logrus.WithError(err).WithField("job", name).Error("Gagal menutup catatan job yang terhenti")
+		}
+	}
+}
+
+func runSafely(ctx context.Context, fn JobFunc) (hasil string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn(ctx)
+}
+
+// lockKey menurunkan kunci advisory lock 64-bit dari nama job.
+func lockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("muraja-job:" + name))
+	return int64(h.Sum64())
+}
+
+// Jobs mengembalikan semua job beserta jadwal berikutnya dan eksekusi terakhir.
+func (s *Scheduler) Jobs() ([]JobInfo, error) {
+	s.mu.RLock()
+	infos := make([]JobInfo, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		next, ok := s.nextRun[job.Name]
+		if !ok {
+			next = job.Schedule.Next(time.Now().UTC())
+		}
+		infos = append(infos, JobInfo{Name: job.Name, Spec: job.Spec, NextRun: next})
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+
+	for i := range infos {
+		var last models.JobRun
+		err := s.db.Where("job_name = ?", infos[i].Name).Order("started_at DESC").First(&last).Error
+		if err == nil {
+			infos[i].LastRun = &last
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+	return infos, nil
+}
+
+// Has memeriksa apakah job dengan nama tersebut terdaftar.
+func (s *Scheduler) Has(name string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.jobs[name]
+	return ok
+}
@@ -1,18 +1,35 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/habbazettt/muraja-server/config"
 	"github.com/habbazettt/muraja-server/dto"
 	"github.com/habbazettt/muraja-server/models"
+	"github.com/habbazettt/muraja-server/notifier"
 	"github.com/habbazettt/muraja-server/utils"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type AuthService struct {
-	DB *gorm.DB
+	DB       *gorm.DB
+	Notifier notifier.Notifier
 }
 
+var errInvalidInvitation = errors.New("invalid invitation code")
+
+// batasKirimTokenReset membatasi pengiriman token reset yang berjalan di luar
+// siklus request.
+const batasKirimTokenReset = 30 * time.Second
+
 func (s *AuthService) Register(c *fiber.Ctx) error {
 	var req dto.RegisterRequest
 
@@ -33,15 +50,50 @@ func (s *AuthService) Register(c *fiber.Ctx) error {
 		return utils.ResponseError(c, fiber.StatusInternalServerError, "Failed to hash password", err.Error())
 	}
 
+	// Registrasi publik selalu menghasilkan akun "user"; role lain hanya bisa
+	// didapat melalui kode undangan yang dibuat admin.
 	user := models.User{
 		Nama:                 req.Nama,
 		Email:                req.Email,
 		Password:             hashedPassword,
-		UserType:             req.UserType,
+		UserType:             models.RoleUser,
 		IsDataMurojaahFilled: false,
 	}
 
-	if err := s.DB.Create(&user).Error; err != nil {
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		var invitation models.Invitation
+		if req.InviteCode != "" {
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("code_hash = ? AND used_at IS NULL AND expires_at > ?", utils.HashToken(req.InviteCode), time.Now()).
+				First(&invitation).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errInvalidInvitation
+				}
+				return err
+			}
+			if invitation.Email != nil && !strings.EqualFold(*invitation.Email, req.Email) {
+				return errInvalidInvitation
+			}
+			user.UserType = invitation.Role
+		}
+
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+
+		if req.InviteCode == "" {
+			return nil
+		}
+
+		now := time.Now()
+		return tx.Model(&invitation).Updates(models.Invitation{UsedAt: &now, UsedByID: &user.ID}).Error
+	})
+
+	if err != nil {
+		if errors.Is(err, errInvalidInvitation) {
+			logrus.Warn("Invalid invitation code used for email: ", req.Email)
+			return utils.ResponseError(c, fiber.StatusBadRequest, "Invalid or expired invitation code", nil)
+		}
 		logrus.WithError(err).Error("Failed to register user")
 		return utils.ResponseError(c, fiber.StatusInternalServerError, "Failed to register user", err.Error())
 	}
@@ -82,7 +134,13 @@ func (s *AuthService) Login(c *fiber.Ctx) error {
 		return utils.ResponseError(c, fiber.StatusUnauthorized, "Invalid Email or password", nil)
 	}
 
-	token, err := utils.GenerateToken(user.ID, user.UserType)
+	familyID, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to generate session family")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Failed to generate token", err.Error())
+	}
+
+	tokens, err := s.issueSession(s.DB, c, user, familyID)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to generate token")
 		return utils.ResponseError(c, fiber.StatusInternalServerError, "Failed to generate token", err.Error())
@@ -93,17 +151,180 @@ func (s *AuthService) Login(c *fiber.Ctx) error {
 	}).Info("User logged in successfully")
 
 	return utils.SuccessResponse(c, fiber.StatusOK, "Login successful", dto.AuthResponse{
-		Token: token,
+		Token:            tokens.Token,
+		ExpiresAt:        tokens.ExpiresAt,
+		RefreshToken:     tokens.RefreshToken,
+		RefreshExpiresAt: tokens.RefreshExpiresAt,
 		User: dto.UserResponse{
 			ID:                   user.ID,
 			Nama:                 user.Nama,
 			Email:                user.Email,
 			UserType:             user.UserType,
+			MushafLayout:         user.MushafLayout,
+			RolloverOtomatis:     user.RolloverOtomatis,
+			ZonaWaktu:            user.ZonaWaktu,
+			Lokasi:               toLokasiUserResponse(user),
 			IsDataMurojaahFilled: user.IsDataMurojaahFilled,
 		},
 	})
 }
 
+func (s *AuthService) RefreshToken(c *fiber.Ctx) error {
+	var req dto.RefreshTokenRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Invalid request body", err.Error())
+	}
+
+	if req.RefreshToken == "" {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Refresh token is required", nil)
+	}
+
+	var session models.Session
+	var tokens dto.TokenResponse
+	var reused bool
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("refresh_token_hash = ?", utils.HashToken(req.RefreshToken)).
+			First(&session).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+
+		// Refresh token yang sudah pernah dirotasi atau dicabut dipakai lagi:
+		// anggap bocor dan cabut seluruh family sesi tersebut. Transaksi tetap
+		// di-commit agar pencabutan tersimpan; respons 401 dikirim setelahnya.
+		if session.RotatedAt != nil || session.RevokedAt != nil {
+			reused = true
+			return revokeSessionFamily(tx, session.FamilyID, now)
+		}
+
+		if now.After(session.ExpiresAt) {
+			return gorm.ErrRecordNotFound
+		}
+
+		var user models.User
+		if err := tx.First(&user, session.UserID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&session).Update("rotated_at", now).Error; err != nil {
+			return err
+		}
+
+		var err error
+		tokens, err = s.issueSession(tx, c, user, session.FamilyID)
+		return err
+	})
+
+	if err == nil && reused {
+		logrus.WithFields(logrus.Fields{
+			"user_id":   session.UserID,
+			"family_id": session.FamilyID,
+		}).Warn("Refresh token reuse detected, session family revoked")
+		return utils.ResponseError(c, fiber.StatusUnauthorized, "Refresh token has already been used, please login again", nil)
+	}
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logrus.Warn("Invalid or expired refresh token used")
+			return utils.ResponseError(c, fiber.StatusUnauthorized, "Invalid or expired refresh token", nil)
+		}
+		logrus.WithError(err).Error("Failed to refresh token")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Failed to refresh token", err.Error())
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"user_id": session.UserID,
+	}).Info("Token refreshed successfully")
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Token refreshed successfully", tokens)
+}
+
+func (s *AuthService) Logout(c *fiber.Ctx) error {
+	userClaims, ok := c.Locals("user").(*utils.Claims)
+	if !ok || userClaims == nil {
+		logrus.Warn("Unauthorized access: Missing user claims")
+		return utils.ResponseError(c, fiber.StatusUnauthorized, "Unauthorized", nil)
+	}
+
+	var session models.Session
+	if err := s.DB.Where("access_token_jti = ?", userClaims.RegisteredClaims.ID).First(&session).Error; err != nil {
+		logrus.WithError(err).Warn("Session not found on logout")
+		return utils.ResponseError(c, fiber.StatusUnauthorized, "Session not found", nil)
+	}
+
+	if err := revokeSessionFamily(s.DB, session.FamilyID, time.Now()); err != nil {
+		logrus.WithError(err).Error("Failed to revoke session")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Failed to logout", err.Error())
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"user_id":   userClaims.ID,
+		"family_id": session.FamilyID,
+	}).Info("User logged out successfully")
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Logout successful", nil)
+}
+
+// issueSession membuat access token baru beserta refresh token yang tersimpan
+// sebagai baris Session dalam family yang diberikan.
+func (s *AuthService) issueSession(tx *gorm.DB, c *fiber.Ctx, user models.User, familyID string) (dto.TokenResponse, error) {
+	jti, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		return dto.TokenResponse{}, err
+	}
+
+	refreshToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return dto.TokenResponse{}, err
+	}
+
+	now := time.Now()
+	accessTTL := config.GetEnvDuration("ACCESS_TOKEN_TTL", 15*time.Minute)
+	refreshTTL := config.GetEnvDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour)
+
+	accessToken, err := utils.GenerateToken(user.ID, user.UserType, user.TokenVersion, jti, accessTTL)
+	if err != nil {
+		return dto.TokenResponse{}, err
+	}
+
+	session := models.Session{
+		UserID:           user.ID,
+		FamilyID:         familyID,
+		RefreshTokenHash: utils.HashToken(refreshToken),
+		AccessTokenJTI:   jti,
+		UserAgent:        truncate(c.Get(fiber.HeaderUserAgent), 255),
+		IPAddress:        c.IP(),
+		ExpiresAt:        now.Add(refreshTTL),
+	}
+	if err := tx.Create(&session).Error; err != nil {
+		return dto.TokenResponse{}, err
+	}
+
+	return dto.TokenResponse{
+		Token:            accessToken,
+		ExpiresAt:        now.Add(accessTTL),
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: session.ExpiresAt,
+	}, nil
+}
+
+func revokeSessionFamily(tx *gorm.DB, familyID string, now time.Time) error {
+	return tx.Model(&models.Session{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", now).Error
+}
+
+func truncate(value string, max int) string {
+	if len(value) > max {
+		return value[:max]
+	}
+	return value
+}
+
 func (s *AuthService) ForgotPassword(c *fiber.Ctx) error {
 	var req dto.ForgotPasswordRequest
 
@@ -111,8 +332,76 @@ func (s *AuthService) ForgotPassword(c *fiber.Ctx) error {
 		return utils.ResponseError(c, fiber.StatusBadRequest, "Invalid request body", err.Error())
 	}
 
-	if req.Email == "" {
-		return utils.ResponseError(c, fiber.StatusBadRequest, "Email is required", nil)
+	if req.Email == "" || !utils.IsValidEmail(req.Email) {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "A valid email is required", nil)
+	}
+
+	// Respons selalu sama agar endpoint ini tidak bisa dipakai untuk menebak email yang terdaftar,
+	// termasuk saat pembuatan atau pengiriman token gagal. Kegagalan hanya dicatat di log.
+	const genericMessage = "If the email is registered, a password reset link has been sent"
+
+	var user models.User
+	if err := s.DB.Where("email = ?", req.Email).First(&user).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logrus.WithError(err).Error("Failed to look up user for password reset")
+		} else {
+			logrus.Warn("Password reset requested for unknown email")
+		}
+		return utils.SuccessResponse(c, fiber.StatusOK, genericMessage, nil)
+	}
+
+	// Token dibuat dan dikirim di latar belakang agar waktu respons untuk email
+	// terdaftar sama dengan email yang tidak terdaftar.
+	go s.kirimTokenReset(user)
+
+	return utils.SuccessResponse(c, fiber.StatusOK, genericMessage, nil)
+}
+
+// kirimTokenReset membuat token reset password untuk user lalu mengirimkannya.
+// Kegagalan hanya dicatat di log karena respons ForgotPassword sudah dikirim.
+func (s *AuthService) kirimTokenReset(user models.User) {
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to generate password reset token")
+		return
+	}
+
+	ttl := config.GetEnvDuration("PASSWORD_RESET_TTL", 30*time.Minute)
+	resetToken := models.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := s.DB.Create(&resetToken).Error; err != nil {
+		logrus.WithError(err).WithField("user_id", user.ID).Error("Failed to store password reset token")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), batasKirimTokenReset)
+	defer cancel()
+	if err := s.notifier().Send(ctx, passwordResetMessage(user, token, ttl)); err != nil {
+		logrus.WithError(err).WithField("user_id", user.ID).Error("Failed to deliver password reset token")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"user_id":    user.ID,
+		"expires_at": resetToken.ExpiresAt,
+	}).Info("Password reset token issued")
+}
+
+func (s *AuthService) ResetPassword(c *fiber.Ctx) error {
+	var req dto.ResetPasswordRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Invalid request body", err.Error())
+	}
+
+	if req.Token == "" {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Token is required", nil)
+	}
+	if len(req.NewPassword) < 6 {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Password must be at least 6 characters", nil)
 	}
 
 	hashed, err := utils.HashPassword(req.NewPassword)
@@ -120,21 +409,76 @@ func (s *AuthService) ForgotPassword(c *fiber.Ctx) error {
 		return utils.ResponseError(c, fiber.StatusInternalServerError, "Failed to hash password", err.Error())
 	}
 
-	if req.Email != "" {
-		var user models.User
-		if err := s.DB.Where("email = ?", req.Email).First(&user).Error; err != nil {
-			return utils.ResponseError(c, fiber.StatusNotFound, "User not found", nil)
+	var resetToken models.PasswordResetToken
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", utils.HashToken(req.Token), now).
+			First(&resetToken).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.User{}).Where("id = ?", resetToken.UserID).Updates(map[string]interface{}{
+			"password":      hashed,
+			"token_version": gorm.Expr("token_version + 1"),
+		}).Error; err != nil {
+			return err
+		}
+
+		// Token yang dipakai beserta semua token lain milik user yang masih aktif ikut dinonaktifkan.
+		if err := tx.Model(&models.PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", resetToken.UserID).
+			Update("used_at", now).Error; err != nil {
+			return err
 		}
-		user.Password = hashed
-		s.DB.Save(&user)
-		return utils.SuccessResponse(c, fiber.StatusOK, "Password updated successfully", map[string]interface{}{
-			"email":        user.Email,
-			"new_password": req.NewPassword, // Kembalikan password baru
-		})
+
+		return tx.Model(&models.Session{}).
+			Where("user_id = ? AND revoked_at IS NULL", resetToken.UserID).
+			Update("revoked_at", now).Error
+	})
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logrus.Warn("Invalid or expired password reset token used")
+			return utils.ResponseError(c, fiber.StatusBadRequest, "Invalid or expired token", nil)
+		}
+		logrus.WithError(err).Error("Failed to reset password")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Failed to reset password", err.Error())
+	}
+
+	utils.InvalidateCachedUser(resetToken.UserID)
+
+	logrus.WithFields(logrus.Fields{
+		"user_id": resetToken.UserID,
+	}).Info("Password reset successfully")
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Password updated successfully", nil)
+}
+
+func (s *AuthService) notifier() notifier.Notifier {
+	if s.Notifier == nil {
+		return notifier.NewFromEnv()
+	}
+	return s.Notifier
+}
+
+func passwordResetMessage(user models.User, token string, ttl time.Duration) notifier.Message {
+	link := token
+	if baseURL := os.Getenv("PASSWORD_RESET_URL"); baseURL != "" {
+		link = fmt.Sprintf("%s?token=%s", baseURL, token)
 	}
 
-	return utils.ResponseError(c, fiber.StatusBadRequest, "Invalid request", nil)
+	return notifier.Message{
+		To:      user.Email,
+		Subject: "Reset password Muraja",
+		Body: fmt.Sprintf(
+			"Assalamu'alaikum %s,\n\nGunakan tautan/token berikut untuk mengatur ulang password Anda:\n%s\n\nToken berlaku selama %s dan hanya dapat digunakan satu kali. Abaikan pesan ini jika Anda tidak memintanya.",
+			user.Nama, link, ttl,
+		),
+	}
 }
+
 func (s *AuthService) GetCurrentUser(c *fiber.Ctx) error {
 	userClaims, ok := c.Locals("user").(*utils.Claims)
 	if !ok || userClaims == nil {
@@ -155,6 +499,10 @@ func (s *AuthService) GetCurrentUser(c *fiber.Ctx) error {
 		Nama:                 user.Nama,
 		Email:                user.Email,
 		UserType:             user.UserType,
+		MushafLayout:         user.MushafLayout,
+		RolloverOtomatis:     user.RolloverOtomatis,
+		ZonaWaktu:            user.ZonaWaktu,
+		Lokasi:               toLokasiUserResponse(user),
 		IsDataMurojaahFilled: user.IsDataMurojaahFilled,
 	}
 
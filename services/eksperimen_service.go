@@ -0,0 +1,454 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/habbazettt/muraja-server/config"
+	"github.com/habbazettt/muraja-server/dto"
+	"github.com/habbazettt/muraja-server/models"
+	"github.com/habbazettt/muraja-server/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	errEksperimenTidakDitemukan = errors.New("eksperimen tidak ditemukan")
+	errEksperimenLainBerjalan   = errors.New("eksperimen lain sedang berjalan")
+	errEksperimenSudahSelesai   = errors.New("eksperimen sudah selesai dan tidak dapat dijalankan lagi")
+)
+
+type EksperimenService struct {
+	DB *gorm.DB
+}
+
+// modelPerVersi menyimpan model yang dipakai varian eksperimen. Isi sebuah
+// versi tidak pernah berubah sehingga aman disimpan selama proses berjalan.
+var modelPerVersi sync.Map
+
+func muatModelVersi(db *gorm.DB, versiID uint) (*config.Model, error) {
+	if aktif := config.ActiveModel(); aktif.VersiID == versiID {
+		return aktif, nil
+	}
+	if m, ok := modelPerVersi.Load(versiID); ok {
+		return m.(*config.Model), nil
+	}
+
+	model, err := muatModel(db, versiID)
+	if err != nil {
+		return nil, err
+	}
+	modelPerVersi.Store(versiID, model)
+	return model, nil
+}
+
+// pilihVarian membagi user ke varian secara deterministik: hash dari ID
+// eksperimen dan ID user dipetakan ke rentang bobot kumulatif varian.
+func pilihVarian(eksperimenID, userID uint, varian []models.VarianEksperimen) models.VarianEksperimen {
+	urut := append([]models.VarianEksperimen(nil), varian...)
+	sort.Slice(urut, func(i, j int) bool { return urut[i].ID < urut[j].ID })
+
+	var total uint32
+	for _, v := range urut {
+		total += uint32(v.Bobot)
+	}
+
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%d:%d", eksperimenID, userID)
+	titik := h.Sum32() % total
+
+	for _, v := range urut {
+		if titik < uint32(v.Bobot) {
+			return v
+		}
+		titik -= uint32(v.Bobot)
+	}
+	return urut[len(urut)-1]
+}
+
+// varianUser mengembalikan varian user pada eksperimen yang sedang berjalan,
+// membuat penugasannya jika belum ada. Hasilnya nil jika tidak ada eksperimen.
+func varianUser(db *gorm.DB, userID uint) (*models.VarianEksperimen, error) {
+	var eksperimen models.Eksperimen
+	err := db.Preload("Varian").Where("aktif = ?", true).Order("id DESC").First(&eksperimen).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && len(eksperimen.Varian) == 0) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dipilih := pilihVarian(eksperimen.ID, userID, eksperimen.Varian)
+	penugasan := models.PenugasanEksperimen{EksperimenID: eksperimen.ID, UserID: userID, VarianID: dipilih.ID}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&penugasan).Error; err != nil {
+		return nil, err
+	}
+
+	// Penugasan yang sudah ada tetap dipakai walaupun bobot varian berubah.
+	if err := db.Where("eksperimen_id = ? AND user_id = ?", eksperimen.ID, userID).First(&penugasan).Error; err != nil {
+		return nil, err
+	}
+	for i := range eksperimen.Varian {
+		if eksperimen.Varian[i].ID == penugasan.VarianID {
+			return &eksperimen.Varian[i], nil
+		}
+	}
+	return nil, fmt.Errorf("varian %d tidak ditemukan", penugasan.VarianID)
+}
+
+// modelUntukUser memilih model rekomendasi untuk user: model varian jika user
+// mengikuti eksperimen, selain itu model yang sedang aktif. Jika varian gagal
+// ditentukan, model aktif tetap dikembalikan bersama error. Jika model varian
+// gagal dimuat, model nil dikembalikan karena penugasan user sudah tersimpan
+// dan rekomendasi dari model lain akan mengotori laporan eksperimen.
+func modelUntukUser(db *gorm.DB, userID uint) (*config.Model, *models.VarianEksperimen, error) {
+	varian, err := varianUser(db, userID)
+	if err != nil || varian == nil {
+		return config.ActiveModel(), nil, err
+	}
+	if varian.VersiID == nil {
+		return config.ActiveModel(), varian, nil
+	}
+
+	model, err := muatModelVersi(db, *varian.VersiID)
+	if err != nil {
+		return nil, varian, fmt.Errorf("gagal memuat model versi %d untuk varian %d: %w", *varian.VersiID, varian.ID, err)
+	}
+	return model, varian, nil
+}
+
+func toEksperimenResponse(e models.Eksperimen) dto.EksperimenResponse {
+	response := dto.EksperimenResponse{
+		ID:          e.ID,
+		Nama:        e.Nama,
+		Deskripsi:   e.Deskripsi,
+		Aktif:       e.Aktif,
+		MulaiPada:   e.MulaiPada,
+		SelesaiPada: e.SelesaiPada,
+		CreatedByID: e.CreatedByID,
+		Varian:      make([]dto.VarianEksperimenResponse, len(e.Varian)),
+		CreatedAt:   e.CreatedAt,
+	}
+	for i, v := range e.Varian {
+		response.Varian[i] = dto.VarianEksperimenResponse{ID: v.ID, Nama: v.Nama, VersiID: v.VersiID, Bobot: v.Bobot}
+	}
+	return response
+}
+
+func (s *EksperimenService) ambilEksperimen(c *fiber.Ctx) (*models.Eksperimen, error) {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return nil, newErrRespons(fiber.StatusBadRequest, "ID eksperimen tidak valid", nil)
+	}
+
+	var eksperimen models.Eksperimen
+	if err := s.DB.Preload("Varian", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).First(&eksperimen, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, newErrRespons(fiber.StatusNotFound, errEksperimenTidakDitemukan.Error(), nil)
+		}
+		return nil, newErrRespons(fiber.StatusInternalServerError, "Gagal mengambil eksperimen", err.Error())
+	}
+	return &eksperimen, nil
+}
+
+func (s *EksperimenService) CreateEksperimen(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(*utils.Claims)
+	if !ok || claims == nil {
+		return utils.ResponseError(c, fiber.StatusUnauthorized, "Unauthorized: Token tidak valid atau tidak ada", nil)
+	}
+
+	log := logrus.WithFields(logrus.Fields{
+		"handler": "CreateEksperimen",
+		"userID":  claims.ID,
+	})
+
+	var req dto.CreateEksperimenRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.WithError(err).Error("Gagal parsing body request")
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Request body tidak valid", err.Error())
+	}
+	if req.Nama == "" {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Nama eksperimen wajib diisi", nil)
+	}
+	if len(req.Varian) < 2 {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Eksperimen membutuhkan minimal dua varian", nil)
+	}
+
+	eksperimen := models.Eksperimen{
+		Nama:        req.Nama,
+		Deskripsi:   req.Deskripsi,
+		CreatedByID: claims.ID,
+	}
+	namaVarian := make(map[string]bool, len(req.Varian))
+	for _, v := range req.Varian {
+		if v.Nama == "" || namaVarian[v.Nama] {
+			return utils.ResponseError(c, fiber.StatusBadRequest, "Nama varian wajib diisi dan tidak boleh sama", nil)
+		}
+		namaVarian[v.Nama] = true
+
+		if v.Bobot < 0 {
+			return utils.ResponseError(c, fiber.StatusBadRequest, "Bobot varian tidak boleh negatif", nil)
+		}
+		if v.Bobot == 0 {
+			v.Bobot = 1
+		}
+
+		if v.VersiID != nil {
+			var jumlah int64
+			if err := s.DB.Model(&models.QTableVersi{}).Where("id = ?", *v.VersiID).Count(&jumlah).Error; err != nil {
+				return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal memeriksa versi model", err.Error())
+			}
+			if jumlah == 0 {
+				return utils.ResponseError(c, fiber.StatusBadRequest, fmt.Sprintf("Versi model %d tidak ditemukan", *v.VersiID), nil)
+			}
+		}
+
+		eksperimen.Varian = append(eksperimen.Varian, models.VarianEksperimen{Nama: v.Nama, VersiID: v.VersiID, Bobot: v.Bobot})
+	}
+
+	var jumlah int64
+	if err := s.DB.Model(&models.Eksperimen{}).Where("nama = ?", req.Nama).Count(&jumlah).Error; err != nil {
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal memeriksa nama eksperimen", err.Error())
+	}
+	if jumlah > 0 {
+		return utils.ResponseError(c, fiber.StatusConflict, "Nama eksperimen sudah dipakai", nil)
+	}
+
+	if err := s.DB.Create(&eksperimen).Error; err != nil {
+		log.WithError(err).Error("Gagal membuat eksperimen")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal membuat eksperimen", err.Error())
+	}
+
+	log.WithField("eksperimenID", eksperimen.ID).Info("Eksperimen berhasil dibuat")
+	return utils.SuccessResponse(c, fiber.StatusCreated, "Eksperimen berhasil dibuat", toEksperimenResponse(eksperimen))
+}
+
+func (s *EksperimenService) GetAllEksperimen(c *fiber.Ctx) error {
+	var daftar []models.Eksperimen
+	if err := s.DB.Preload("Varian", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).Order("id DESC").Find(&daftar).Error; err != nil {
+		logrus.WithError(err).Error("Gagal mengambil daftar eksperimen")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal mengambil daftar eksperimen", err.Error())
+	}
+
+	response := make([]dto.EksperimenResponse, len(daftar))
+	for i, e := range daftar {
+		response[i] = toEksperimenResponse(e)
+	}
+	return utils.SuccessResponse(c, fiber.StatusOK, "Daftar eksperimen berhasil diambil", response)
+}
+
+func (s *EksperimenService) GetEksperimenByID(c *fiber.Ctx) error {
+	eksperimen, err := s.ambilEksperimen(c)
+	if err != nil {
+		return responsError(c, err)
+	}
+	return utils.SuccessResponse(c, fiber.StatusOK, "Eksperimen berhasil diambil", toEksperimenResponse(*eksperimen))
+}
+
+// MulaiEksperimen menjalankan eksperimen. Hanya satu eksperimen yang boleh
+// berjalan pada satu waktu.
+func (s *EksperimenService) MulaiEksperimen(c *fiber.Ctx) error {
+	eksperimen, err := s.ambilEksperimen(c)
+	if err != nil {
+		return responsError(c, err)
+	}
+
+	log := logrus.WithFields(logrus.Fields{
+		"handler":      "MulaiEksperimen",
+		"eksperimenID": eksperimen.ID,
+	})
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", qTableLockKey).Error; err != nil {
+			return err
+		}
+		if eksperimen.SelesaiPada != nil {
+			return errEksperimenSudahSelesai
+		}
+
+		var berjalan int64
+		if err := tx.Model(&models.Eksperimen{}).Where("aktif = ? AND id <> ?", true, eksperimen.ID).Count(&berjalan).Error; err != nil {
+			return err
+		}
+		if berjalan > 0 {
+			return errEksperimenLainBerjalan
+		}
+
+		now := time.Now()
+		eksperimen.Aktif = true
+		if eksperimen.MulaiPada == nil {
+			eksperimen.MulaiPada = &now
+		}
+		return tx.Model(eksperimen).Updates(map[string]interface{}{
+			"aktif":      true,
+			"mulai_pada": eksperimen.MulaiPada,
+		}).Error
+	})
+	if err != nil {
+		if errors.Is(err, errEksperimenLainBerjalan) || errors.Is(err, errEksperimenSudahSelesai) {
+			return utils.ResponseError(c, fiber.StatusConflict, err.Error(), nil)
+		}
+		log.WithError(err).Error("Gagal menjalankan eksperimen")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal menjalankan eksperimen", err.Error())
+	}
+
+	log.Info("Eksperimen berhasil dijalankan")
+	return utils.SuccessResponse(c, fiber.StatusOK, "Eksperimen berhasil dijalankan", toEksperimenResponse(*eksperimen))
+}
+
+// HentikanEksperimen mengakhiri eksperimen. Rekomendasi berikutnya kembali
+// memakai versi model aktif untuk semua user.
+func (s *EksperimenService) HentikanEksperimen(c *fiber.Ctx) error {
+	eksperimen, err := s.ambilEksperimen(c)
+	if err != nil {
+		return responsError(c, err)
+	}
+	if !eksperimen.Aktif {
+		return utils.ResponseError(c, fiber.StatusConflict, "Eksperimen tidak sedang berjalan", nil)
+	}
+
+	now := time.Now()
+	eksperimen.Aktif = false
+	eksperimen.SelesaiPada = &now
+	if err := s.DB.Model(eksperimen).Updates(map[string]interface{}{
+		"aktif":        false,
+		"selesai_pada": now,
+	}).Error; err != nil {
+		logrus.WithError(err).WithField("eksperimenID", eksperimen.ID).Error("Gagal menghentikan eksperimen")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal menghentikan eksperimen", err.Error())
+	}
+
+	logrus.WithField("eksperimenID", eksperimen.ID).Info("Eksperimen berhasil dihentikan")
+	return utils.SuccessResponse(c, fiber.StatusOK, "Eksperimen berhasil dihentikan", toEksperimenResponse(*eksperimen))
+}
+
+// GetLaporanEksperimen membandingkan varian dari penerapan rekomendasi,
+// tingkat penyelesaian target murojaah sejak user masuk eksperimen, dan
+// penilaian EfektifitasJadwal.
+func (s *EksperimenService) GetLaporanEksperimen(c *fiber.Ctx) error {
+	eksperimen, err := s.ambilEksperimen(c)
+	if err != nil {
+		return responsError(c, err)
+	}
+
+	log := logrus.WithFields(logrus.Fields{
+		"handler":      "GetLaporanEksperimen",
+		"eksperimenID": eksperimen.ID,
+	})
+
+	laporan := make(map[uint]*dto.LaporanVarianResponse, len(eksperimen.Varian))
+	urutan := make([]uint, len(eksperimen.Varian))
+	for i, v := range eksperimen.Varian {
+		laporan[v.ID] = &dto.LaporanVarianResponse{VarianID: v.ID, Nama: v.Nama, VersiID: v.VersiID}
+		urutan[i] = v.ID
+	}
+
+	var pengguna []struct {
+		VarianID uint
+		Jumlah   int
+	}
+	if err := s.DB.Model(&models.PenugasanEksperimen{}).
+		Select("varian_id, COUNT(*) as jumlah").
+		Where("eksperimen_id = ?", eksperimen.ID).
+		Group("varian_id").Scan(&pengguna).Error; err != nil {
+		log.WithError(err).Error("Gagal menghitung peserta eksperimen")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal membuat laporan eksperimen", err.Error())
+	}
+	for _, p := range pengguna {
+		if l, ok := laporan[p.VarianID]; ok {
+			l.JumlahUser = p.Jumlah
+		}
+	}
+
+	var rekomendasi []struct {
+		VarianID   uint
+		Jumlah     int
+		Diterapkan int
+	}
+	if err := s.DB.Model(&models.JadwalRekomendasi{}).
+		Select("varian_id, COUNT(*) as jumlah, COUNT(diterapkan_pada) as diterapkan").
+		Where("eksperimen_id = ?", eksperimen.ID).
+		Group("varian_id").Scan(&rekomendasi).Error; err != nil {
+		log.WithError(err).Error("Gagal menghitung rekomendasi eksperimen")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal membuat laporan eksperimen", err.Error())
+	}
+	for _, r := range rekomendasi {
+		if l, ok := laporan[r.VarianID]; ok {
+			l.JumlahRekomendasi = r.Jumlah
+			l.JumlahDiterapkan = r.Diterapkan
+			if r.Jumlah > 0 {
+				l.TingkatPenerapan = float64(r.Diterapkan) / float64(r.Jumlah)
+			}
+		}
+	}
+
+	// Log harian dihitung sejak user masuk eksperimen sampai eksperimen selesai.
+	logQuery := s.DB.Table("penugasan_eksperimens AS p").
+		Select("p.varian_id, COALESCE(SUM(l.total_target_halaman), 0) as target, COALESCE(SUM(l.total_selesai_halaman), 0) as selesai").
+		Joins("JOIN log_harians AS l ON l.user_id = p.user_id AND l.tanggal >= DATE(p.created_at)").
+		Where("p.eksperimen_id = ?", eksperimen.ID)
+	if eksperimen.SelesaiPada != nil {
+		logQuery = logQuery.Where("l.tanggal <= ?", eksperimen.SelesaiPada.Format("2006-01-02"))
+	}
+	var penyelesaian []struct {
+		VarianID uint
+		Target   int
+		Selesai  int
+	}
+	if err := logQuery.Group("p.varian_id").Scan(&penyelesaian).Error; err != nil {
+		log.WithError(err).Error("Gagal menghitung penyelesaian murojaah eksperimen")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal membuat laporan eksperimen", err.Error())
+	}
+	for _, p := range penyelesaian {
+		if l, ok := laporan[p.VarianID]; ok {
+			l.TotalTargetHalaman = p.Target
+			l.TotalSelesaiHalaman = p.Selesai
+			if p.Target > 0 {
+				l.TingkatSelesai = float64(p.Selesai) / float64(p.Target)
+			}
+		}
+	}
+
+	// Penilaian hanya dihitung jika diberikan selama user mengikuti
+	// eksperimen, bukan penilaian lama atau sesudah eksperimen selesai.
+	efektifitasQuery := s.DB.Table("penugasan_eksperimens AS p").
+		Select("p.varian_id, COUNT(j.id) as jumlah, AVG(j.efektifitas_jadwal) as rata").
+		Joins("JOIN jadwal_personals AS j ON j.user_id = p.user_id AND j.efektifitas_jadwal > 0 AND j.efektifitas_dinilai_pada >= p.created_at").
+		Where("p.eksperimen_id = ?", eksperimen.ID)
+	if eksperimen.SelesaiPada != nil {
+		efektifitasQuery = efektifitasQuery.Where("j.efektifitas_dinilai_pada <= ?", *eksperimen.SelesaiPada)
+	}
+	var efektifitas []struct {
+		VarianID uint
+		Jumlah   int
+		Rata     float64
+	}
+	if err := efektifitasQuery.Group("p.varian_id").Scan(&efektifitas).Error; err != nil {
+		log.WithError(err).Error("Gagal menghitung efektivitas jadwal eksperimen")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal membuat laporan eksperimen", err.Error())
+	}
+	for _, e := range efektifitas {
+		if l, ok := laporan[e.VarianID]; ok && e.Jumlah > 0 {
+			rata := e.Rata
+			l.JumlahPenilaian = e.Jumlah
+			l.RataRataEfektifitas = &rata
+		}
+	}
+
+	response := dto.LaporanEksperimenResponse{
+		Eksperimen: toEksperimenResponse(*eksperimen),
+		Varian:     make([]dto.LaporanVarianResponse, len(urutan)),
+	}
+	for i, id := range urutan {
+		response.Varian[i] = *laporan[id]
+	}
+
+	log.Info("Laporan eksperimen berhasil dibuat")
+	return utils.SuccessResponse(c, fiber.StatusOK, "Laporan eksperimen berhasil diambil", response)
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/habbazettt/muraja-server/utils"
+)
+
+// errRespons adalah kegagalan dari helper handler yang sudah membawa status
+// HTTP dan pesan untuk klien.
+type errRespons struct {
+	Status int
+	Pesan  string
+	Detail interface{}
+}
+
+func (e *errRespons) Error() string {
+	return e.Pesan
+}
+
+func newErrRespons(status int, pesan string, detail interface{}) *errRespons {
+	return &errRespons{Status: status, Pesan: pesan, Detail: detail}
+}
+
+// responsError mengubah error dari helper menjadi respons error. Error selain
+// errRespons dianggap kesalahan server.
+func responsError(c *fiber.Ctx, err error) error {
+	var e *errRespons
+	if errors.As(err, &e) {
+		return utils.ResponseError(c, e.Status, e.Pesan, e.Detail)
+	}
+	return utils.ResponseError(c, fiber.StatusInternalServerError, "Terjadi kesalahan pada server", err.Error())
+}
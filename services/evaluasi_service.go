@@ -0,0 +1,350 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/habbazettt/muraja-server/config"
+	"github.com/habbazettt/muraja-server/dto"
+	"github.com/habbazettt/muraja-server/models"
+	"github.com/habbazettt/muraja-server/qlearning"
+	"github.com/habbazettt/muraja-server/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// distribusiKebijakan adalah peluang setiap aksi direkomendasikan pada state,
+// mengikuti alur GetRecommendation: nilai Q state atau state mirip, lalu
+// jadwal historis terbaik jika keduanya tidak ada.
+func distribusiKebijakan(model *config.Model, kebijakan qlearning.Kebijakan, state string, kunjungan map[string]int) (map[string]float64, map[string]float64) {
+	if nilaiQ, _ := nilaiQState(model, state); len(nilaiQ) > 0 {
+		return kebijakan.Distribusi(nilaiQ, kunjungan), nilaiQ
+	}
+	if len(model.HistoricalBest) > 0 {
+		return map[string]float64{model.HistoricalBest[0].Jadwal: 1}, nil
+	}
+	return map[string]float64{}, nil
+}
+
+// kunjunganSemuaState menghitung berapa kali setiap aksi sudah
+// direkomendasikan, per state.
+func kunjunganSemuaState(db *gorm.DB) (map[string]map[string]int, error) {
+	var rows []struct {
+		State             string
+		RekomendasiJadwal string
+		Jumlah            int
+	}
+	if err := db.Model(&models.JadwalRekomendasi{}).
+		Select("state, rekomendasi_jadwal, COUNT(*) as jumlah").
+		Group("state, rekomendasi_jadwal").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	kunjungan := make(map[string]map[string]int)
+	for _, r := range rows {
+		if kunjungan[r.State] == nil {
+			kunjungan[r.State] = make(map[string]int)
+		}
+		kunjungan[r.State][r.RekomendasiJadwal] = r.Jumlah
+	}
+	return kunjungan, nil
+}
+
+func rasio(a, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
+
+// evaluasiModel memutar ulang jadwal personal terhadap model dan kebijakan,
+// lalu mengestimasi nilai kebijakan dari rekomendasi yang sudah mendapat
+// umpan balik. Kunjungan UCB memakai jumlah saat ini, bukan saat rekomendasi
+// dibuat, sehingga estimasi untuk UCB bersifat perkiraan.
+func evaluasiModel(db *gorm.DB, model *config.Model, kebijakan qlearning.Kebijakan) (dto.EvaluasiModelResponse, error) {
+	hasil := dto.EvaluasiModelResponse{
+		VersiID:            model.VersiID,
+		Kebijakan:          kebijakan.Nama,
+		ParameterKebijakan: kebijakan.Parameter,
+	}
+
+	kunjungan, err := kunjunganSemuaState(db)
+	if err != nil {
+		return hasil, err
+	}
+
+	var jadwal []models.JadwalPersonal
+	if err := db.Order("user_id").Find(&jadwal).Error; err != nil {
+		return hasil, err
+	}
+
+	type statistikAksi struct{ dipakai, efektif int }
+	dataState := make(map[string]int)
+	dataAksi := make(map[string]map[string]*statistikAksi)
+	var peluangHit float64
+	rentang := model.QTable.RentangHafalan()
+	for _, j := range jadwal {
+		aksi := j.Jadwal.String()
+		state, ok := stateJadwalPersonal(j, rentang)
+		if aksi == "" || !ok {
+			hasil.Dilewati++
+			continue
+		}
+		hasil.JumlahData++
+		dataState[state]++
+
+		if dataAksi[state] == nil {
+			dataAksi[state] = make(map[string]*statistikAksi)
+		}
+		st := dataAksi[state][aksi]
+		if st == nil {
+			st = &statistikAksi{}
+			dataAksi[state][aksi] = st
+		}
+		st.dipakai++
+		efektif := j.EfektifitasJadwal >= batasEfektif
+		if efektif {
+			st.efektif++
+		}
+
+		if len(model.QTable[state]) == 0 {
+			continue
+		}
+		hasil.Cakupan.DataTercakup++
+		if efektif {
+			hasil.JumlahEfektif++
+			peluang, _ := distribusiKebijakan(model, kebijakan, state, kunjungan[state])
+			peluangHit += peluang[aksi]
+		}
+	}
+	if hasil.JumlahEfektif > 0 {
+		hitRate := peluangHit / float64(hasil.JumlahEfektif)
+		hasil.HitRate = &hitRate
+	}
+
+	hasil.Cakupan.StateDiData = len(dataState)
+	hasil.Cakupan.StateModel = len(model.QTable)
+	for state := range dataState {
+		if len(model.QTable[state]) > 0 {
+			hasil.Cakupan.StateTercakup++
+		}
+	}
+	hasil.Cakupan.StateTanpaData = hasil.Cakupan.StateModel - hasil.Cakupan.StateTercakup
+	hasil.Cakupan.PersentaseState = rasio(hasil.Cakupan.StateTercakup, hasil.Cakupan.StateDiData) * 100
+	hasil.Cakupan.PersentaseData = rasio(hasil.Cakupan.DataTercakup, hasil.JumlahData) * 100
+
+	semuaState := make(map[string]bool, len(model.QTable)+len(dataState))
+	for state := range model.QTable {
+		semuaState[state] = true
+	}
+	for state := range dataState {
+		semuaState[state] = true
+	}
+	urutState := make([]string, 0, len(semuaState))
+	for state := range semuaState {
+		urutState = append(urutState, state)
+	}
+	sort.Strings(urutState)
+
+	for _, state := range urutState {
+		peluang, nilaiQ := distribusiKebijakan(model, kebijakan, state, kunjungan[state])
+		distribusi := dto.DistribusiStateResponse{
+			State:        state,
+			DikenalModel: len(model.QTable[state]) > 0,
+			JumlahData:   dataState[state],
+		}
+
+		semuaAksi := make(map[string]bool)
+		for a := range peluang {
+			semuaAksi[a] = true
+		}
+		for a := range nilaiQ {
+			semuaAksi[a] = true
+		}
+		for a := range dataAksi[state] {
+			semuaAksi[a] = true
+		}
+		for a := range semuaAksi {
+			item := dto.DistribusiAksiResponse{Aksi: a, Peluang: peluang[a]}
+			if q, ok := nilaiQ[a]; ok {
+				item.QValue = &q
+			}
+			if st := dataAksi[state][a]; st != nil {
+				item.JumlahDipakai = st.dipakai
+				item.JumlahEfektif = st.efektif
+			}
+			distribusi.Aksi = append(distribusi.Aksi, item)
+		}
+		sort.Slice(distribusi.Aksi, func(i, j int) bool {
+			if distribusi.Aksi[i].Peluang != distribusi.Aksi[j].Peluang {
+				return distribusi.Aksi[i].Peluang > distribusi.Aksi[j].Peluang
+			}
+			return distribusi.Aksi[i].Aksi < distribusi.Aksi[j].Aksi
+		})
+		hasil.Distribusi = append(hasil.Distribusi, distribusi)
+	}
+
+	// Hanya rekomendasi yang diterapkan apa adanya yang dipakai, karena
+	// propensitas tercatat untuk jadwal utama, bukan alternatif pilihan user.
+	var sampel []struct {
+		State       string
+		Aksi        string
+		Propensitas float64
+		Reward      float64
+	}
+	if err := db.Table("jadwal_rekomendasis AS r").
+		Select("r.state, r.rekomendasi_jadwal as aksi, r.propensitas, u.reward").
+		Joins("JOIN umpan_balik_rekomendasis AS u ON u.rekomendasi_id = r.id").
+		Where("r.propensitas > 0 AND u.aksi = r.rekomendasi_jadwal").
+		Scan(&sampel).Error; err != nil {
+		return hasil, err
+	}
+
+	if n := len(sampel); n > 0 {
+		var totalReward, totalBobot, totalBerbobot float64
+		for _, s := range sampel {
+			peluang, _ := distribusiKebijakan(model, kebijakan, s.State, kunjungan[s.State])
+			bobot := peluang[s.Aksi] / s.Propensitas
+			totalReward += s.Reward
+			totalBobot += bobot
+			totalBerbobot += bobot * s.Reward
+		}
+
+		logging := totalReward / float64(n)
+		ips := totalBerbobot / float64(n)
+		hasil.IPS = dto.EstimasiIPSResponse{JumlahSampel: n, NilaiLogging: &logging, EstimasiIPS: &ips}
+		if totalBobot > 0 {
+			snips := totalBerbobot / totalBobot
+			hasil.IPS.EstimasiSNIPS = &snips
+		}
+	}
+
+	return hasil, nil
+}
+
+func formatOpsional(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', 6, 64)
+}
+
+// tulisEvaluasiCSV menulis ringkasan metrik sebagai pasangan metrik,nilai,
+// diikuti satu baris kosong dan tabel distribusi aksi per state.
+func tulisEvaluasiCSV(c *fiber.Ctx, hasil dto.EvaluasiModelResponse) error {
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="evaluasi-model-%d.csv"`, hasil.VersiID))
+
+	w := csv.NewWriter(c.Response().BodyWriter())
+	ringkasan := [][]string{
+		{"metrik", "nilai"},
+		{"versi_id", strconv.FormatUint(uint64(hasil.VersiID), 10)},
+		{"kebijakan", hasil.Kebijakan},
+		{"parameter_kebijakan", strconv.FormatFloat(hasil.ParameterKebijakan, 'f', -1, 64)},
+		{"jumlah_data", strconv.Itoa(hasil.JumlahData)},
+		{"dilewati", strconv.Itoa(hasil.Dilewati)},
+		{"jumlah_efektif", strconv.Itoa(hasil.JumlahEfektif)},
+		{"hit_rate", formatOpsional(hasil.HitRate)},
+		{"state_di_data", strconv.Itoa(hasil.Cakupan.StateDiData)},
+		{"state_tercakup", strconv.Itoa(hasil.Cakupan.StateTercakup)},
+		{"persentase_state", strconv.FormatFloat(hasil.Cakupan.PersentaseState, 'f', 2, 64)},
+		{"data_tercakup", strconv.Itoa(hasil.Cakupan.DataTercakup)},
+		{"persentase_data", strconv.FormatFloat(hasil.Cakupan.PersentaseData, 'f', 2, 64)},
+		{"state_model", strconv.Itoa(hasil.Cakupan.StateModel)},
+		{"state_tanpa_data", strconv.Itoa(hasil.Cakupan.StateTanpaData)},
+		{"ips_jumlah_sampel", strconv.Itoa(hasil.IPS.JumlahSampel)},
+		{"ips_nilai_logging", formatOpsional(hasil.IPS.NilaiLogging)},
+		{"ips_estimasi", formatOpsional(hasil.IPS.EstimasiIPS)},
+		{"ips_estimasi_snips", formatOpsional(hasil.IPS.EstimasiSNIPS)},
+		{},
+		{"state", "dikenal_model", "jumlah_data", "aksi", "q_value", "peluang", "jumlah_dipakai", "jumlah_efektif"},
+	}
+	if err := w.WriteAll(ringkasan); err != nil {
+		return err
+	}
+
+	for _, d := range hasil.Distribusi {
+		for _, a := range d.Aksi {
+			if err := w.Write([]string{
+				d.State,
+				strconv.FormatBool(d.DikenalModel),
+				strconv.Itoa(d.JumlahData),
+				a.Aksi,
+				formatOpsional(a.QValue),
+				strconv.FormatFloat(a.Peluang, 'f', 6, 64),
+				strconv.Itoa(a.JumlahDipakai),
+				strconv.Itoa(a.JumlahEfektif),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// EvaluasiModel mengevaluasi versi model (default: versi aktif) dengan
+// kebijakan rekomendasi yang sedang dipakai, atau kebijakan dari query
+// "kebijakan" dan "parameter". Query format=csv menghasilkan berkas CSV.
+func (s *QLearningService) EvaluasiModel(c *fiber.Ctx) error {
+	log := logrus.WithField("handler", "EvaluasiModel")
+
+	model := config.ActiveModel()
+	if v := c.Query("versi_id"); v != "" {
+		versiID, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return utils.ResponseError(c, fiber.StatusBadRequest, "ID versi tidak valid", nil)
+		}
+		var versi models.QTableVersi
+		if err := s.DB.First(&versi, versiID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return utils.ResponseError(c, fiber.StatusNotFound, errVersiQTableTidakDitemukan.Error(), nil)
+			}
+			return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal mengambil versi Q-table", err.Error())
+		}
+		if model, err = muatModelVersi(s.DB, versi.ID); err != nil {
+			log.WithError(err).Error("Gagal memuat versi model")
+			return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal memuat versi model", err.Error())
+		}
+	}
+
+	kebijakan, err := qlearning.KebijakanFromEnv()
+	if err != nil {
+		log.WithError(err).Warn("Kebijakan rekomendasi tidak valid, memakai greedy")
+	}
+	if nama := c.Query("kebijakan"); nama != "" {
+		var parameter *float64
+		if v := c.Query("parameter"); v != "" {
+			p, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return utils.ResponseError(c, fiber.StatusBadRequest, "Parameter kebijakan tidak valid", err.Error())
+			}
+			parameter = &p
+		}
+		if kebijakan, err = qlearning.NewKebijakan(nama, parameter); err != nil {
+			return utils.ResponseError(c, fiber.StatusBadRequest, "Kebijakan tidak valid", err.Error())
+		}
+	}
+
+	hasil, err := evaluasiModel(s.DB, model, kebijakan)
+	if err != nil {
+		log.WithError(err).Error("Gagal mengevaluasi model")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal mengevaluasi model", err.Error())
+	}
+
+	log.WithFields(logrus.Fields{
+		"versiID":    hasil.VersiID,
+		"kebijakan":  hasil.Kebijakan,
+		"jumlahData": hasil.JumlahData,
+	}).Info("Evaluasi model berhasil dibuat")
+
+	if c.Query("format") == "csv" {
+		return tulisEvaluasiCSV(c, hasil)
+	}
+	return utils.SuccessResponse(c, fiber.StatusOK, "Evaluasi model berhasil dibuat", hasil)
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/habbazettt/muraja-server/dto"
+	"github.com/habbazettt/muraja-server/models"
+	"github.com/habbazettt/muraja-server/mushaf"
+	"github.com/habbazettt/muraja-server/srs"
+	"github.com/habbazettt/muraja-server/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// bobotMurojaahBaru menentukan seberapa besar murojaah terakhir menggeser
+// kekuatan halaman (exponential moving average).
+const bobotMurojaahBaru = 0.4
+
+// skorTanpaRating dan kualitasTanpaRating dipakai untuk halaman yang
+// diselesaikan tanpa rating.
+const (
+	skorTanpaRating     = 0.8
+	kualitasTanpaRating = 4
+)
+
+var skorRating = map[models.RatingHalaman]float64{
+	models.RatingLancar:       1.0,
+	models.RatingKurangLancar: 0.6,
+	models.RatingBanyakSalah:  0.2,
+}
+
+// kualitasRating memetakan rating ke skala kualitas 0..5 milik SM-2.
+var kualitasRating = map[models.RatingHalaman]int{
+	models.RatingLancar:       5,
+	models.RatingKurangLancar: 3,
+	models.RatingBanyakSalah:  1,
+}
+
+var errRatingHalaman = errors.New("rating halaman tidak valid")
+
+func kartuHalaman(record models.KekuatanHalaman) srs.Kartu {
+	kartu := srs.Kartu{
+		EaseFactor:   record.EaseFactor,
+		IntervalHari: record.IntervalHari,
+		Repetisi:     record.Repetisi,
+	}
+	if record.JatuhTempo != nil {
+		kartu.JatuhTempo = *record.JatuhTempo
+	}
+	return kartu
+}
+
+// catatKekuatanHalaman memperbarui peta hafalan dan jadwal murojaah berikutnya
+// untuk halaman [awal, akhir] pada layout default.
+func catatKekuatanHalaman(tx *gorm.DB, userID uint, awal, akhir int, ratings map[int]models.RatingHalaman, waktu time.Time) error {
+	if awal > akhir {
+		return nil
+	}
+
+	var existing []models.KekuatanHalaman
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND halaman BETWEEN ? AND ?", userID, awal, akhir).
+		Find(&existing).Error; err != nil {
+		return err
+	}
+	perHalaman := make(map[int]models.KekuatanHalaman, len(existing))
+	for _, record := range existing {
+		perHalaman[record.Halaman] = record
+	}
+
+	records := make([]models.KekuatanHalaman, 0, akhir-awal+1)
+	for halaman := awal; halaman <= akhir; halaman++ {
+		skor, kualitas := skorTanpaRating, kualitasTanpaRating
+		rating, ok := ratings[halaman]
+		if ok {
+			skor, kualitas = skorRating[rating], kualitasRating[rating]
+		}
+
+		record, ok := perHalaman[halaman]
+		kartu := srs.Baru()
+		if ok {
+			record.Kekuatan = record.Kekuatan*(1-bobotMurojaahBaru) + skor*bobotMurojaahBaru
+			record.JumlahMurojaah++
+			kartu = kartuHalaman(record)
+		} else {
+			record = models.KekuatanHalaman{UserID: userID, Halaman: halaman, Kekuatan: skor, JumlahMurojaah: 1}
+		}
+		if rating != "" {
+			record.RatingTerakhir = rating
+		}
+		record.TerakhirMurojaah = waktu
+
+		kartu = srs.Review(kartu, kualitas, waktu)
+		record.EaseFactor = kartu.EaseFactor
+		record.IntervalHari = kartu.IntervalHari
+		record.Repetisi = kartu.Repetisi
+		record.JatuhTempo = &kartu.JatuhTempo
+
+		records = append(records, record)
+	}
+
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "halaman"}},
+		UpdateAll: true,
+	}).Create(&records).Error
+}
+
+// parseRatingHalaman memvalidasi rating dari request; setiap halaman harus
+// berada di dalam rentang yang baru diselesaikan.
+func parseRatingHalaman(req []dto.RatingHalamanRequest, awal, akhir int) (map[int]models.RatingHalaman, error) {
+	ratings := make(map[int]models.RatingHalaman, len(req))
+	for _, r := range req {
+		if !models.IsValidRatingHalaman(r.Rating) {
+			return nil, errRatingHalaman
+		}
+		if r.Halaman < awal || r.Halaman > akhir {
+			return nil, errRatingHalaman
+		}
+		ratings[r.Halaman] = models.RatingHalaman(r.Rating)
+	}
+	return ratings, nil
+}
+
+func levelKekuatan(record *models.KekuatanHalaman) string {
+	switch {
+	case record == nil || record.JumlahMurojaah == 0:
+		return "belum"
+	case record.Kekuatan < 0.5:
+		return "lemah"
+	case record.Kekuatan < 0.8:
+		return "sedang"
+	default:
+		return "kuat"
+	}
+}
+
+// GetPetaHafalan mengembalikan kekuatan hafalan per halaman untuk 30 juz,
+// siap dipakai sebagai heat-map.
+func (s *LogMurojaahService) GetPetaHafalan(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(*utils.Claims)
+	if !ok || claims == nil {
+		return utils.ResponseError(c, fiber.StatusUnauthorized, "Unauthorized: Token tidak valid atau tidak ada", nil)
+	}
+
+	targetUserID, err := s.resolveTargetUserID(c, claims)
+	if err != nil {
+		return targetUserErrorResponse(c, err)
+	}
+
+	log := logrus.WithFields(logrus.Fields{
+		"handler":     "GetPetaHafalan",
+		"userID":      targetUserID,
+		"requesterID": claims.ID,
+	})
+
+	layout := mushaf.Default()
+	var records []models.KekuatanHalaman
+	if err := s.DB.Where("user_id = ?", targetUserID).Find(&records).Error; err != nil {
+		log.WithError(err).Error("Gagal mengambil data kekuatan halaman")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal mengambil peta hafalan", err.Error())
+	}
+
+	perHalaman := make(map[int]*models.KekuatanHalaman, len(records))
+	for i := range records {
+		perHalaman[records[i].Halaman] = &records[i]
+	}
+
+	response := dto.PetaHafalanResponse{
+		UserID:       targetUserID,
+		MushafLayout: layout.Kode,
+		TotalHalaman: layout.TotalHalaman,
+		JumlahPerLevel: map[string]int{
+			"belum":  0,
+			"lemah":  0,
+			"sedang": 0,
+			"kuat":   0,
+		},
+		Juz: make([]dto.PetaHafalanJuz, 0, mushaf.JumlahJuz),
+	}
+
+	for juz := 1; juz <= mushaf.JumlahJuz; juz++ {
+		awal := layout.AwalHalamanJuz(juz)
+		jumlah := layout.JumlahHalamanJuz(juz)
+
+		juzDTO := dto.PetaHafalanJuz{
+			Juz:           juz,
+			JumlahHalaman: jumlah,
+			Halaman:       make([]dto.KekuatanHalamanResponse, jumlah),
+		}
+
+		totalKekuatan := 0.0
+		for i := 0; i < jumlah; i++ {
+			record := perHalaman[awal+i]
+			level := levelKekuatan(record)
+			response.JumlahPerLevel[level]++
+
+			halamanDTO := dto.KekuatanHalamanResponse{
+				Halaman:      awal + i,
+				HalamanDiJuz: i + 1,
+				Level:        level,
+			}
+			if record != nil {
+				terakhir := record.TerakhirMurojaah
+				halamanDTO.Kekuatan = record.Kekuatan
+				halamanDTO.JumlahMurojaah = record.JumlahMurojaah
+				halamanDTO.RatingTerakhir = string(record.RatingTerakhir)
+				halamanDTO.TerakhirMurojaah = &terakhir
+
+				totalKekuatan += record.Kekuatan
+				juzDTO.HalamanDimurojaah++
+			}
+			juzDTO.Halaman[i] = halamanDTO
+		}
+
+		if jumlah > 0 {
+			juzDTO.RataRataKekuatan = totalKekuatan / float64(jumlah)
+		}
+		response.HalamanDimurojaah += juzDTO.HalamanDimurojaah
+		response.Juz = append(response.Juz, juzDTO)
+	}
+
+	log.Info("Berhasil mengambil peta hafalan")
+	return utils.SuccessResponse(c, fiber.StatusOK, "Peta hafalan berhasil diambil", response)
+}
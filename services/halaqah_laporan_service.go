@@ -0,0 +1,309 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/habbazettt/muraja-server/dto"
+	"github.com/habbazettt/muraja-server/models"
+	"github.com/habbazettt/muraja-server/utils"
+	"github.com/sirupsen/logrus"
+)
+
+const maxRentangLaporanHari = 366
+
+var errRentangTanggalTidakValid = errors.New("rentang tanggal tidak valid, gunakan format YYYY-MM-DD dengan mulai <= akhir dan maksimal 366 hari")
+
+// parseRentangTanggal membaca query "mulai" dan "akhir". Default-nya adalah
+// tujuh hari terakhir termasuk hari ini.
+func parseRentangTanggal(c *fiber.Ctx) (time.Time, time.Time, error) {
+	now := time.Now().UTC()
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	start := end.AddDate(0, 0, -6)
+
+	if v := c.Query("akhir"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return time.Time{}, time.Time{}, errRentangTanggalTidakValid
+		}
+		end = t
+		start = end.AddDate(0, 0, -6)
+	}
+	if v := c.Query("mulai"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return time.Time{}, time.Time{}, errRentangTanggalTidakValid
+		}
+		start = t
+	}
+
+	if start.After(end) || end.Sub(start) > maxRentangLaporanHari*24*time.Hour {
+		return time.Time{}, time.Time{}, errRentangTanggalTidakValid
+	}
+	return start, end, nil
+}
+
+type logHarianRingkas struct {
+	UserID              uint
+	Tanggal             time.Time
+	TotalTargetHalaman  int
+	TotalSelesaiHalaman int
+}
+
+func (s *HalaqahService) logSantriHalaqah(halaqah *models.Halaqah, start, end time.Time) ([]logHarianRingkas, error) {
+	santriIDs := make([]uint, 0, len(halaqah.Anggota))
+	for _, anggota := range halaqah.Anggota {
+		if anggota.Peran == models.PeranSantri {
+			santriIDs = append(santriIDs, anggota.UserID)
+		}
+	}
+
+	var rows []logHarianRingkas
+	if len(santriIDs) == 0 {
+		return rows, nil
+	}
+
+	err := s.DB.Model(&models.LogHarian{}).
+		Select("user_id, tanggal, total_target_halaman, total_selesai_halaman").
+		Where("user_id IN ? AND tanggal BETWEEN ? AND ?", santriIDs, start, end).
+		Order("tanggal ASC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// hitungStreak mengembalikan streak yang masih berjalan pada tanggal akhir
+// (hari ini boleh belum aktif) dan streak terpanjang di dalam rentang.
+func hitungStreak(hariAktif map[string]bool, start, end time.Time) (int, int) {
+	terpanjang, berjalan := 0, 0
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if hariAktif[d.Format("2006-01-02")] {
+			berjalan++
+			if berjalan > terpanjang {
+				terpanjang = berjalan
+			}
+		} else {
+			berjalan = 0
+		}
+	}
+
+	saatIni := 0
+	d := end
+	if !hariAktif[d.Format("2006-01-02")] {
+		d = d.AddDate(0, 0, -1)
+	}
+	for ; !d.Before(start) && hariAktif[d.Format("2006-01-02")]; d = d.AddDate(0, 0, -1) {
+		saatIni++
+	}
+
+	return saatIni, terpanjang
+}
+
+func (s *HalaqahService) GetLeaderboard(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(*utils.Claims)
+	if !ok || claims == nil {
+		return utils.ResponseError(c, fiber.StatusUnauthorized, "Unauthorized: Token tidak valid atau tidak ada", nil)
+	}
+
+	halaqah, err := s.authorizeHalaqah(c, claims, false)
+	if err != nil {
+		return responsError(c, err)
+	}
+
+	log := logrus.WithFields(logrus.Fields{
+		"handler":   "GetLeaderboard",
+		"userID":    claims.ID,
+		"halaqahID": halaqah.ID,
+	})
+
+	start, end, err := parseRentangTanggal(c)
+	if err != nil {
+		return utils.ResponseError(c, fiber.StatusBadRequest, err.Error(), nil)
+	}
+
+	rows, err := s.logSantriHalaqah(halaqah, start, end)
+	if err != nil {
+		log.WithError(err).Error("Gagal mengambil log santri halaqah")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal memproses leaderboard", err.Error())
+	}
+
+	totalPerUser := make(map[uint]int)
+	hariAktifPerUser := make(map[uint]map[string]bool)
+	for _, row := range rows {
+		if row.TotalSelesaiHalaman <= 0 {
+			continue
+		}
+		totalPerUser[row.UserID] += row.TotalSelesaiHalaman
+		if hariAktifPerUser[row.UserID] == nil {
+			hariAktifPerUser[row.UserID] = make(map[string]bool)
+		}
+		hariAktifPerUser[row.UserID][row.Tanggal.Format("2006-01-02")] = true
+	}
+
+	// Mentor dan pengelola tetap melihat nama asli; sesama santri tidak.
+	bolehLihatNama := isPengelolaHalaqah(claims, halaqah)
+
+	entries := make([]dto.LeaderboardEntry, 0, len(halaqah.Anggota))
+	for _, anggota := range halaqah.Anggota {
+		if anggota.Peran != models.PeranSantri {
+			continue
+		}
+
+		hariAktif := hariAktifPerUser[anggota.UserID]
+		streakSaatIni, streakTerpanjang := hitungStreak(hariAktif, start, end)
+
+		entry := dto.LeaderboardEntry{
+			TotalSelesaiHalaman: totalPerUser[anggota.UserID],
+			TotalHariAktif:      len(hariAktif),
+			StreakSaatIni:       streakSaatIni,
+			StreakTerpanjang:    streakTerpanjang,
+		}
+		if entry.TotalHariAktif > 0 {
+			entry.RataRataPerHari = float64(entry.TotalSelesaiHalaman) / float64(entry.TotalHariAktif)
+		}
+
+		if anggota.SembunyikanNama && !bolehLihatNama && anggota.UserID != claims.ID {
+			entry.Nama = namaAnggotaTersembunyi
+			entry.Tersembunyi = true
+		} else {
+			userID := anggota.UserID
+			entry.UserID = &userID
+			if anggota.User != nil {
+				entry.Nama = anggota.User.Nama
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].TotalSelesaiHalaman != entries[j].TotalSelesaiHalaman {
+			return entries[i].TotalSelesaiHalaman > entries[j].TotalSelesaiHalaman
+		}
+		if entries[i].TotalHariAktif != entries[j].TotalHariAktif {
+			return entries[i].TotalHariAktif > entries[j].TotalHariAktif
+		}
+		return entries[i].StreakSaatIni > entries[j].StreakSaatIni
+	})
+
+	for i := range entries {
+		// Nilai yang sama persis mendapat peringkat yang sama.
+		if i > 0 && entries[i].TotalSelesaiHalaman == entries[i-1].TotalSelesaiHalaman &&
+			entries[i].TotalHariAktif == entries[i-1].TotalHariAktif &&
+			entries[i].StreakSaatIni == entries[i-1].StreakSaatIni {
+			entries[i].Peringkat = entries[i-1].Peringkat
+		} else {
+			entries[i].Peringkat = i + 1
+		}
+	}
+
+	log.WithField("jumlahSantri", len(entries)).Info("Berhasil menyusun leaderboard halaqah")
+	return utils.SuccessResponse(c, fiber.StatusOK, "Leaderboard halaqah berhasil diambil", dto.LeaderboardResponse{
+		HalaqahID:    halaqah.ID,
+		TanggalMulai: start.Format("02-01-2006"),
+		TanggalAkhir: end.Format("02-01-2006"),
+		Peringkat:    entries,
+	})
+}
+
+func (s *HalaqahService) GetProgressHalaqah(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(*utils.Claims)
+	if !ok || claims == nil {
+		return utils.ResponseError(c, fiber.StatusUnauthorized, "Unauthorized: Token tidak valid atau tidak ada", nil)
+	}
+
+	halaqah, err := s.authorizeHalaqah(c, claims, false)
+	if err != nil {
+		return responsError(c, err)
+	}
+
+	log := logrus.WithFields(logrus.Fields{
+		"handler":   "GetProgressHalaqah",
+		"userID":    claims.ID,
+		"halaqahID": halaqah.ID,
+	})
+
+	start, end, err := parseRentangTanggal(c)
+	if err != nil {
+		return utils.ResponseError(c, fiber.StatusBadRequest, err.Error(), nil)
+	}
+
+	rows, err := s.logSantriHalaqah(halaqah, start, end)
+	if err != nil {
+		log.WithError(err).Error("Gagal mengambil log santri halaqah")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal memproses progres halaqah", err.Error())
+	}
+
+	perTanggal := make(map[string]*dto.ProgressHarianHalaqah)
+	response := dto.ProgressHalaqahResponse{
+		HalaqahID:    halaqah.ID,
+		TanggalMulai: start.Format("02-01-2006"),
+		TanggalAkhir: end.Format("02-01-2006"),
+	}
+
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		response.PerHari = append(response.PerHari, dto.ProgressHarianHalaqah{Tanggal: d.Format("02-01-2006")})
+	}
+	for i := range response.PerHari {
+		perTanggal[response.PerHari[i].Tanggal] = &response.PerHari[i]
+	}
+
+	for _, row := range rows {
+		hari, ok := perTanggal[row.Tanggal.Format("02-01-2006")]
+		if !ok {
+			continue
+		}
+		hari.TotalTargetHalaman += row.TotalTargetHalaman
+		hari.TotalSelesaiHalaman += row.TotalSelesaiHalaman
+		if row.TotalSelesaiHalaman > 0 {
+			hari.JumlahAnggotaAktif++
+		}
+		response.TotalSelesaiHalaman += row.TotalSelesaiHalaman
+	}
+
+	for _, anggota := range halaqah.Anggota {
+		if anggota.Peran == models.PeranSantri {
+			response.JumlahSantri++
+		}
+	}
+
+	log.Info("Berhasil menyusun progres halaqah")
+	return utils.SuccessResponse(c, fiber.StatusOK, "Progres halaqah berhasil diambil", response)
+}
+
+func (s *HalaqahService) UpdatePrivasi(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(*utils.Claims)
+	if !ok || claims == nil {
+		return utils.ResponseError(c, fiber.StatusUnauthorized, "Unauthorized: Token tidak valid atau tidak ada", nil)
+	}
+
+	halaqahID, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "ID halaqah tidak valid", nil)
+	}
+
+	var req dto.UpdatePrivasiHalaqahRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Request body tidak valid", err.Error())
+	}
+
+	result := s.DB.Model(&models.HalaqahAnggota{}).
+		Where("halaqah_id = ? AND user_id = ?", halaqahID, claims.ID).
+		Update("sembunyikan_nama", req.SembunyikanNama)
+	if result.Error != nil {
+		logrus.WithError(result.Error).Error("Gagal memperbarui privasi anggota halaqah")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal memperbarui privasi", result.Error.Error())
+	}
+	if result.RowsAffected == 0 {
+		return utils.ResponseError(c, fiber.StatusNotFound, "Anda bukan anggota halaqah ini", nil)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"userID":          claims.ID,
+		"halaqahID":       halaqahID,
+		"sembunyikanNama": req.SembunyikanNama,
+	}).Info("Privasi anggota halaqah berhasil diperbarui")
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Privasi leaderboard berhasil diperbarui", req)
+}
@@ -0,0 +1,425 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/habbazettt/muraja-server/dto"
+	"github.com/habbazettt/muraja-server/models"
+	"github.com/habbazettt/muraja-server/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type HalaqahService struct {
+	DB *gorm.DB
+}
+
+// isMentorOfUser memeriksa apakah mentorID tercatat sebagai mentor di salah
+// satu halaqah tempat userID menjadi santri.
+func isMentorOfUser(db *gorm.DB, mentorID, userID uint) (bool, error) {
+	var count int64
+	err := db.Table("halaqah_anggota AS mentor").
+		Joins("JOIN halaqah_anggota AS santri ON santri.halaqah_id = mentor.halaqah_id").
+		Where("mentor.user_id = ? AND mentor.peran = ?", mentorID, models.PeranMentor).
+		Where("santri.user_id = ? AND santri.peran = ?", userID, models.PeranSantri).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// peranDiHalaqah mengembalikan peran user di halaqah, atau string kosong jika
+// user bukan anggota.
+func peranDiHalaqah(db *gorm.DB, halaqahID, userID uint) (models.PeranHalaqah, error) {
+	var anggota models.HalaqahAnggota
+	err := db.Where("halaqah_id = ? AND user_id = ?", halaqahID, userID).First(&anggota).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	return anggota.Peran, err
+}
+
+// isPengelolaHalaqah bernilai true untuk admin dan mentor halaqah tersebut.
+// Anggota halaqah harus sudah dimuat.
+func isPengelolaHalaqah(claims *utils.Claims, halaqah *models.Halaqah) bool {
+	if utils.HasPermission(claims.Role, utils.PermHalaqahManage) {
+		return true
+	}
+	for _, anggota := range halaqah.Anggota {
+		if anggota.UserID == claims.ID && anggota.Peran == models.PeranMentor {
+			return true
+		}
+	}
+	return false
+}
+
+// tampilanAnggota menentukan seberapa banyak data anggota yang disertakan
+// dalam respons halaqah.
+type tampilanAnggota int
+
+const (
+	tanpaAnggota tampilanAnggota = iota
+	// anggotaTerbatas untuk santri: hanya nama dan peran sesama anggota, dengan
+	// nama anggota yang memilih SembunyikanNama disamarkan.
+	anggotaTerbatas
+	// anggotaLengkap untuk mentor dan pengelola, termasuk email.
+	anggotaLengkap
+)
+
+// namaAnggotaTersembunyi menggantikan nama anggota yang memilih
+// SembunyikanNama ketika dilihat sesama santri.
+const namaAnggotaTersembunyi = "Anggota Halaqah"
+
+// toHalaqahResponse menyusun respons halaqah untuk peminta dengan ID
+// pemintaID; nama peminta sendiri tidak pernah disamarkan.
+func toHalaqahResponse(halaqah models.Halaqah, tampilan tampilanAnggota, pemintaID uint) dto.HalaqahResponse {
+	response := dto.HalaqahResponse{
+		ID:          halaqah.ID,
+		Nama:        halaqah.Nama,
+		Deskripsi:   halaqah.Deskripsi,
+		CreatedByID: halaqah.CreatedByID,
+		CreatedAt:   halaqah.CreatedAt,
+	}
+
+	for _, anggota := range halaqah.Anggota {
+		if anggota.Peran == models.PeranMentor {
+			response.JumlahMentor++
+		} else {
+			response.JumlahSantri++
+		}
+
+		if tampilan == tanpaAnggota {
+			continue
+		}
+
+		item := dto.AnggotaHalaqahResponse{
+			Peran:           string(anggota.Peran),
+			SembunyikanNama: anggota.SembunyikanNama,
+			Sejak:           anggota.CreatedAt,
+		}
+		if tampilan == anggotaTerbatas && anggota.SembunyikanNama && anggota.UserID != pemintaID {
+			item.Nama = namaAnggotaTersembunyi
+			response.Anggota = append(response.Anggota, item)
+			continue
+		}
+
+		userID := anggota.UserID
+		item.UserID = &userID
+		if anggota.User != nil {
+			item.Nama = anggota.User.Nama
+			if tampilan == anggotaLengkap {
+				item.Email = anggota.User.Email
+			}
+		}
+		response.Anggota = append(response.Anggota, item)
+	}
+
+	return response
+}
+
+// authorizeHalaqah memuat halaqah dan memastikan requester boleh mengaksesnya.
+// Jika mentorOnly bernilai true, anggota biasa (santri) akan ditolak.
+func (s *HalaqahService) authorizeHalaqah(c *fiber.Ctx, claims *utils.Claims, mentorOnly bool) (*models.Halaqah, error) {
+	halaqahID, err := c.ParamsInt("id")
+	if err != nil {
+		return nil, newErrRespons(fiber.StatusBadRequest, "ID halaqah tidak valid", nil)
+	}
+
+	var halaqah models.Halaqah
+	if err := s.DB.Preload("Anggota.User").First(&halaqah, halaqahID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, newErrRespons(fiber.StatusNotFound, "Halaqah tidak ditemukan", nil)
+		}
+		return nil, newErrRespons(fiber.StatusInternalServerError, "Gagal mengambil halaqah", err.Error())
+	}
+
+	if utils.HasPermission(claims.Role, utils.PermHalaqahManage) {
+		return &halaqah, nil
+	}
+
+	peran, err := peranDiHalaqah(s.DB, halaqah.ID, claims.ID)
+	if err != nil {
+		return nil, newErrRespons(fiber.StatusInternalServerError, "Gagal memeriksa keanggotaan halaqah", err.Error())
+	}
+	if peran == "" || (mentorOnly && peran != models.PeranMentor) {
+		return nil, newErrRespons(fiber.StatusForbidden, "Anda tidak punya hak akses ke halaqah ini", nil)
+	}
+
+	return &halaqah, nil
+}
+
+func (s *HalaqahService) GetAllHalaqah(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(*utils.Claims)
+	if !ok || claims == nil {
+		return utils.ResponseError(c, fiber.StatusUnauthorized, "Unauthorized: Token tidak valid atau tidak ada", nil)
+	}
+
+	log := logrus.WithFields(logrus.Fields{
+		"handler": "GetAllHalaqah",
+		"userID":  claims.ID,
+	})
+
+	query := s.DB.Model(&models.Halaqah{}).Preload("Anggota")
+	if !utils.HasPermission(claims.Role, utils.PermHalaqahManage) {
+		query = query.Where("id IN (?)", s.DB.Model(&models.HalaqahAnggota{}).Select("halaqah_id").Where("user_id = ?", claims.ID))
+	}
+
+	var halaqahs []models.Halaqah
+	if err := query.Order("nama ASC").Find(&halaqahs).Error; err != nil {
+		log.WithError(err).Error("Gagal mengambil daftar halaqah")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal mengambil daftar halaqah", err.Error())
+	}
+
+	response := make([]dto.HalaqahResponse, len(halaqahs))
+	for i, halaqah := range halaqahs {
+		response[i] = toHalaqahResponse(halaqah, tanpaAnggota, claims.ID)
+	}
+
+	log.WithField("count", len(response)).Info("Berhasil mengambil daftar halaqah")
+	return utils.SuccessResponse(c, fiber.StatusOK, "Daftar halaqah berhasil diambil", response)
+}
+
+func (s *HalaqahService) CreateHalaqah(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(*utils.Claims)
+	if !ok || claims == nil {
+		return utils.ResponseError(c, fiber.StatusUnauthorized, "Unauthorized: Token tidak valid atau tidak ada", nil)
+	}
+
+	log := logrus.WithFields(logrus.Fields{
+		"handler": "CreateHalaqah",
+		"userID":  claims.ID,
+	})
+
+	var req dto.CreateHalaqahRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.WithError(err).Error("Gagal parsing body request")
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Request body tidak valid", err.Error())
+	}
+	if req.Nama == "" {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Nama halaqah wajib diisi", nil)
+	}
+
+	halaqah := models.Halaqah{
+		Nama:        req.Nama,
+		Deskripsi:   req.Deskripsi,
+		CreatedByID: claims.ID,
+	}
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&halaqah).Error; err != nil {
+			return err
+		}
+
+		// Ustadz yang membuat halaqah otomatis menjadi mentornya.
+		if claims.Role != models.RoleUstadz {
+			return nil
+		}
+		return tx.Create(&models.HalaqahAnggota{
+			HalaqahID: halaqah.ID,
+			UserID:    claims.ID,
+			Peran:     models.PeranMentor,
+		}).Error
+	})
+	if err != nil {
+		log.WithError(err).Error("Gagal membuat halaqah")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal membuat halaqah", err.Error())
+	}
+
+	s.DB.Preload("Anggota.User").First(&halaqah, halaqah.ID)
+
+	log.WithField("halaqahID", halaqah.ID).Info("Halaqah berhasil dibuat")
+	return utils.SuccessResponse(c, fiber.StatusCreated, "Halaqah berhasil dibuat", toHalaqahResponse(halaqah, anggotaLengkap, claims.ID))
+}
+
+func (s *HalaqahService) GetHalaqahByID(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(*utils.Claims)
+	if !ok || claims == nil {
+		return utils.ResponseError(c, fiber.StatusUnauthorized, "Unauthorized: Token tidak valid atau tidak ada", nil)
+	}
+
+	halaqah, err := s.authorizeHalaqah(c, claims, false)
+	if err != nil {
+		return responsError(c, err)
+	}
+
+	tampilan := anggotaTerbatas
+	if isPengelolaHalaqah(claims, halaqah) {
+		tampilan = anggotaLengkap
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Halaqah berhasil diambil", toHalaqahResponse(*halaqah, tampilan, claims.ID))
+}
+
+func (s *HalaqahService) UpdateHalaqah(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(*utils.Claims)
+	if !ok || claims == nil {
+		return utils.ResponseError(c, fiber.StatusUnauthorized, "Unauthorized: Token tidak valid atau tidak ada", nil)
+	}
+
+	halaqah, err := s.authorizeHalaqah(c, claims, true)
+	if err != nil {
+		return responsError(c, err)
+	}
+
+	log := logrus.WithFields(logrus.Fields{
+		"handler":   "UpdateHalaqah",
+		"userID":    claims.ID,
+		"halaqahID": halaqah.ID,
+	})
+
+	var req dto.UpdateHalaqahRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.WithError(err).Error("Gagal parsing body request")
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Request body tidak valid", err.Error())
+	}
+
+	updated := false
+	if req.Nama != nil && *req.Nama != "" && *req.Nama != halaqah.Nama {
+		halaqah.Nama = *req.Nama
+		updated = true
+	}
+	if req.Deskripsi != nil && *req.Deskripsi != halaqah.Deskripsi {
+		halaqah.Deskripsi = *req.Deskripsi
+		updated = true
+	}
+	if !updated {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Tidak ada data yang diubah", nil)
+	}
+
+	if err := s.DB.Model(halaqah).Select("nama", "deskripsi").Updates(halaqah).Error; err != nil {
+		log.WithError(err).Error("Gagal memperbarui halaqah")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal memperbarui halaqah", err.Error())
+	}
+
+	log.Info("Halaqah berhasil diperbarui")
+	return utils.SuccessResponse(c, fiber.StatusOK, "Halaqah berhasil diperbarui", toHalaqahResponse(*halaqah, anggotaLengkap, claims.ID))
+}
+
+func (s *HalaqahService) DeleteHalaqah(c *fiber.Ctx) error {
+	halaqahID, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "ID halaqah tidak valid", nil)
+	}
+
+	result := s.DB.Delete(&models.Halaqah{}, halaqahID)
+	if result.Error != nil {
+		logrus.WithError(result.Error).Error("Gagal menghapus halaqah")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal menghapus halaqah", result.Error.Error())
+	}
+	if result.RowsAffected == 0 {
+		return utils.ResponseError(c, fiber.StatusNotFound, "Halaqah tidak ditemukan", nil)
+	}
+
+	logrus.WithField("halaqahID", halaqahID).Info("Halaqah berhasil dihapus")
+	return utils.SuccessResponse(c, fiber.StatusOK, "Halaqah berhasil dihapus", nil)
+}
+
+// AddAnggota hanya terbuka bagi pengelola halaqah (admin). Keanggotaan santri
+// memberi mentor akses baca ke log dan statistiknya, sehingga mentor tidak
+// boleh menambahkan santri sendiri.
+func (s *HalaqahService) AddAnggota(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(*utils.Claims)
+	if !ok || claims == nil {
+		return utils.ResponseError(c, fiber.StatusUnauthorized, "Unauthorized: Token tidak valid atau tidak ada", nil)
+	}
+
+	halaqah, err := s.authorizeHalaqah(c, claims, true)
+	if err != nil {
+		return responsError(c, err)
+	}
+
+	log := logrus.WithFields(logrus.Fields{
+		"handler":   "AddAnggota",
+		"userID":    claims.ID,
+		"halaqahID": halaqah.ID,
+	})
+
+	var req dto.AddAnggotaHalaqahRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.WithError(err).Error("Gagal parsing body request")
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Request body tidak valid", err.Error())
+	}
+
+	peran := models.PeranHalaqah(req.Peran)
+	if peran != models.PeranSantri && peran != models.PeranMentor {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Peran harus 'santri' atau 'mentor'", nil)
+	}
+
+	var user models.User
+	if err := s.DB.First(&user, req.UserID).Error; err != nil {
+		return utils.ResponseError(c, fiber.StatusNotFound, "User tidak ditemukan", nil)
+	}
+	if peran == models.PeranMentor && user.UserType != models.RoleUstadz && user.UserType != models.RoleAdmin {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Mentor harus berperan ustadz atau admin", nil)
+	}
+
+	anggota := models.HalaqahAnggota{
+		HalaqahID: halaqah.ID,
+		UserID:    user.ID,
+		Peran:     peran,
+	}
+	result := s.DB.Where(models.HalaqahAnggota{HalaqahID: halaqah.ID, UserID: user.ID}).FirstOrCreate(&anggota)
+	if result.Error != nil {
+		log.WithError(result.Error).Error("Gagal menambahkan anggota halaqah")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal menambahkan anggota", result.Error.Error())
+	}
+	if result.RowsAffected == 0 {
+		return utils.ResponseError(c, fiber.StatusConflict, "User sudah menjadi anggota halaqah ini", nil)
+	}
+
+	log.WithFields(logrus.Fields{
+		"anggotaID": user.ID,
+		"peran":     peran,
+	}).Info("Anggota halaqah berhasil ditambahkan")
+
+	return utils.SuccessResponse(c, fiber.StatusCreated, "Anggota halaqah berhasil ditambahkan", dto.AnggotaHalaqahResponse{
+		UserID: &user.ID,
+		Nama:   user.Nama,
+		Email:  user.Email,
+		Peran:  string(anggota.Peran),
+		Sejak:  anggota.CreatedAt,
+	})
+}
+
+func (s *HalaqahService) RemoveAnggota(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(*utils.Claims)
+	if !ok || claims == nil {
+		return utils.ResponseError(c, fiber.StatusUnauthorized, "Unauthorized: Token tidak valid atau tidak ada", nil)
+	}
+
+	halaqah, err := s.authorizeHalaqah(c, claims, true)
+	if err != nil {
+		return responsError(c, err)
+	}
+
+	anggotaUserID, err := c.ParamsInt("userID")
+	if err != nil {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "ID user tidak valid", nil)
+	}
+
+	log := logrus.WithFields(logrus.Fields{
+		"handler":   "RemoveAnggota",
+		"userID":    claims.ID,
+		"halaqahID": halaqah.ID,
+		"anggotaID": anggotaUserID,
+	})
+
+	peran, err := peranDiHalaqah(s.DB, halaqah.ID, uint(anggotaUserID))
+	if err != nil {
+		log.WithError(err).Error("Gagal memeriksa keanggotaan halaqah")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal menghapus anggota", err.Error())
+	}
+	if peran == "" {
+		return utils.ResponseError(c, fiber.StatusNotFound, "User bukan anggota halaqah ini", nil)
+	}
+	if peran == models.PeranMentor && !utils.HasPermission(claims.Role, utils.PermHalaqahManage) {
+		return utils.ResponseError(c, fiber.StatusForbidden, "Hanya admin yang dapat menghapus mentor", nil)
+	}
+
+	if err := s.DB.Where("halaqah_id = ? AND user_id = ?", halaqah.ID, anggotaUserID).Delete(&models.HalaqahAnggota{}).Error; err != nil {
+		log.WithError(err).Error("Gagal menghapus anggota halaqah")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal menghapus anggota", err.Error())
+	}
+
+	log.Info("Anggota halaqah berhasil dihapus")
+	return utils.SuccessResponse(c, fiber.StatusOK, "Anggota halaqah berhasil dihapus", nil)
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/habbazettt/muraja-server/dto"
+	"github.com/habbazettt/muraja-server/models"
+	"github.com/habbazettt/muraja-server/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type InvitationService struct {
+	DB *gorm.DB
+}
+
+const defaultInvitationExpiry = 72 * time.Hour
+
+func toInvitationResponse(inv models.Invitation) dto.InvitationResponse {
+	return dto.InvitationResponse{
+		ID:          inv.ID,
+		Role:        inv.Role,
+		Email:       inv.Email,
+		ExpiresAt:   inv.ExpiresAt,
+		UsedAt:      inv.UsedAt,
+		UsedByID:    inv.UsedByID,
+		CreatedByID: inv.CreatedByID,
+		CreatedAt:   inv.CreatedAt,
+	}
+}
+
+func (s *InvitationService) CreateInvitation(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(*utils.Claims)
+	if !ok || claims == nil {
+		return utils.ResponseError(c, fiber.StatusUnauthorized, "Unauthorized: Invalid claims", nil)
+	}
+
+	var req dto.CreateInvitationRequest
+	if err := c.BodyParser(&req); err != nil {
+		logrus.WithError(err).Error("Failed to parse request body")
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Invalid request body", err.Error())
+	}
+
+	if !models.IsValidRole(req.Role) {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Invalid role", nil)
+	}
+	if req.Email != "" && !utils.IsValidEmail(req.Email) {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Invalid email", nil)
+	}
+
+	code, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to generate invitation code")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Failed to create invitation", nil)
+	}
+
+	expiry := defaultInvitationExpiry
+	if req.ExpiresInJam > 0 {
+		expiry = time.Duration(req.ExpiresInJam) * time.Hour
+	}
+
+	invitation := models.Invitation{
+		CodeHash:    utils.HashToken(code),
+		Role:        req.Role,
+		ExpiresAt:   time.Now().Add(expiry),
+		CreatedByID: claims.ID,
+	}
+	if req.Email != "" {
+		invitation.Email = &req.Email
+	}
+
+	if err := s.DB.Create(&invitation).Error; err != nil {
+		logrus.WithError(err).Error("Failed to create invitation")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Failed to create invitation", err.Error())
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"invitation_id": invitation.ID,
+		"role":          invitation.Role,
+		"created_by":    claims.ID,
+	}).Info("Invitation created successfully")
+
+	// Kode mentah hanya dikembalikan sekali, saat undangan dibuat.
+	response := toInvitationResponse(invitation)
+	response.Code = code
+
+	return utils.SuccessResponse(c, fiber.StatusCreated, "Invitation created successfully", response)
+}
+
+func (s *InvitationService) GetAllInvitations(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	offset := (page - 1) * limit
+
+	var total int64
+	var invitations []models.Invitation
+
+	query := s.DB.Model(&models.Invitation{})
+	if c.Query("status") == "active" {
+		query = query.Where("used_at IS NULL AND expires_at > ?", time.Now())
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		logrus.WithError(err).Error("Failed to count invitations")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Failed to fetch invitations", err.Error())
+	}
+
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&invitations).Error; err != nil {
+		logrus.WithError(err).Error("Failed to fetch invitations")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Failed to fetch invitations", err.Error())
+	}
+
+	response := make([]dto.InvitationResponse, len(invitations))
+	for i, inv := range invitations {
+		response[i] = toInvitationResponse(inv)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Invitations retrieved successfully", fiber.Map{
+		"pagination": fiber.Map{
+			"current_page": page,
+			"total_data":   total,
+			"total_pages":  int(math.Ceil(float64(total) / float64(limit))),
+		},
+		"invitations": response,
+	})
+}
+
+func (s *InvitationService) RevokeInvitation(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	result := s.DB.Where("id = ? AND used_at IS NULL", id).Delete(&models.Invitation{})
+	if result.Error != nil {
+		logrus.WithError(result.Error).Error("Failed to revoke invitation")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Failed to revoke invitation", result.Error.Error())
+	}
+	if result.RowsAffected == 0 {
+		return utils.ResponseError(c, fiber.StatusNotFound, "Invitation not found or already used", nil)
+	}
+
+	logrus.WithField("invitation_id", id).Info("Invitation revoked successfully")
+	return utils.SuccessResponse(c, fiber.StatusOK, "Invitation revoked successfully", nil)
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"math"
 	"strconv"
+	"time"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -103,19 +104,31 @@ func (s *JadwalPersonalService) CreateJadwalPersonal(c *fiber.Ctx) error {
 		log.WithError(err).Warn("Gagal mem-parsing request body untuk jadwal personal")
 		return utils.ResponseError(c, fiber.StatusBadRequest, "Request body tidak valid", err.Error())
 	}
+	if len(req.Jadwal) == 0 {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Jadwal wajib berisi minimal satu slot", nil)
+	}
 
 	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
 		jadwalPersonal := models.JadwalPersonal{
-			UserID:            userID,
-			TotalHafalan:      req.TotalHafalan,
-			Jadwal:            req.Jadwal,
-			Kesibukan:         req.Kesibukan,
-			EfektifitasJadwal: req.EfektifitasJadwal,
+			UserID:                 userID,
+			TotalHafalan:           req.TotalHafalan,
+			Jadwal:                 req.Jadwal,
+			Kesibukan:              req.Kesibukan,
+			EfektifitasJadwal:      req.EfektifitasJadwal,
+			EfektifitasDinilaiPada: &now,
 		}
 
+		// Waktu penilaian lama dipertahankan jika nilainya tidak berubah.
+		updates := append(clause.AssignmentColumns([]string{"total_hafalan", "jadwal", "kesibukan", "efektifitas_jadwal", "updated_at"}),
+			clause.Assignment{
+				Column: clause.Column{Name: "efektifitas_dinilai_pada"},
+				Value: gorm.Expr(`CASE WHEN jadwal_personals.efektifitas_jadwal IS DISTINCT FROM EXCLUDED.efektifitas_jadwal
+					THEN EXCLUDED.efektifitas_dinilai_pada ELSE jadwal_personals.efektifitas_dinilai_pada END`),
+			})
 		if err := tx.Clauses(clause.OnConflict{
 			Columns:   []clause.Column{{Name: "user_id"}},
-			DoUpdates: clause.AssignmentColumns([]string{"total_hafalan", "jadwal", "kesibukan", "efektifitas_jadwal", "updated_at"}),
+			DoUpdates: updates,
 		}).Create(&jadwalPersonal).Error; err != nil {
 			return err
 		}
@@ -213,6 +226,9 @@ func (s *JadwalPersonalService) UpdateJadwalPersonal(c *fiber.Ctx) error {
 		updated = true
 	}
 	if req.Jadwal != nil {
+		if len(*req.Jadwal) == 0 {
+			return utils.ResponseError(c, fiber.StatusBadRequest, "Jadwal wajib berisi minimal satu slot", nil)
+		}
 		jadwalPersonal.Jadwal = *req.Jadwal
 		updated = true
 	}
@@ -221,6 +237,10 @@ func (s *JadwalPersonalService) UpdateJadwalPersonal(c *fiber.Ctx) error {
 		updated = true
 	}
 	if req.EfektifitasJadwal != nil {
+		if *req.EfektifitasJadwal != jadwalPersonal.EfektifitasJadwal {
+			now := time.Now()
+			jadwalPersonal.EfektifitasDinilaiPada = &now
+		}
 		jadwalPersonal.EfektifitasJadwal = *req.EfektifitasJadwal
 		updated = true
 	}
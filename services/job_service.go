@@ -0,0 +1,133 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/habbazettt/muraja-server/dto"
+	"github.com/habbazettt/muraja-server/models"
+	"github.com/habbazettt/muraja-server/scheduler"
+	"github.com/habbazettt/muraja-server/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type JobService struct {
+	DB        *gorm.DB
+	Scheduler *scheduler.Scheduler
+}
+
+func toJobRunResponse(run models.JobRun) dto.JobRunResponse {
+	return dto.JobRunResponse{
+		ID:          run.ID,
+		JobName:     run.JobName,
+		ScheduledAt: run.ScheduledAt,
+		Pemicu:      run.Pemicu,
+		Status:      string(run.Status),
+		Host:        run.Host,
+		Hasil:       run.Hasil,
+		Error:       run.Error,
+		StartedAt:   run.StartedAt,
+		FinishedAt:  run.FinishedAt,
+		DurasiMs:    run.DurasiMs,
+	}
+}
+
+func (s *JobService) GetAllJobs(c *fiber.Ctx) error {
+	jobs, err := s.Scheduler.Jobs()
+	if err != nil {
+		logrus.WithError(err).Error("Gagal mengambil status job")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal mengambil daftar job", err.Error())
+	}
+
+	response := make([]dto.JobResponse, len(jobs))
+	for i, job := range jobs {
+		response[i] = dto.JobResponse{Name: job.Name, Spec: job.Spec}
+		if !job.NextRun.IsZero() {
+			next := job.NextRun
+			response[i].NextRun = &next
+		}
+		if job.LastRun != nil {
+			last := toJobRunResponse(*job.LastRun)
+			response[i].LastRun = &last
+		}
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Daftar job berhasil diambil", response)
+}
+
+func (s *JobService) GetJobRuns(c *fiber.Ctx) error {
+	name := c.Params("name")
+	if !s.Scheduler.Has(name) {
+		return utils.ResponseError(c, fiber.StatusNotFound, "Job tidak ditemukan", nil)
+	}
+
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	offset := (page - 1) * limit
+
+	var total int64
+	query := s.DB.Model(&models.JobRun{}).Where("job_name = ?", name)
+	if err := query.Count(&total).Error; err != nil {
+		logrus.WithError(err).Error("Gagal menghitung riwayat job")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal mengambil riwayat job", err.Error())
+	}
+
+	var runs []models.JobRun
+	if err := query.Order("started_at DESC").Limit(limit).Offset(offset).Find(&runs).Error; err != nil {
+		logrus.WithError(err).Error("Gagal mengambil riwayat job")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal mengambil riwayat job", err.Error())
+	}
+
+	response := make([]dto.JobRunResponse, len(runs))
+	for i, run := range runs {
+		response[i] = toJobRunResponse(run)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Riwayat job berhasil diambil", fiber.Map{
+		"pagination": fiber.Map{
+			"current_page": page,
+			"total_data":   total,
+			"total_pages":  int(math.Ceil(float64(total) / float64(limit))),
+		},
+		"runs": response,
+	})
+}
+
+func (s *JobService) TriggerJob(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(*utils.Claims)
+	if !ok || claims == nil {
+		return utils.ResponseError(c, fiber.StatusUnauthorized, "Unauthorized: Token tidak valid atau tidak ada", nil)
+	}
+
+	name := c.Params("name")
+	log := logrus.WithFields(logrus.Fields{
+		"handler":     "TriggerJob",
+		"job":         name,
+		"requesterID": claims.ID,
+	})
+
+	run, err := s.Scheduler.Trigger(name)
+	if err != nil {
+		switch {
+		case errors.Is(err, scheduler.ErrJobNotFound):
+			return utils.ResponseError(c, fiber.StatusNotFound, "Job tidak ditemukan", nil)
+		case errors.Is(err, scheduler.ErrJobLocked):
+			return utils.ResponseError(c, fiber.StatusConflict, "Job sedang berjalan", nil)
+		default:
+			log.WithError(err).Error("Gagal memicu job")
+			return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal memicu job", err.Error())
+		}
+	}
+
+	log.WithField("runID", run.ID).Info("Job dipicu secara manual")
+	return utils.SuccessResponse(c, fiber.StatusAccepted, "Job berhasil dipicu", toJobRunResponse(*run))
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/habbazettt/muraja-server/models"
+	"github.com/habbazettt/muraja-server/qlearning"
+	"github.com/habbazettt/muraja-server/scheduler"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	JobRolloverHarian  = "rollover-harian"
+	JobBersihkanToken  = "bersihkan-token"
+	JobKirimPengingat  = "kirim-pengingat"
+	JobPerbaruiQTable  = "perbarui-qtable"
+	JobBersihkanJobRun = "bersihkan-job-run"
+
+	defaultRetensiJobRunHari = 14
+)
+
+// RegisterJobs mendaftarkan semua job periodik server ke scheduler.
+func RegisterJobs(s *scheduler.Scheduler, db *gorm.DB, pengingat *PengingatService) error {
+	if err := s.Register(JobRolloverHarian, "5 0 * * *", func(ctx context.Context) (string, error) {
+		jumlah, err := JalankanRollover(db.WithContext(ctx), time.Now().UTC().AddDate(0, 0, -1))
+		return fmt.Sprintf("%d detail log dipindahkan", jumlah), err
+	}); err != nil {
+		return err
+	}
+
+	if err := s.Register(JobBersihkanToken, "@hourly", func(ctx context.Context) (string, error) {
+		return BersihkanTokenKedaluwarsa(db.WithContext(ctx), time.Now().UTC())
+	}); err != nil {
+		return err
+	}
+
+	if err := s.Register(JobKirimPengingat, "* * * * *", func(ctx context.Context) (string, error) {
+		return pengingat.KirimPengingatJatuhTempo(ctx, time.Now().UTC())
+	}); err != nil {
+		return err
+	}
+
+	retensiHari := retensiJobRunFromEnv()
+	if err := s.Register(JobBersihkanJobRun, "15 1 * * *", func(ctx context.Context) (string, error) {
+		return BersihkanJobRun(db.WithContext(ctx), time.Now().UTC(), retensiHari)
+	}); err != nil {
+		return err
+	}
+
+	params, err := qlearning.ParamsFromEnv()
+	if err != nil {
+		logrus.WithError(err).Warn("Parameter Q-learning tidak valid, memakai default")
+	}
+	jendelaHari := jendelaEvaluasiFromEnv()
+	return s.Register(JobPerbaruiQTable, "30 0 * * *", func(ctx context.Context) (string, error) {
+		return PerbaruiQTable(db.WithContext(ctx), time.Now().UTC(), params, jendelaHari)
+	})
+}
+
+// BersihkanTokenKedaluwarsa menghapus token reset password dan sesi yang sudah
+// kedaluwarsa. Sesi yang dicabut tetapi belum kedaluwarsa dipertahankan karena
+// masih dibutuhkan untuk mendeteksi pemakaian ulang refresh token.
+func BersihkanTokenKedaluwarsa(db *gorm.DB, now time.Time) (string, error) {
+	resetTokens := db.Where("expires_at < ?", now).Delete(&models.PasswordResetToken{})
+	if resetTokens.Error != nil {
+		return "", resetTokens.Error
+	}
+
+	sessions := db.Where("expires_at < ?", now).Delete(&models.Session{})
+	if sessions.Error != nil {
+		return "", sessions.Error
+	}
+
+	return fmt.Sprintf("%d token reset dan %d sesi dihapus", resetTokens.RowsAffected, sessions.RowsAffected), nil
+}
+
+// retensiJobRunFromEnv membaca JOB_RUN_RETENSI_HARI: lama hari riwayat job
+// yang sukses disimpan.
+func retensiJobRunFromEnv() int {
+	if v := os.Getenv("JOB_RUN_RETENSI_HARI"); v != "" {
+		if hari, err := strconv.Atoi(v); err == nil && hari > 0 {
+			return hari
+		}
+		logrus.WithField("nilai", v).Warn("JOB_RUN_RETENSI_HARI tidak valid, memakai default")
+	}
+	return defaultRetensiJobRunHari
+}
+
+// BersihkanJobRun menghapus riwayat job yang sukses dan lebih lama dari
+// retensiHari, karena kirim-pengingat saja mencatat satu run setiap menit. Run
+// yang gagal dan run terakhir setiap job tetap disimpan.
+func BersihkanJobRun(db *gorm.DB, now time.Time, retensiHari int) (string, error) {
+	res := db.Where("status = ? AND started_at < ?", models.StatusJobSukses, now.AddDate(0, 0, -retensiHari)).
+		Where("id NOT IN (SELECT MAX(id) FROM job_runs GROUP BY job_name)").
+		Delete(&models.JobRun{})
+	if res.Error != nil {
+		return "", res.Error
+	}
+	return fmt.Sprintf("%d riwayat job dihapus", res.RowsAffected), nil
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/habbazettt/muraja-server/dto"
 	"github.com/habbazettt/muraja-server/models"
+	"github.com/habbazettt/muraja-server/mushaf"
+	"github.com/habbazettt/muraja-server/slot"
 	"github.com/habbazettt/muraja-server/utils"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
@@ -18,25 +20,103 @@ type LogMurojaahService struct {
 	DB *gorm.DB
 }
 
-func calculateTotalPages(startJuz, startHalaman, endJuz, endHalaman int) (int, error) {
-	if startJuz > endJuz || (startJuz == endJuz && startHalaman > endHalaman) {
-		return 0, errors.New("target/progres akhir tidak boleh lebih kecil dari awal")
+var errRentangMushaf = errors.New("rentang mushaf tidak valid")
+
+// resolveRentangTarget menormalkan awal dan akhir target menjadi nomor halaman
+// mutlak pada layout mushaf, sehingga total halaman dihitung dengan benar
+// meskipun jumlah halaman per juz berbeda-beda.
+func resolveRentangTarget(layout *mushaf.Layout, req dto.RentangTargetRequest) (int, int, int, error) {
+	startPage, err := layout.Resolve(mushaf.Alamat{
+		Juz:          req.TargetStartJuz,
+		HalamanDiJuz: req.TargetStartHalaman,
+		Halaman:      req.TargetStartPage,
+		Ayat:         req.TargetStartAyat,
+	})
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("%w: awal target: %v", errRentangMushaf, err)
 	}
 
-	const halamanPerJuz = 20
+	endPage, err := layout.Resolve(mushaf.Alamat{
+		Juz:          req.TargetEndJuz,
+		HalamanDiJuz: req.TargetEndHalaman,
+		Halaman:      req.TargetEndPage,
+		Ayat:         req.TargetEndAyat,
+	})
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("%w: akhir target: %v", errRentangMushaf, err)
+	}
 
-	if startJuz == endJuz {
-		return (endHalaman - startHalaman) + 1, nil
+	total, err := mushaf.JumlahHalaman(startPage, endPage)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("%w: %v", errRentangMushaf, err)
 	}
 
-	halamanDiJuzAwal := (halamanPerJuz - startHalaman) + 1
+	return startPage, endPage, total, nil
+}
 
-	halamanDiJuzAkhir := endHalaman
+// newDetailLogTarget mengisi kolom target DetailLog dari rentang halaman mutlak.
+func newDetailLogTarget(layout *mushaf.Layout, startPage, endPage, total int) models.DetailLog {
+	startJuz, startHalaman := layout.JuzHalamanOf(startPage)
+	endJuz, endHalaman := layout.JuzHalamanOf(endPage)
+
+	return models.DetailLog{
+		TargetStartJuz:     startJuz,
+		TargetStartHalaman: startHalaman,
+		TargetEndJuz:       endJuz,
+		TargetEndHalaman:   endHalaman,
+		TargetStartPage:    startPage,
+		TargetEndPage:      endPage,
+		TotalTargetHalaman: total,
+	}
+}
+
+func toDetailLogResponse(detail models.DetailLog) dto.DetailLogResponse {
+	response := dto.DetailLogResponse{
+		ID:                  detail.ID,
+		WaktuMurojaah:       detail.WaktuMurojaah,
+		Slot:                string(detail.Slot),
+		MushafLayout:        detail.MushafLayout,
+		TargetStartJuz:      detail.TargetStartJuz,
+		TargetStartHalaman:  detail.TargetStartHalaman,
+		TargetEndJuz:        detail.TargetEndJuz,
+		TargetEndHalaman:    detail.TargetEndHalaman,
+		TargetStartPage:     detail.TargetStartPage,
+		TargetEndPage:       detail.TargetEndPage,
+		TotalTargetHalaman:  detail.TotalTargetHalaman,
+		SelesaiEndJuz:       detail.SelesaiEndJuz,
+		SelesaiEndHalaman:   detail.SelesaiEndHalaman,
+		SelesaiEndPage:      detail.SelesaiEndPage,
+		TotalSelesaiHalaman: detail.TotalSelesaiHalaman,
+		Status:              string(detail.Status),
+		Catatan:             detail.Catatan,
+		UpdatedAt:           detail.UpdatedAt,
+	}
+
+	layout, err := mushaf.Get(detail.MushafLayout)
+	if err != nil {
+		return response
+	}
+	if awal, _, err := layout.RentangAyatHalaman(detail.TargetStartPage); err == nil {
+		response.TargetStartAyat = awal.String()
+	}
+	if _, akhir, err := layout.RentangAyatHalaman(detail.TargetEndPage); err == nil {
+		response.TargetEndAyat = akhir.String()
+	}
 
-	juzPerantara := (endJuz - startJuz) - 1
-	halamanDiJuzPerantara := juzPerantara * halamanPerJuz
+	return response
+}
 
-	return halamanDiJuzAwal + halamanDiJuzAkhir + halamanDiJuzPerantara, nil
+// layoutUser mengembalikan layout mushaf pilihan user, atau layout default jika
+// user belum memilih atau layout pilihannya sudah tidak disediakan.
+func layoutUser(db *gorm.DB, userID uint) (*mushaf.Layout, error) {
+	var user models.User
+	if err := db.Select("id", "mushaf_layout").First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+	if layout, err := mushaf.Get(user.MushafLayout); err == nil {
+		return layout, nil
+	}
+	return mushaf.Default(), nil
 }
 
 func (s *LogMurojaahService) recalculateTotals(tx *gorm.DB, logHarianID uint) error {
@@ -60,21 +140,70 @@ func (s *LogMurojaahService) recalculateTotals(tx *gorm.DB, logHarianID uint) er
 	}).Error
 }
 
+var (
+	errUserIDTidakValid = errors.New("query parameter userID tidak valid")
+	errAksesDitolak     = errors.New("anda tidak punya hak akses ke data user ini")
+)
+
+// resolveTargetUserID menentukan pemilik data yang diminta. Tanpa query userID,
+// data milik requester sendiri yang dipakai; selain itu requester harus punya
+// izin membaca log semua user, atau menjadi ustadz/mentor halaqah dari user tersebut.
+func (s *LogMurojaahService) resolveTargetUserID(c *fiber.Ctx, claims *utils.Claims) (uint, error) {
+	if c.Query("userID") == "" {
+		return claims.ID, nil
+	}
+
+	id, err := strconv.Atoi(c.Query("userID"))
+	if err != nil || id <= 0 {
+		return 0, errUserIDTidakValid
+	}
+	targetUserID := uint(id)
+
+	if targetUserID == claims.ID || utils.HasPermission(claims.Role, utils.PermLogReadAny) {
+		return targetUserID, nil
+	}
+
+	if utils.HasPermission(claims.Role, utils.PermLogReadAssigned) {
+		var count int64
+		if err := s.DB.Model(&models.User{}).Where("id = ? AND ustadz_id = ?", targetUserID, claims.ID).Count(&count).Error; err != nil {
+			return 0, err
+		}
+		if count > 0 {
+			return targetUserID, nil
+		}
+
+		isMentor, err := isMentorOfUser(s.DB, claims.ID, targetUserID)
+		if err != nil {
+			return 0, err
+		}
+		if isMentor {
+			return targetUserID, nil
+		}
+	}
+
+	return 0, errAksesDitolak
+}
+
+func targetUserErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, errUserIDTidakValid):
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Query parameter userID tidak valid", nil)
+	case errors.Is(err, errAksesDitolak):
+		return utils.ResponseError(c, fiber.StatusForbidden, "Anda tidak punya hak akses ke data user ini", nil)
+	default:
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal memeriksa hak akses", err.Error())
+	}
+}
+
 func (s *LogMurojaahService) GetOrCreateLogHarian(c *fiber.Ctx) error {
 	claims, ok := c.Locals("user").(*utils.Claims)
 	if !ok || claims == nil {
 		return utils.ResponseError(c, fiber.StatusUnauthorized, "Unauthorized: Token tidak valid atau tidak ada", nil)
 	}
 
-	var targetUserID uint
-	if claims.Role == "admin" && c.Query("userID") != "" {
-		id, err := strconv.Atoi(c.Query("userID"))
-		if err != nil {
-			return utils.ResponseError(c, fiber.StatusBadRequest, "Query parameter userID tidak valid", nil)
-		}
-		targetUserID = uint(id)
-	} else {
-		targetUserID = claims.ID
+	targetUserID, err := s.resolveTargetUserID(c, claims)
+	if err != nil {
+		return targetUserErrorResponse(c, err)
 	}
 
 	log := logrus.WithFields(logrus.Fields{
@@ -85,7 +214,6 @@ func (s *LogMurojaahService) GetOrCreateLogHarian(c *fiber.Ctx) error {
 
 	tanggalStr := c.Query("tanggal")
 	var tanggal time.Time
-	var err error
 	if tanggalStr == "" {
 		tanggal = time.Now()
 	} else {
@@ -109,21 +237,7 @@ func (s *LogMurojaahService) GetOrCreateLogHarian(c *fiber.Ctx) error {
 
 	detailDTOs := make([]dto.DetailLogResponse, len(logHarian.DetailLogs))
 	for i, detail := range logHarian.DetailLogs {
-		detailDTOs[i] = dto.DetailLogResponse{
-			ID:                  detail.ID,
-			WaktuMurojaah:       detail.WaktuMurojaah,
-			TargetStartJuz:      detail.TargetStartJuz,
-			TargetStartHalaman:  detail.TargetStartHalaman,
-			TargetEndJuz:        detail.TargetEndJuz,
-			TargetEndHalaman:    detail.TargetEndHalaman,
-			TotalTargetHalaman:  detail.TotalTargetHalaman,
-			SelesaiEndJuz:       detail.SelesaiEndJuz,
-			SelesaiEndHalaman:   detail.SelesaiEndHalaman,
-			TotalSelesaiHalaman: detail.TotalSelesaiHalaman,
-			Status:              string(detail.Status),
-			Catatan:             detail.Catatan,
-			UpdatedAt:           detail.UpdatedAt,
-		}
+		detailDTOs[i] = toDetailLogResponse(detail)
 	}
 
 	response := dto.LogHarianResponse{
@@ -156,9 +270,20 @@ func (s *LogMurojaahService) AddDetailToLog(c *fiber.Ctx) error {
 		return utils.ResponseError(c, fiber.StatusBadRequest, "Request body tidak valid", err.Error())
 	}
 
+	waktu := slot.Normalisasi(req.WaktuMurojaah)
+	if waktu == "" {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Waktu murojaah wajib diisi", nil)
+	}
+
+	layout, err := layoutUser(s.DB, targetUserID)
+	if err != nil {
+		log.WithError(err).Error("Gagal mengambil layout mushaf user")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal menyimpan sesi murojaah", err.Error())
+	}
+
 	var newDetail models.DetailLog
 
-	err := s.DB.Transaction(func(tx *gorm.DB) error {
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
 		today := time.Now().UTC()
 		today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
 
@@ -167,25 +292,17 @@ func (s *LogMurojaahService) AddDetailToLog(c *fiber.Ctx) error {
 			return err
 		}
 
-		totalTarget, err := calculateTotalPages(req.TargetStartJuz, req.TargetStartHalaman, req.TargetEndJuz, req.TargetEndHalaman)
+		startPage, endPage, totalTarget, err := resolveRentangTarget(layout, req.RentangTargetRequest)
 		if err != nil {
 			return err
 		}
-		if totalTarget <= 0 {
-			return errors.New("target murojaah harus lebih dari 0 halaman")
-		}
 
-		newDetail = models.DetailLog{
-			LogHarianID:        logHarian.ID,
-			WaktuMurojaah:      req.WaktuMurojaah,
-			TargetStartJuz:     req.TargetStartJuz,
-			TargetStartHalaman: req.TargetStartHalaman,
-			TargetEndJuz:       req.TargetEndJuz,
-			TargetEndHalaman:   req.TargetEndHalaman,
-			TotalTargetHalaman: totalTarget,
-			Status:             models.StatusSesiBelumSelesai,
-			Catatan:            req.Catatan,
-		}
+		newDetail = newDetailLogTarget(layout, startPage, endPage, totalTarget)
+		newDetail.LogHarianID = logHarian.ID
+		newDetail.MushafLayout = layout.Kode
+		setWaktuMurojaah(&newDetail, waktu)
+		newDetail.Status = models.StatusSesiBelumSelesai
+		newDetail.Catatan = req.Catatan
 		if err := tx.Create(&newDetail).Error; err != nil {
 			return err
 		}
@@ -195,27 +312,13 @@ func (s *LogMurojaahService) AddDetailToLog(c *fiber.Ctx) error {
 
 	if err != nil {
 		log.WithError(err).Error("Gagal menambahkan detail log dalam transaksi")
-		if err.Error() == "target murojaah harus lebih dari 0 halaman" || err.Error() == "target/progres akhir tidak boleh lebih kecil dari awal" {
+		if errors.Is(err, errRentangMushaf) {
 			return utils.ResponseError(c, fiber.StatusBadRequest, err.Error(), nil)
 		}
 		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal menyimpan sesi murojaah", err.Error())
 	}
 
-	response := dto.DetailLogResponse{
-		ID:                  newDetail.ID,
-		WaktuMurojaah:       newDetail.WaktuMurojaah,
-		TargetStartJuz:      newDetail.TargetStartJuz,
-		TargetStartHalaman:  newDetail.TargetStartHalaman,
-		TargetEndJuz:        newDetail.TargetEndJuz,
-		TargetEndHalaman:    newDetail.TargetEndHalaman,
-		TotalTargetHalaman:  newDetail.TotalTargetHalaman,
-		SelesaiEndJuz:       newDetail.SelesaiEndJuz,
-		SelesaiEndHalaman:   newDetail.SelesaiEndHalaman,
-		TotalSelesaiHalaman: newDetail.TotalSelesaiHalaman,
-		Status:              string(newDetail.Status),
-		Catatan:             newDetail.Catatan,
-		UpdatedAt:           newDetail.UpdatedAt,
-	}
+	response := toDetailLogResponse(newDetail)
 
 	log.Info("Berhasil menambahkan detail sesi murojaah baru")
 	return utils.SuccessResponse(c, fiber.StatusCreated, "Sesi murojaah berhasil ditambahkan", response)
@@ -258,13 +361,27 @@ func (s *LogMurojaahService) UpdateDetailLog(c *fiber.Ctx) error {
 			return err
 		}
 
-		totalSelesai, err := calculateTotalPages(detailLog.TargetStartJuz, detailLog.TargetStartHalaman, req.SelesaiEndJuz, req.SelesaiEndHalaman)
+		// Progres dibaca dalam layout saat sesi dicatat. Sesi dengan layout
+		// yang sudah tidak disediakan tidak bisa dilanjutkan karena nomor
+		// halamannya tidak dapat dipetakan dengan tepat.
+		layout, err := mushaf.Get(detailLog.MushafLayout)
 		if err != nil {
-			return err
+			return fmt.Errorf("%w: sesi ini dicatat dengan layout mushaf %q yang sudah tidak disediakan", errRentangMushaf, detailLog.MushafLayout)
 		}
 
-		if totalSelesai > detailLog.TotalTargetHalaman {
-			totalSelesai = detailLog.TotalTargetHalaman
+		selesaiEndPage, err := layout.Resolve(mushaf.Alamat{
+			Juz:          req.SelesaiEndJuz,
+			HalamanDiJuz: req.SelesaiEndHalaman,
+			Halaman:      req.SelesaiEndPage,
+			Ayat:         req.SelesaiEndAyat,
+		})
+		if err != nil {
+			return fmt.Errorf("%w: progres akhir: %v", errRentangMushaf, err)
+		}
+
+		totalSelesai, err := mushaf.JumlahHalaman(detailLog.TargetStartPage, selesaiEndPage)
+		if err != nil {
+			return fmt.Errorf("%w: %v", errRentangMushaf, err)
 		}
 
 		var newStatus models.StatusDetailLog
@@ -276,8 +393,25 @@ func (s *LogMurojaahService) UpdateDetailLog(c *fiber.Ctx) error {
 			log.Info("Progres belum mencapai target. Status tetap 'Belum Selesai'.")
 		}
 
-		detailLog.SelesaiEndJuz = req.SelesaiEndJuz
-		detailLog.SelesaiEndHalaman = req.SelesaiEndHalaman
+		// Halaman yang baru diselesaikan sejak update sebelumnya dicatat ke peta hafalan.
+		awalCatat := max(detailLog.TargetStartPage, detailLog.HalamanTercatat+1)
+		akhirCatat := min(selesaiEndPage, detailLog.TargetEndPage)
+
+		ratings, err := parseRatingHalaman(req.RatingHalaman, awalCatat, akhirCatat)
+		if err != nil {
+			return err
+		}
+		if awalCatat <= akhirCatat {
+			if err := catatKekuatanHalaman(tx, userID, awalCatat, akhirCatat, ratings, time.Now().UTC()); err != nil {
+				return err
+			}
+			detailLog.HalamanTercatat = akhirCatat
+		}
+
+		totalSelesai = min(totalSelesai, detailLog.TotalTargetHalaman)
+
+		detailLog.SelesaiEndJuz, detailLog.SelesaiEndHalaman = layout.JuzHalamanOf(selesaiEndPage)
+		detailLog.SelesaiEndPage = selesaiEndPage
 		detailLog.TotalSelesaiHalaman = totalSelesai
 		detailLog.Catatan = req.Catatan
 		detailLog.Status = newStatus
@@ -291,30 +425,19 @@ func (s *LogMurojaahService) UpdateDetailLog(c *fiber.Ctx) error {
 
 	if err != nil {
 		log.WithError(err).Error("Gagal memperbarui detail log dalam transaksi")
-		if err.Error() == "target/progres akhir tidak boleh lebih kecil dari awal" {
+		if errors.Is(err, errRentangMushaf) {
 			return utils.ResponseError(c, fiber.StatusBadRequest, err.Error(), nil)
 		}
+		if errors.Is(err, errRatingHalaman) {
+			return utils.ResponseError(c, fiber.StatusBadRequest, "Rating halaman tidak valid: gunakan lancar, kurang_lancar, atau banyak_salah untuk halaman yang baru diselesaikan", nil)
+		}
 		if err.Error() == "detail log tidak ditemukan atau Anda tidak punya hak akses" {
 			return utils.ResponseError(c, fiber.StatusNotFound, err.Error(), nil)
 		}
 		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal memperbarui sesi murojaah", err.Error())
 	}
 
-	response := dto.DetailLogResponse{
-		ID:                  detailLog.ID,
-		WaktuMurojaah:       detailLog.WaktuMurojaah,
-		TargetStartJuz:      detailLog.TargetStartJuz,
-		TargetStartHalaman:  detailLog.TargetStartHalaman,
-		TargetEndJuz:        detailLog.TargetEndJuz,
-		TargetEndHalaman:    detailLog.TargetEndHalaman,
-		TotalTargetHalaman:  detailLog.TotalTargetHalaman,
-		SelesaiEndJuz:       detailLog.SelesaiEndJuz,
-		SelesaiEndHalaman:   detailLog.SelesaiEndHalaman,
-		TotalSelesaiHalaman: detailLog.TotalSelesaiHalaman,
-		Status:              string(detailLog.Status),
-		Catatan:             detailLog.Catatan,
-		UpdatedAt:           detailLog.UpdatedAt,
-	}
+	response := toDetailLogResponse(detailLog)
 
 	log.Info("Berhasil memperbarui detail sesi murojaah")
 	return utils.SuccessResponse(c, fiber.StatusOK, "Sesi murojaah berhasil diperbarui", response)
@@ -378,15 +501,9 @@ func (s *LogMurojaahService) GetRecapMingguan(c *fiber.Ctx) error {
 		return utils.ResponseError(c, fiber.StatusUnauthorized, "Unauthorized: Token tidak valid atau tidak ada", nil)
 	}
 
-	var targetUserID uint
-	if claims.Role == "admin" && c.Query("userID") != "" {
-		id, err := strconv.Atoi(c.Query("userID"))
-		if err != nil {
-			return utils.ResponseError(c, fiber.StatusBadRequest, "Query parameter userID tidak valid", nil)
-		}
-		targetUserID = uint(id)
-	} else {
-		targetUserID = claims.ID
+	targetUserID, err := s.resolveTargetUserID(c, claims)
+	if err != nil {
+		return targetUserErrorResponse(c, err)
 	}
 
 	log := logrus.WithFields(logrus.Fields{
@@ -409,7 +526,7 @@ func (s *LogMurojaahService) GetRecapMingguan(c *fiber.Ctx) error {
 	startDate = time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, time.UTC)
 	endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 23, 59, 59, 0, time.UTC)
 
-	err := s.DB.Model(&models.LogHarian{}).
+	err = s.DB.Model(&models.LogHarian{}).
 		Select("to_char(tanggal, 'DD-MM-YYYY') as tanggal, total_selesai_halaman").
 		Where("user_id = ? AND tanggal BETWEEN ? AND ?", targetUserID, startDate, endDate).
 		Order("tanggal ASC").
@@ -429,15 +546,9 @@ func (s *LogMurojaahService) GetStatistikMurojaah(c *fiber.Ctx) error {
 		return utils.ResponseError(c, fiber.StatusUnauthorized, "Unauthorized: Token tidak valid atau tidak ada", nil)
 	}
 
-	var targetUserID uint
-	if claims.Role == "admin" && c.Query("userID") != "" {
-		id, err := strconv.Atoi(c.Query("userID"))
-		if err != nil {
-			return utils.ResponseError(c, fiber.StatusBadRequest, "Query parameter userID tidak valid", nil)
-		}
-		targetUserID = uint(id)
-	} else {
-		targetUserID = claims.ID
+	targetUserID, err := s.resolveTargetUserID(c, claims)
+	if err != nil {
+		return targetUserErrorResponse(c, err)
 	}
 
 	log := logrus.WithFields(logrus.Fields{
@@ -452,7 +563,7 @@ func (s *LogMurojaahService) GetStatistikMurojaah(c *fiber.Ctx) error {
 		HariAktif    int
 	}
 
-	err := s.DB.Model(&models.LogHarian{}).
+	err = s.DB.Model(&models.LogHarian{}).
 		Select("SUM(total_selesai_halaman) as total_selesai, COUNT(id) as hari_aktif").
 		Where("user_id = ? AND total_selesai_halaman > 0", targetUserID).
 		Scan(&stats).Error
@@ -478,22 +589,26 @@ func (s *LogMurojaahService) GetStatistikMurojaah(c *fiber.Ctx) error {
 		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal memproses statistik", err.Error())
 	}
 
-	var sesiProduktif struct {
-		WaktuMurojaah string
-	}
+	// Sesi dikelompokkan per slot baku sehingga variasi penulisan dihitung
+	// sebagai sesi yang sama; label custom digabung ke slot "custom".
+	var sesiPerSlot []dto.StatistikSlotResponse
 	err = s.DB.Model(&models.DetailLog{}).
-		Select("waktu_murojaah").
+		Select("detail_logs.slot, COUNT(detail_logs.id) as jumlah_sesi, SUM(detail_logs.total_selesai_halaman) as total_selesai_halaman").
 		Joins("JOIN log_harians ON log_harians.id = detail_logs.log_harian_id").
 		Where("log_harians.user_id = ? AND detail_logs.status = ?", targetUserID, models.StatusSesiSelesai).
-		Group("waktu_murojaah").
-		Order("COUNT(detail_logs.id) DESC").
-		Limit(1).
-		Scan(&sesiProduktif).Error
-	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		Group("detail_logs.slot").
+		Order("jumlah_sesi DESC, total_selesai_halaman DESC").
+		Scan(&sesiPerSlot).Error
+	if err != nil {
 		log.WithError(err).Error("Gagal mencari sesi paling produktif")
 		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal memproses statistik", err.Error())
 	}
 
+	var sesiProduktif string
+	if len(sesiPerSlot) > 0 {
+		sesiProduktif = sesiPerSlot[0].Slot
+	}
+
 	var hariProduktifPtr *dto.RecapHarianSimple
 	if hariProduktif.Tanggal != "" {
 		hariProduktifPtr = &hariProduktif
@@ -503,7 +618,8 @@ func (s *LogMurojaahService) GetStatistikMurojaah(c *fiber.Ctx) error {
 		TotalSelesaiHalaman:    stats.TotalSelesai,
 		TotalHariAktif:         stats.HariAktif,
 		RataRataHalamanPerHari: rataRata,
-		SesiPalingProduktif:    sesiProduktif.WaktuMurojaah,
+		SesiPalingProduktif:    sesiProduktif,
+		SesiPerSlot:            sesiPerSlot,
 		HariPalingProduktif:    hariProduktifPtr,
 	}
 
@@ -529,9 +645,15 @@ func (s *LogMurojaahService) ApplyAIRekomendasi(c *fiber.Ctx) error {
 		return utils.ResponseError(c, fiber.StatusBadRequest, "Request body tidak valid", err.Error())
 	}
 
+	layout, err := layoutUser(s.DB, userID)
+	if err != nil {
+		log.WithError(err).Error("Gagal mengambil layout mushaf user")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal menerapkan rekomendasi", err.Error())
+	}
+
 	var newDetail models.DetailLog
 
-	err := s.DB.Transaction(func(tx *gorm.DB) error {
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
 		var rekomendasi models.JadwalRekomendasi
 		if err := tx.Where("id = ? AND user_id = ?", req.RekomendasiID, userID).First(&rekomendasi).Error; err != nil {
 			return errors.New("riwayat rekomendasi tidak ditemukan atau bukan milik anda")
@@ -545,26 +667,36 @@ func (s *LogMurojaahService) ApplyAIRekomendasi(c *fiber.Ctx) error {
 			return err
 		}
 
-		totalTarget, err := calculateTotalPages(req.TargetStartJuz, req.TargetStartHalaman, req.TargetEndJuz, req.TargetEndHalaman)
+		startPage, endPage, totalTarget, err := resolveRentangTarget(layout, req.RentangTargetRequest)
 		if err != nil {
 			return err
 		}
 
-		newDetail = models.DetailLog{
-			LogHarianID:        logHarian.ID,
-			WaktuMurojaah:      fmt.Sprintf("AI: %s", rekomendasi.RekomendasiJadwal),
-			TargetStartJuz:     req.TargetStartJuz,
-			TargetStartHalaman: req.TargetStartHalaman,
-			TargetEndJuz:       req.TargetEndJuz,
-			TargetEndHalaman:   req.TargetEndHalaman,
-			TotalTargetHalaman: totalTarget,
-			Status:             models.StatusSesiBelumSelesai,
-			Catatan:            req.Catatan,
+		newDetail = newDetailLogTarget(layout, startPage, endPage, totalTarget)
+		newDetail.LogHarianID = logHarian.ID
+		newDetail.MushafLayout = layout.Kode
+		waktu, err := slotDariRekomendasi(rekomendasi, req.WaktuMurojaah)
+		if err != nil {
+			return err
+		}
+		setWaktuMurojaah(&newDetail, waktu)
+		newDetail.Status = models.StatusSesiBelumSelesai
+		newDetail.RekomendasiID = &rekomendasi.ID
+		newDetail.Catatan = req.Catatan
+		if newDetail.Catatan == "" {
+			newDetail.Catatan = fmt.Sprintf("Rekomendasi AI: %s", rekomendasi.JadwalDiterapkan())
 		}
 		if err := tx.Create(&newDetail).Error; err != nil {
 			return err
 		}
 
+		// Tanggal penerapan pertama menjadi awal jendela evaluasi reward.
+		if rekomendasi.DiterapkanPada == nil {
+			if err := tx.Model(&rekomendasi).Update("diterapkan_pada", today).Error; err != nil {
+				return err
+			}
+		}
+
 		return s.recalculateTotals(tx, logHarian.ID)
 	})
 
@@ -573,26 +705,12 @@ func (s *LogMurojaahService) ApplyAIRekomendasi(c *fiber.Ctx) error {
 		if err.Error() == "riwayat rekomendasi tidak ditemukan atau bukan milik anda" {
 			return utils.ResponseError(c, fiber.StatusNotFound, err.Error(), nil)
 		}
-		if err.Error() == "target/progres akhir tidak boleh lebih kecil dari awal" {
+		if errors.Is(err, errRentangMushaf) || errors.Is(err, errSlotBukanRekomendasi) {
 			return utils.ResponseError(c, fiber.StatusBadRequest, err.Error(), nil)
 		}
 		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal menerapkan rekomendasi", err.Error())
 	}
 
-	response := dto.DetailLogResponse{
-		ID:                  newDetail.ID,
-		WaktuMurojaah:       newDetail.WaktuMurojaah,
-		TargetStartJuz:      newDetail.TargetStartJuz,
-		TargetStartHalaman:  newDetail.TargetStartHalaman,
-		TargetEndJuz:        newDetail.TargetEndJuz,
-		TargetEndHalaman:    newDetail.TargetEndHalaman,
-		TotalTargetHalaman:  newDetail.TotalTargetHalaman,
-		SelesaiEndJuz:       newDetail.SelesaiEndJuz,
-		SelesaiEndHalaman:   newDetail.SelesaiEndHalaman,
-		TotalSelesaiHalaman: newDetail.TotalSelesaiHalaman,
-		Status:              string(newDetail.Status),
-		Catatan:             newDetail.Catatan,
-		UpdatedAt:           newDetail.UpdatedAt,
-	}
+	response := toDetailLogResponse(newDetail)
 	return utils.SuccessResponse(c, fiber.StatusCreated, "Rekomendasi berhasil diterapkan ke log harian", response)
-}
\ No newline at end of file
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/habbazettt/muraja-server/config"
+	"github.com/habbazettt/muraja-server/dto"
+	"github.com/habbazettt/muraja-server/models"
+	"github.com/habbazettt/muraja-server/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const defaultIntervalSinkronModel = time.Minute
+
+var errModelSudahTerdaftar = errors.New("model dengan isi yang sama sudah terdaftar")
+
+// adopsiVersiLama menangani versi yang dibuat sebelum registry model mencatat
+// checksum dan data historis: data historisnya diisi dari berkas dan versi
+// berkas pertama diberi checksum berkas saat ini, sehingga berkas yang sama
+// tidak didaftarkan ulang dan menggantikan hasil pembaruan online.
+func adopsiVersiLama(tx *gorm.DB, model *config.Model, checksum string) (bool, error) {
+	var jumlahVersi, jumlahChecksum int64
+	if err := tx.Model(&models.QTableVersi{}).Count(&jumlahVersi).Error; err != nil {
+		return false, err
+	}
+	if err := tx.Model(&models.QTableVersi{}).Where("checksum <> ''").Count(&jumlahChecksum).Error; err != nil {
+		return false, err
+	}
+	if jumlahVersi == 0 || jumlahChecksum > 0 {
+		return false, nil
+	}
+
+	var tanpaHistoris []uint
+	if err := tx.Model(&models.QTableVersi{}).
+		Where("NOT EXISTS (SELECT 1 FROM historis_versis h WHERE h.versi_id = q_table_versis.id)").
+		Pluck("id", &tanpaHistoris).Error; err != nil {
+		return false, err
+	}
+	for _, id := range tanpaHistoris {
+		if err := simpanHistoris(tx, id, model.HistoricalBest); err != nil {
+			return false, err
+		}
+	}
+
+	var pertama models.QTableVersi
+	err := tx.Where("sumber = ?", models.SumberQTableFile).Order("id").First(&pertama).Error
+	if err == nil {
+		err = tx.Model(&pertama).Update("checksum", checksum).Error
+	} else if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = nil
+	}
+	return true, err
+}
+
+// daftarkanModel menyimpan model sebagai versi baru kecuali checksum-nya sudah
+// pernah terdaftar.
+func daftarkanModel(db *gorm.DB, versi *models.QTableVersi, model *config.Model, aktifkan bool) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", qTableLockKey).Error; err != nil {
+			return err
+		}
+
+		var ada models.QTableVersi
+		err := tx.Where("checksum = ?", versi.Checksum).Order("id").First(&ada).Error
+		if err == nil {
+			*versi = ada
+			return errModelSudahTerdaftar
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if versi.Sumber == models.SumberQTableFile {
+			adopsi, err := adopsiVersiLama(tx, model, versi.Checksum)
+			if err != nil || adopsi {
+				return err
+			}
+		}
+		return simpanVersiModel(tx, versi, model, aktifkan)
+	})
+}
+
+// SinkronkanModel menyamakan model di memori dengan berkas model dan database.
+// Berkas yang isinya berubah didaftarkan sebagai versi baru yang aktif, lalu
+// versi aktif di database dimuat jika berbeda dari yang sedang dipakai, mis.
+// karena diganti oleh replika lain.
+func SinkronkanModel(db *gorm.DB) error {
+	model, checksum, err := config.BacaBerkasModel()
+	if err != nil {
+		// Berkas yang tidak valid diabaikan; model yang sedang dipakai tetap aktif.
+		logrus.WithError(err).Warn("Berkas model tidak valid, tidak didaftarkan")
+	} else {
+		versi := models.QTableVersi{Sumber: models.SumberQTableFile, Checksum: checksum, Catatan: "Dimuat dari q_table_model.json"}
+		err := daftarkanModel(db, &versi, model, true)
+		if err != nil && !errors.Is(err, errModelSudahTerdaftar) {
+			return fmt.Errorf("gagal mendaftarkan berkas model: %w", err)
+		}
+		if err == nil && versi.ID != 0 {
+			logrus.WithField("versiID", versi.ID).Info("Berkas model baru didaftarkan sebagai versi aktif")
+		}
+	}
+
+	versi, err := versiAktif(db)
+	if err != nil {
+		return fmt.Errorf("versi model aktif tidak ditemukan: %w", err)
+	}
+	if versi.ID == config.ActiveModel().VersiID {
+		return nil
+	}
+
+	aktif, err := muatModel(db, versi.ID)
+	if err != nil {
+		return err
+	}
+	config.SetModel(aktif)
+	logrus.WithFields(logrus.Fields{
+		"versiID":       versi.ID,
+		"jumlahState":   len(aktif.QTable),
+		"jumlahHistori": len(aktif.HistoricalBest),
+	}).Info("Model rekomendasi aktif berhasil dimuat")
+	return nil
+}
+
+// intervalSinkronModelFromEnv membaca MODEL_SYNC_INTERVAL (durasi Go, mis.
+// "30s"). Nilai "0" mematikan pemantauan.
+func intervalSinkronModelFromEnv() time.Duration {
+	v := os.Getenv("MODEL_SYNC_INTERVAL")
+	if v == "" {
+		return defaultIntervalSinkronModel
+	}
+	if v == "0" {
+		return 0
+	}
+	interval, err := time.ParseDuration(v)
+	if err != nil || interval <= 0 {
+		logrus.WithField("nilai", v).Warn("MODEL_SYNC_INTERVAL tidak valid, memakai default")
+		return defaultIntervalSinkronModel
+	}
+	return interval
+}
+
+// PantauModel menjalankan SinkronkanModel secara berkala sampai ctx selesai.
+// Berbeda dengan job scheduler, pemantauan ini berjalan di setiap replika agar
+// semua replika memakai versi aktif yang sama.
+func PantauModel(ctx context.Context, db *gorm.DB) {
+	interval := intervalSinkronModelFromEnv()
+	if interval == 0 {
+		logrus.Info("Pemantauan model dinonaktifkan")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := SinkronkanModel(db); err != nil {
+				logrus.WithError(err).Error("Gagal menyinkronkan model rekomendasi")
+			}
+		}
+	}
+}
+
+// UnggahModel mendaftarkan model hasil pelatihan ulang sebagai versi baru.
+func (s *QLearningService) UnggahModel(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(*utils.Claims)
+	if !ok || claims == nil {
+		return utils.ResponseError(c, fiber.StatusUnauthorized, "Unauthorized: Token tidak valid atau tidak ada", nil)
+	}
+
+	log := logrus.WithFields(logrus.Fields{
+		"handler":     "UnggahModel",
+		"requesterID": claims.ID,
+	})
+
+	var req dto.UnggahModelRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.WithError(err).Error("Gagal mem-parsing request body")
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Cannot parse request body", err.Error())
+	}
+	if len(req.QTable) == 0 {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "q_table wajib diisi", nil)
+	}
+
+	tabel, err := config.ParseQTable(req.QTable)
+	if err != nil {
+		log.WithError(err).Warn("Q-table yang diunggah tidak valid")
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Q-table tidak valid", err.Error())
+	}
+	model := &config.Model{QTable: tabel, HistoricalBest: config.ActiveModel().HistoricalBest}
+	if len(req.HistoricalBest) > 0 {
+		if model.HistoricalBest, err = config.ParseHistoricalBest(req.HistoricalBest); err != nil {
+			log.WithError(err).Warn("Data historis yang diunggah tidak valid")
+			return utils.ResponseError(c, fiber.StatusBadRequest, "Data historis tidak valid", err.Error())
+		}
+	}
+
+	aktifkan := req.Aktifkan == nil || *req.Aktifkan
+	versi := models.QTableVersi{
+		Sumber:   models.SumberQTableUpload,
+		Catatan:  req.Catatan,
+		Checksum: config.ChecksumModel(req.QTable, req.HistoricalBest),
+	}
+	if err := daftarkanModel(s.DB, &versi, model, aktifkan); err != nil {
+		if errors.Is(err, errModelSudahTerdaftar) {
+			return utils.ResponseError(c, fiber.StatusConflict, err.Error(), fmt.Sprintf("versi %d", versi.ID))
+		}
+		log.WithError(err).Error("Gagal menyimpan model")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal menyimpan model", err.Error())
+	}
+
+	if aktifkan {
+		config.SetModel(model)
+	}
+	log.WithFields(logrus.Fields{"versiID": versi.ID, "aktif": aktifkan}).Info("Model baru berhasil diunggah")
+	return utils.SuccessResponse(c, fiber.StatusCreated, "Model berhasil diunggah", toQTableVersiResponse(versi))
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/habbazettt/muraja-server/dto"
+	"github.com/habbazettt/muraja-server/mushaf"
+	"github.com/habbazettt/muraja-server/utils"
+	"gorm.io/gorm"
+)
+
+type MushafService struct {
+	DB *gorm.DB
+}
+
+// layoutDariQuery memakai ?layout jika diisi, selain itu layout pilihan user.
+func (s *MushafService) layoutDariQuery(c *fiber.Ctx) (*mushaf.Layout, error) {
+	if kode := c.Query("layout"); kode != "" {
+		return mushaf.Get(kode)
+	}
+
+	claims, ok := c.Locals("user").(*utils.Claims)
+	if !ok || claims == nil {
+		return mushaf.Default(), nil
+	}
+	return layoutUser(s.DB, claims.ID)
+}
+
+func (s *MushafService) GetAllLayouts(c *fiber.Ctx) error {
+	all := mushaf.All()
+	response := make([]dto.LayoutMushafResponse, len(all))
+	for i, layout := range all {
+		response[i] = dto.LayoutMushafResponse{
+			Kode:         layout.Kode,
+			Nama:         layout.Nama,
+			TotalHalaman: layout.TotalHalaman,
+			Catatan:      layout.Catatan,
+		}
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Daftar layout mushaf berhasil diambil", response)
+}
+
+func (s *MushafService) GetLayout(c *fiber.Ctx) error {
+	layout, err := s.layoutDariQuery(c)
+	if err != nil {
+		return utils.ResponseError(c, fiber.StatusBadRequest, err.Error(), nil)
+	}
+
+	response := dto.LayoutMushafResponse{
+		Kode:         layout.Kode,
+		Nama:         layout.Nama,
+		TotalHalaman: layout.TotalHalaman,
+		Catatan:      layout.Catatan,
+		Juz:          make([]dto.JuzMushafResponse, mushaf.JumlahJuz),
+	}
+	for juz := 1; juz <= mushaf.JumlahJuz; juz++ {
+		response.Juz[juz-1] = dto.JuzMushafResponse{
+			Juz:           juz,
+			HalamanAwal:   layout.AwalHalamanJuz(juz),
+			JumlahHalaman: layout.JumlahHalamanJuz(juz),
+			AyatAwal:      mushaf.AwalJuz(juz).String(),
+		}
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Layout mushaf berhasil diambil", response)
+}
+
+func (s *MushafService) GetHalaman(c *fiber.Ctx) error {
+	layout, err := s.layoutDariQuery(c)
+	if err != nil {
+		return utils.ResponseError(c, fiber.StatusBadRequest, err.Error(), nil)
+	}
+
+	halaman, err := c.ParamsInt("halaman")
+	if err != nil {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Nomor halaman tidak valid", nil)
+	}
+
+	awal, akhir, err := layout.RentangAyatHalaman(halaman)
+	if err != nil {
+		return utils.ResponseError(c, fiber.StatusBadRequest, err.Error(), nil)
+	}
+
+	juz, halamanDiJuz := layout.JuzHalamanOf(halaman)
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Halaman mushaf berhasil diambil", dto.HalamanMushafResponse{
+		Halaman:      halaman,
+		Juz:          juz,
+		HalamanDiJuz: halamanDiJuz,
+		AyatAwal:     awal.String(),
+		AyatAkhir:    akhir.String(),
+		SurahAwal:    mushaf.NamaSurah(awal.Surah),
+		SurahAkhir:   mushaf.NamaSurah(akhir.Surah),
+	})
+}
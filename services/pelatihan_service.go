@@ -0,0 +1,103 @@
+package services
+
+import (
+	"time"
+
+	"github.com/habbazettt/muraja-server/config"
+	"github.com/habbazettt/muraja-server/models"
+	"github.com/habbazettt/muraja-server/qlearning"
+	"gorm.io/gorm"
+)
+
+// batasEfektif adalah skor EfektifitasJadwal minimal agar sebuah jadwal
+// dihitung efektif di data historis.
+const batasEfektif = 4
+
+// DataLatih adalah bahan pelatihan Q-table yang dibangun dari database.
+type DataLatih struct {
+	Episode  []qlearning.Episode
+	Historis []config.HistoricalInfo
+	// Dilewati adalah jumlah jadwal personal yang tidak bisa dijadikan episode:
+	// jadwal kosong, hafalan di luar kategori, atau tanpa penilaian maupun log.
+	Dilewati int
+}
+
+// stateJadwalPersonal menyusun state model dari kesibukan dan total hafalan
+// sebuah jadwal personal, dengan kategori hafalan dari rentang.
+func stateJadwalPersonal(j models.JadwalPersonal, rentang []qlearning.RentangHafalan) (string, bool) {
+	kategori, ok := qlearning.KategoriDariJuz(rentang, j.TotalHafalan)
+	kesibukan := qlearning.NormalisasiKesibukan(j.Kesibukan)
+	if !ok || kesibukan == "" {
+		return "", false
+	}
+	return qlearning.State(kesibukan, kategori), true
+}
+
+// SiapkanDataLatih membangun satu episode dari setiap JadwalPersonal: state
+// dari kesibukan dan kategori hafalan, aksi dari jadwal, dan reward dari
+// EfektifitasJadwal serta tingkat penyelesaian log harian sejak tanggal sejak.
+// Data historis dihitung dari jadwal yang sudah dinilai.
+func SiapkanDataLatih(db *gorm.DB, sejak time.Time) (DataLatih, error) {
+	var data DataLatih
+
+	var jadwal []models.JadwalPersonal
+	if err := db.Order("user_id").Find(&jadwal).Error; err != nil {
+		return data, err
+	}
+
+	var logs []struct {
+		UserID  uint
+		Target  int
+		Selesai int
+	}
+	if err := db.Model(&models.LogHarian{}).
+		Select("user_id, COALESCE(SUM(total_target_halaman), 0) as target, COALESCE(SUM(total_selesai_halaman), 0) as selesai").
+		Where("tanggal >= ?", sejak).
+		Group("user_id").Scan(&logs).Error; err != nil {
+		return data, err
+	}
+	tingkat := make(map[uint]float64, len(logs))
+	for _, l := range logs {
+		if l.Target > 0 {
+			tingkat[l.UserID] = float64(l.Selesai) / float64(l.Target)
+		}
+	}
+
+	historis := make(map[string]*config.HistoricalInfo)
+	for _, j := range jadwal {
+		aksi := j.Jadwal.String()
+		state, ok := stateJadwalPersonal(j, qlearning.KategoriHafalan)
+		tingkatSelesai, adaLog := tingkat[j.UserID]
+		if aksi == "" || !ok || (j.EfektifitasJadwal <= 0 && !adaLog) {
+			data.Dilewati++
+			continue
+		}
+
+		data.Episode = append(data.Episode, qlearning.Episode{
+			State:  state,
+			Aksi:   aksi,
+			Reward: qlearning.Reward(j.EfektifitasJadwal, tingkatSelesai),
+		})
+
+		if j.EfektifitasJadwal <= 0 {
+			continue
+		}
+		info := historis[aksi]
+		if info == nil {
+			info = &config.HistoricalInfo{Jadwal: aksi}
+			historis[aksi] = info
+		}
+		info.TotalPenggunaan++
+		if j.EfektifitasJadwal >= batasEfektif {
+			info.PenggunaanEfektif++
+		}
+	}
+
+	for _, info := range historis {
+		info.PersentaseEfektif = float64(info.PenggunaanEfektif) / float64(info.TotalPenggunaan) * 100
+		data.Historis = append(data.Historis, *info)
+	}
+	config.SortHistoricalBest(data.Historis)
+
+	return data, nil
+}
@@ -0,0 +1,614 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/habbazettt/muraja-server/dto"
+	"github.com/habbazettt/muraja-server/models"
+	"github.com/habbazettt/muraja-server/notifier"
+	"github.com/habbazettt/muraja-server/sholat"
+	"github.com/habbazettt/muraja-server/slot"
+	"github.com/habbazettt/muraja-server/srs"
+	"github.com/habbazettt/muraja-server/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// jendelaPengingat adalah toleransi keterlambatan job; pengingat yang jatuh
+	// tempo lebih lama dari ini dianggap terlewat dan tidak dikirim.
+	jendelaPengingat = 10 * time.Minute
+	maksKanalPerUser = 10
+)
+
+type acuanWaktu struct {
+	Waktu  sholat.Waktu
+	Offset time.Duration
+}
+
+// acuanSlot memetakan slot jadwal murojaah ke waktu sholat acuannya.
+var acuanSlot = map[slot.Slot]acuanWaktu{
+	slot.BadaShubuh:   {sholat.Subuh, 20 * time.Minute},
+	slot.Dhuha:        {sholat.Terbit, time.Hour},
+	slot.BadaDzuhur:   {sholat.Dzuhur, 15 * time.Minute},
+	slot.BadaAshar:    {sholat.Ashar, 15 * time.Minute},
+	slot.BadaMaghrib:  {sholat.Maghrib, 15 * time.Minute},
+	slot.BadaIsya:     {sholat.Isya, 20 * time.Minute},
+	slot.SebelumTidur: {sholat.Isya, 2*time.Hour + 30*time.Minute},
+}
+
+type PengingatService struct {
+	DB     *gorm.DB
+	Kanal  map[string]notifier.Notifier
+	Sholat sholat.Provider
+}
+
+func NewPengingatService(db *gorm.DB) *PengingatService {
+	provider, err := sholat.ProviderFromEnv()
+	if err != nil {
+		logrus.WithError(err).Warn("JADWAL_SHOLAT_TETAP tidak valid, memakai jadwal default")
+		provider = sholat.DefaultJadwalTetap
+	}
+
+	return &PengingatService{
+		DB:     db,
+		Kanal:  notifier.KanalFromEnv(),
+		Sholat: provider,
+	}
+}
+
+func zonaUser(user models.User) *time.Location {
+	if loc, err := time.LoadLocation(user.ZonaWaktu); err == nil && user.ZonaWaktu != "" {
+		return loc
+	}
+	return time.UTC
+}
+
+func lokasiUser(user models.User) sholat.Lokasi {
+	lokasi := sholat.Lokasi{Zona: zonaUser(user), Elevasi: user.Elevasi}
+	if user.Latitude != nil && user.Longitude != nil {
+		lokasi.Latitude, lokasi.Longitude = *user.Latitude, *user.Longitude
+	}
+	return lokasi
+}
+
+func kalkulatorUser(user models.User) sholat.Kalkulator {
+	metode, ok := sholat.GetMetode(user.MetodeSholat)
+	if !ok {
+		metode, _ = sholat.GetMetode(sholat.DefaultMetode)
+	}
+	return sholat.Kalkulator{Metode: metode, Madhab: sholat.Madhab(user.Madhab)}
+}
+
+// providerUser menghitung waktu sholat dari koordinat user jika sudah diisi,
+// selain itu memakai jadwal tetap dari konfigurasi server.
+func (s *PengingatService) providerUser(user models.User) sholat.Provider {
+	if user.Latitude == nil || user.Longitude == nil {
+		return s.Sholat
+	}
+	return kalkulatorUser(user)
+}
+
+type slotTerjadwal struct {
+	Slot     string
+	Acuan    *acuanWaktu
+	JadwalAt time.Time
+}
+
+// slotHariIni mengubah slot JadwalPersonal menjadi waktu konkret pada tanggal
+// lokal user. Slot yang tidak dikenali tetap dikembalikan tanpa waktu.
+func (s *PengingatService) slotHariIni(user models.User, jadwal slot.Daftar, tanggal time.Time) ([]slotTerjadwal, error) {
+	waktuSholat, err := s.providerUser(user).JadwalHarian(tanggal, lokasiUser(user))
+	if err != nil {
+		return nil, err
+	}
+
+	hasil := make([]slotTerjadwal, 0, len(jadwal))
+	for _, kode := range slotJadwal(jadwal) {
+		item := slotTerjadwal{Slot: string(kode)}
+		if acuan, ok := acuanSlot[kode]; ok {
+			item.Acuan = &acuan
+			item.JadwalAt = waktuSholat[acuan.Waktu].Add(acuan.Offset)
+		}
+		hasil = append(hasil, item)
+	}
+	return hasil, nil
+}
+
+func tanggalLokal(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// kirimKeKanal mengirim pesan ke semua kanal aktif user.
+func (s *PengingatService) kirimKeKanal(ctx context.Context, kanal []models.KanalNotifikasi, msg notifier.Message) []dto.HasilKirimKanal {
+	hasil := make([]dto.HasilKirimKanal, 0, len(kanal))
+	for _, k := range kanal {
+		r := dto.HasilKirimKanal{KanalID: k.ID, Jenis: k.Jenis}
+
+		n, ok := s.Kanal[k.Jenis]
+		if !ok {
+			r.Error = "kanal belum dikonfigurasi di server"
+			hasil = append(hasil, r)
+			continue
+		}
+
+		msg.To = k.Tujuan
+		if err := n.Send(ctx, msg); err != nil {
+			r.Error = err.Error()
+			if errors.Is(err, notifier.ErrLanggananBerakhir) {
+				s.nonaktifkanKanal(k)
+			}
+		} else {
+			r.Sukses = true
+		}
+		hasil = append(hasil, r)
+	}
+	return hasil
+}
+
+// nonaktifkanKanal mematikan kanal yang tujuannya sudah tidak berlaku agar
+// tidak terus dicoba pada setiap pengingat.
+func (s *PengingatService) nonaktifkanKanal(k models.KanalNotifikasi) {
+	if err := s.DB.Model(&models.KanalNotifikasi{}).Where("id = ?", k.ID).Update("aktif", false).Error; err != nil {
+		logrus.WithError(err).WithField("kanalID", k.ID).Error("Gagal menonaktifkan kanal notifikasi")
+		return
+	}
+	logrus.WithFields(logrus.Fields{"userID": k.UserID, "kanalID": k.ID, "jenis": k.Jenis}).Info("Kanal notifikasi dinonaktifkan karena langganan berakhir")
+}
+
+// pesanPengingat menyusun isi pengingat dari target slot pada log harian
+// tanggal. Log harian ditulis dengan tanggal UTC (srs.Tanggal), sehingga
+// tanggal harus memakai basis yang sama.
+func (s *PengingatService) pesanPengingat(user models.User, kode string, tanggal, jadwalAt time.Time) notifier.Message {
+	var targets []models.DetailLog
+	if err := s.DB.Joins("JOIN log_harians ON log_harians.id = detail_logs.log_harian_id").
+		Where("log_harians.user_id = ? AND log_harians.tanggal = ? AND detail_logs.status = ? AND detail_logs.slot = ?",
+			user.ID, tanggal, models.StatusSesiBelumSelesai, kode).
+		Find(&targets).Error; err != nil {
+		// Pengingat tetap dikirim tanpa daftar target.
+		logrus.WithError(err).WithFields(logrus.Fields{"userID": user.ID, "slot": kode}).Warn("Gagal mengambil target pengingat")
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Assalamu'alaikum %s, sudah waktunya murojaah %s.", user.Nama, kode)
+	detailIDs := make([]uint, 0, len(targets))
+	for _, t := range targets {
+		fmt.Fprintf(&body, "\n- Juz %d hal. %d sampai juz %d hal. %d (%d halaman)",
+			t.TargetStartJuz, t.TargetStartHalaman, t.TargetEndJuz, t.TargetEndHalaman, t.TotalTargetHalaman)
+		detailIDs = append(detailIDs, t.ID)
+	}
+
+	return notifier.Message{
+		Subject: "Pengingat murojaah " + kode,
+		Body:    body.String(),
+		Data: fiber.Map{
+			"slot":           kode,
+			"jadwal_at":      jadwalAt,
+			"detail_log_ids": detailIDs,
+		},
+	}
+}
+
+// KirimPengingatJatuhTempo mengirim pengingat untuk semua slot yang jatuh tempo
+// dalam jendela pengingat terakhir. Dijalankan oleh scheduler setiap menit.
+func (s *PengingatService) KirimPengingatJatuhTempo(ctx context.Context, now time.Time) (string, error) {
+	var userIDs []uint
+	if err := s.DB.Model(&models.KanalNotifikasi{}).Where("aktif = ?", true).Distinct().Pluck("user_id", &userIDs).Error; err != nil {
+		return "", err
+	}
+
+	terkirim := 0
+	for _, userID := range userIDs {
+		var user models.User
+		if err := s.DB.Preload("JadwalPersonal").First(&user, userID).Error; err != nil {
+			continue
+		}
+		if user.JadwalPersonal == nil || len(user.JadwalPersonal.Jadwal) == 0 {
+			continue
+		}
+
+		n, err := s.kirimPengingatUser(ctx, user, now)
+		if err != nil {
+			logrus.WithError(err).WithField("userID", user.ID).Error("Gagal mengirim pengingat")
+			continue
+		}
+		terkirim += n
+	}
+
+	return fmt.Sprintf("%d pengingat dikirim", terkirim), nil
+}
+
+func (s *PengingatService) kirimPengingatUser(ctx context.Context, user models.User, now time.Time) (int, error) {
+	zona := zonaUser(user)
+	slots, err := s.slotHariIni(user, user.JadwalPersonal.Jadwal, now.In(zona))
+	if err != nil {
+		return 0, err
+	}
+
+	var pengaturan []models.PengaturanSlot
+	if err := s.DB.Where("user_id = ?", user.ID).Find(&pengaturan).Error; err != nil {
+		return 0, err
+	}
+	perSlot := make(map[string]models.PengaturanSlot, len(pengaturan))
+	for _, p := range pengaturan {
+		perSlot[p.Slot] = p
+	}
+
+	var kanal []models.KanalNotifikasi
+	if err := s.DB.Where("user_id = ? AND aktif = ?", user.ID, true).Find(&kanal).Error; err != nil {
+		return 0, err
+	}
+
+	terkirim := 0
+	for _, terjadwal := range slots {
+		if terjadwal.Acuan == nil {
+			continue
+		}
+
+		p := perSlot[terjadwal.Slot]
+		if p.Dibisukan {
+			continue
+		}
+		jatuhTempo := terjadwal.JadwalAt
+		if p.TundaSampai != nil && p.TundaSampai.After(jatuhTempo) {
+			jatuhTempo = *p.TundaSampai
+		}
+		if jatuhTempo.After(now) || !jatuhTempo.After(now.Add(-jendelaPengingat)) {
+			continue
+		}
+
+		// Catatan dibuat lebih dulu; unique index memastikan hanya satu
+		// pengiriman per slot per hari meskipun job berjalan di beberapa replika.
+		record := models.PengingatTerkirim{
+			UserID:    user.ID,
+			Slot:      terjadwal.Slot,
+			Tanggal:   tanggalLokal(now, zona),
+			JadwalAt:  jatuhTempo,
+			DikirimAt: now,
+		}
+		res := s.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+		if res.Error != nil {
+			return terkirim, res.Error
+		}
+		if res.RowsAffected == 0 {
+			continue
+		}
+
+		hasil := s.kirimKeKanal(ctx, kanal, s.pesanPengingat(user, terjadwal.Slot, srs.Tanggal(now), jatuhTempo))
+		var errs []string
+		for _, h := range hasil {
+			if h.Sukses {
+				record.JumlahKanal++
+			} else {
+				errs = append(errs, fmt.Sprintf("%s#%d: %s", h.Jenis, h.KanalID, h.Error))
+			}
+		}
+		record.Error = strings.Join(errs, "; ")
+		if err := s.DB.Save(&record).Error; err != nil {
+			return terkirim, err
+		}
+		terkirim++
+	}
+
+	return terkirim, nil
+}
+
+func validasiTujuanKanal(ctx context.Context, jenis, tujuan string) error {
+	switch jenis {
+	case notifier.KanalEmail:
+		if _, err := mail.ParseAddress(tujuan); err != nil {
+			return errors.New("alamat email tidak valid")
+		}
+	case notifier.KanalWebhook:
+		if _, err := notifier.ValidasiURLPublik(ctx, tujuan); err != nil {
+			return fmt.Errorf("URL webhook tidak valid: %w", err)
+		}
+	case notifier.KanalWebPush:
+		var sub notifier.PushSubscription
+		if err := json.Unmarshal([]byte(tujuan), &sub); err != nil || sub.Keys.P256dh == "" || sub.Keys.Auth == "" {
+			return errors.New("push subscription tidak valid")
+		}
+		if _, err := notifier.ValidasiURLPublik(ctx, sub.Endpoint); err != nil {
+			return fmt.Errorf("endpoint push subscription tidak valid: %w", err)
+		}
+	case notifier.KanalLog:
+	default:
+		return errors.New("jenis kanal tidak dikenal")
+	}
+	return nil
+}
+
+// ringkasTujuan menyembunyikan detail tujuan yang panjang atau sensitif.
+func ringkasTujuan(k models.KanalNotifikasi) string {
+	switch k.Jenis {
+	case notifier.KanalWebhook:
+		if u, err := url.Parse(k.Tujuan); err == nil {
+			return u.Scheme + "://" + u.Host
+		}
+	case notifier.KanalWebPush:
+		var sub notifier.PushSubscription
+		if err := json.Unmarshal([]byte(k.Tujuan), &sub); err == nil {
+			if u, err := url.Parse(sub.Endpoint); err == nil {
+				return u.Host
+			}
+		}
+	}
+	return k.Tujuan
+}
+
+func toKanalNotifikasiResponse(k models.KanalNotifikasi) dto.KanalNotifikasiResponse {
+	return dto.KanalNotifikasiResponse{
+		ID:        k.ID,
+		Jenis:     k.Jenis,
+		Tujuan:    ringkasTujuan(k),
+		Aktif:     k.Aktif,
+		CreatedAt: k.CreatedAt,
+	}
+}
+
+func (s *PengingatService) GetAllKanal(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(*utils.Claims)
+	if !ok || claims == nil {
+		return utils.ResponseError(c, fiber.StatusUnauthorized, "Unauthorized: Token tidak valid atau tidak ada", nil)
+	}
+
+	var kanal []models.KanalNotifikasi
+	if err := s.DB.Where("user_id = ?", claims.ID).Order("created_at").Find(&kanal).Error; err != nil {
+		logrus.WithError(err).Error("Gagal mengambil kanal notifikasi")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal mengambil kanal notifikasi", err.Error())
+	}
+
+	response := make([]dto.KanalNotifikasiResponse, len(kanal))
+	for i, k := range kanal {
+		response[i] = toKanalNotifikasiResponse(k)
+	}
+	return utils.SuccessResponse(c, fiber.StatusOK, "Kanal notifikasi berhasil diambil", response)
+}
+
+func (s *PengingatService) CreateKanal(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(*utils.Claims)
+	if !ok || claims == nil {
+		return utils.ResponseError(c, fiber.StatusUnauthorized, "Unauthorized: Token tidak valid atau tidak ada", nil)
+	}
+
+	var req dto.CreateKanalNotifikasiRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Request body tidak valid", err.Error())
+	}
+	req.Jenis = strings.ToLower(strings.TrimSpace(req.Jenis))
+	req.Tujuan = strings.TrimSpace(req.Tujuan)
+
+	if err := validasiTujuanKanal(c.UserContext(), req.Jenis, req.Tujuan); err != nil {
+		return utils.ResponseError(c, fiber.StatusBadRequest, err.Error(), nil)
+	}
+	if _, ok := s.Kanal[req.Jenis]; !ok {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Kanal "+req.Jenis+" belum dikonfigurasi di server", nil)
+	}
+
+	var jumlah int64
+	if err := s.DB.Model(&models.KanalNotifikasi{}).Where("user_id = ?", claims.ID).Count(&jumlah).Error; err != nil {
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal menyimpan kanal notifikasi", err.Error())
+	}
+	if jumlah >= maksKanalPerUser {
+		return utils.ResponseError(c, fiber.StatusBadRequest, fmt.Sprintf("Maksimal %d kanal notifikasi per user", maksKanalPerUser), nil)
+	}
+
+	kanal := models.KanalNotifikasi{
+		UserID: claims.ID,
+		Jenis:  req.Jenis,
+		Tujuan: req.Tujuan,
+		Aktif:  true,
+	}
+	if err := s.DB.Create(&kanal).Error; err != nil {
+		logrus.WithError(err).Error("Gagal menyimpan kanal notifikasi")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal menyimpan kanal notifikasi", err.Error())
+	}
+
+	logrus.WithFields(logrus.Fields{"userID": claims.ID, "kanalID": kanal.ID, "jenis": kanal.Jenis}).Info("Kanal notifikasi ditambahkan")
+	return utils.SuccessResponse(c, fiber.StatusCreated, "Kanal notifikasi berhasil ditambahkan", toKanalNotifikasiResponse(kanal))
+}
+
+func (s *PengingatService) DeleteKanal(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(*utils.Claims)
+	if !ok || claims == nil {
+		return utils.ResponseError(c, fiber.StatusUnauthorized, "Unauthorized: Token tidak valid atau tidak ada", nil)
+	}
+
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "ID kanal tidak valid", nil)
+	}
+
+	res := s.DB.Where("id = ? AND user_id = ?", id, claims.ID).Delete(&models.KanalNotifikasi{})
+	if res.Error != nil {
+		logrus.WithError(res.Error).Error("Gagal menghapus kanal notifikasi")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal menghapus kanal notifikasi", res.Error.Error())
+	}
+	if res.RowsAffected == 0 {
+		return utils.ResponseError(c, fiber.StatusNotFound, "Kanal notifikasi tidak ditemukan", nil)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Kanal notifikasi berhasil dihapus", nil)
+}
+
+// KirimTes mengirim pesan percobaan ke semua kanal aktif milik user.
+func (s *PengingatService) KirimTes(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(*utils.Claims)
+	if !ok || claims == nil {
+		return utils.ResponseError(c, fiber.StatusUnauthorized, "Unauthorized: Token tidak valid atau tidak ada", nil)
+	}
+
+	var kanal []models.KanalNotifikasi
+	if err := s.DB.Where("user_id = ? AND aktif = ?", claims.ID, true).Find(&kanal).Error; err != nil {
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal mengambil kanal notifikasi", err.Error())
+	}
+	if len(kanal) == 0 {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Belum ada kanal notifikasi aktif", nil)
+	}
+
+	hasil := s.kirimKeKanal(c.Context(), kanal, notifier.Message{
+		Subject: "Tes pengingat murojaah",
+		Body:    "Kanal notifikasi Anda sudah tersambung.",
+	})
+	return utils.SuccessResponse(c, fiber.StatusOK, "Pesan percobaan selesai dikirim", hasil)
+}
+
+func (s *PengingatService) GetVapidPublicKey(c *fiber.Ctx) error {
+	key := os.Getenv("VAPID_PUBLIC_KEY")
+	if _, ok := s.Kanal[notifier.KanalWebPush]; !ok || key == "" {
+		return utils.ResponseError(c, fiber.StatusNotFound, "Web push belum dikonfigurasi", nil)
+	}
+	return utils.SuccessResponse(c, fiber.StatusOK, "VAPID public key", fiber.Map{"public_key": key})
+}
+
+// GetSlotHariIni menampilkan slot jadwal user hari ini beserta waktu
+// pengingatnya dan status bisu/tunda.
+func (s *PengingatService) GetSlotHariIni(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(*utils.Claims)
+	if !ok || claims == nil {
+		return utils.ResponseError(c, fiber.StatusUnauthorized, "Unauthorized: Token tidak valid atau tidak ada", nil)
+	}
+
+	var user models.User
+	if err := s.DB.Preload("JadwalPersonal").First(&user, claims.ID).Error; err != nil {
+		return utils.ResponseError(c, fiber.StatusNotFound, "User tidak ditemukan", nil)
+	}
+	if user.JadwalPersonal == nil {
+		return utils.SuccessResponse(c, fiber.StatusOK, "Belum ada jadwal personal", []dto.SlotPengingatResponse{})
+	}
+
+	zona := zonaUser(user)
+	now := time.Now()
+	slots, err := s.slotHariIni(user, user.JadwalPersonal.Jadwal, now.In(zona))
+	if err != nil {
+		logrus.WithError(err).Error("Gagal menghitung jadwal pengingat")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal menghitung jadwal pengingat", err.Error())
+	}
+
+	var pengaturan []models.PengaturanSlot
+	s.DB.Where("user_id = ?", user.ID).Find(&pengaturan)
+	var terkirim []models.PengingatTerkirim
+	s.DB.Where("user_id = ? AND tanggal = ?", user.ID, tanggalLokal(now, zona)).Find(&terkirim)
+
+	response := make([]dto.SlotPengingatResponse, len(slots))
+	for i, terjadwal := range slots {
+		item := dto.SlotPengingatResponse{Slot: terjadwal.Slot, Dikenali: terjadwal.Acuan != nil}
+		if terjadwal.Acuan != nil {
+			jadwalAt := terjadwal.JadwalAt
+			item.JadwalAt = &jadwalAt
+			item.WaktuAcuan = string(terjadwal.Acuan.Waktu)
+		}
+		for _, p := range pengaturan {
+			if p.Slot == terjadwal.Slot {
+				item.Dibisukan = p.Dibisukan
+				if p.TundaSampai != nil && p.TundaSampai.After(now) {
+					item.TundaSampai = p.TundaSampai
+				}
+			}
+		}
+		for _, t := range terkirim {
+			if t.Slot == terjadwal.Slot {
+				dikirim := t.DikirimAt
+				item.TerkirimAt = &dikirim
+			}
+		}
+		response[i] = item
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Jadwal pengingat berhasil diambil", response)
+}
+
+func (s *PengingatService) simpanPengaturanSlot(userID uint, kode string, values map[string]interface{}) error {
+	pengaturan := models.PengaturanSlot{UserID: userID, Slot: kode}
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where(models.PengaturanSlot{UserID: userID, Slot: kode}).FirstOrCreate(&pengaturan).Error; err != nil {
+			return err
+		}
+		return tx.Model(&pengaturan).Updates(values).Error
+	})
+}
+
+// UpdatePengaturanSlot membisukan atau mengaktifkan kembali pengingat sebuah slot.
+func (s *PengingatService) UpdatePengaturanSlot(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(*utils.Claims)
+	if !ok || claims == nil {
+		return utils.ResponseError(c, fiber.StatusUnauthorized, "Unauthorized: Token tidak valid atau tidak ada", nil)
+	}
+
+	var req dto.UpdatePengaturanSlotRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Request body tidak valid", err.Error())
+	}
+	kode := slot.Normalisasi(req.Slot)
+	if _, ok := acuanSlot[kode]; !ok {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Slot tidak dikenal", nil)
+	}
+
+	if err := s.simpanPengaturanSlot(claims.ID, string(kode), map[string]interface{}{"dibisukan": req.Dibisukan}); err != nil {
+		logrus.WithError(err).Error("Gagal menyimpan pengaturan slot")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal menyimpan pengaturan slot", err.Error())
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Pengaturan slot berhasil disimpan", fiber.Map{
+		"slot":      kode,
+		"dibisukan": req.Dibisukan,
+	})
+}
+
+// TundaSlot menunda pengingat sebuah slot selama beberapa menit dari sekarang.
+func (s *PengingatService) TundaSlot(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(*utils.Claims)
+	if !ok || claims == nil {
+		return utils.ResponseError(c, fiber.StatusUnauthorized, "Unauthorized: Token tidak valid atau tidak ada", nil)
+	}
+
+	var req dto.TundaSlotRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Request body tidak valid", err.Error())
+	}
+	if req.Menit < 1 || req.Menit > 240 {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Durasi tunda harus antara 1 dan 240 menit", nil)
+	}
+	kode := slot.Normalisasi(req.Slot)
+	if _, ok := acuanSlot[kode]; !ok {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Slot tidak dikenal", nil)
+	}
+
+	now := time.Now()
+	tundaSampai := now.UTC().Add(time.Duration(req.Menit) * time.Minute)
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := (&PengingatService{DB: tx}).simpanPengaturanSlot(claims.ID, string(kode), map[string]interface{}{"tunda_sampai": tundaSampai}); err != nil {
+			return err
+		}
+
+		// Pengingat hari ini yang sudah terkirim dihapus agar bisa dikirim lagi
+		// setelah masa tunda berakhir. Tanggalnya mengikuti hari slot itu di
+		// zona user, bukan hari berakhirnya tunda yang bisa lewat tengah malam.
+		var user models.User
+		if err := tx.Select("id", "zona_waktu").First(&user, claims.ID).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ? AND slot = ? AND tanggal = ?", claims.ID, string(kode), tanggalLokal(now, zonaUser(user))).
+			Delete(&models.PengingatTerkirim{}).Error
+	})
+	if err != nil {
+		logrus.WithError(err).Error("Gagal menunda pengingat")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal menunda pengingat", err.Error())
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Pengingat berhasil ditunda", fiber.Map{
+		"slot":         kode,
+		"tunda_sampai": tundaSampai,
+	})
+}
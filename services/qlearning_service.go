@@ -0,0 +1,492 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/habbazettt/muraja-server/config"
+	"github.com/habbazettt/muraja-server/dto"
+	"github.com/habbazettt/muraja-server/models"
+	"github.com/habbazettt/muraja-server/qlearning"
+	"github.com/habbazettt/muraja-server/slot"
+	"github.com/habbazettt/muraja-server/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// qTableLockKey adalah kunci advisory lock transaksi untuk semua perubahan
+	// versi Q-table, agar dua replika tidak mengaktifkan versi bersamaan.
+	qTableLockKey              = 7_240_017
+	defaultJendelaEvaluasiHari = 7
+)
+
+var errVersiQTableTidakDitemukan = errors.New("versi Q-table tidak ditemukan")
+
+type QLearningService struct {
+	DB *gorm.DB
+}
+
+// jendelaEvaluasiFromEnv membaca QLEARNING_JENDELA_HARI: lama hari setelah
+// rekomendasi diterapkan yang dipakai untuk menghitung tingkat penyelesaian.
+func jendelaEvaluasiFromEnv() int {
+	if v := os.Getenv("QLEARNING_JENDELA_HARI"); v != "" {
+		if hari, err := strconv.Atoi(v); err == nil && hari > 0 {
+			return hari
+		}
+		logrus.WithField("nilai", v).Warn("QLEARNING_JENDELA_HARI tidak valid, memakai default")
+	}
+	return defaultJendelaEvaluasiHari
+}
+
+func muatNilaiQ(db *gorm.DB, versiID uint) (qlearning.Tabel, error) {
+	var nilai []models.QValue
+	if err := db.Where("versi_id = ?", versiID).Find(&nilai).Error; err != nil {
+		return nil, err
+	}
+
+	tabel := make(qlearning.Tabel)
+	for _, v := range nilai {
+		if tabel[v.State] == nil {
+			tabel[v.State] = make(map[string]float64)
+		}
+		tabel[v.State][v.Aksi] = v.Nilai
+	}
+	return tabel, nil
+}
+
+// muatModel memuat Q-table dan data historis sebuah versi model.
+func muatModel(db *gorm.DB, versiID uint) (*config.Model, error) {
+	tabel, err := muatNilaiQ(db, versiID)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []models.HistorisVersi
+	if err := db.Where("versi_id = ?", versiID).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	historis := make([]config.HistoricalInfo, len(rows))
+	for i, r := range rows {
+		historis[i] = config.HistoricalInfo{
+			Jadwal:            r.Jadwal,
+			TotalPenggunaan:   r.TotalPenggunaan,
+			PenggunaanEfektif: r.PenggunaanEfektif,
+			PersentaseEfektif: r.PersentaseEfektif,
+		}
+	}
+	config.SortHistoricalBest(historis)
+
+	return &config.Model{VersiID: versiID, QTable: tabel, HistoricalBest: historis}, nil
+}
+
+func simpanHistoris(tx *gorm.DB, versiID uint, historis []config.HistoricalInfo) error {
+	if len(historis) == 0 {
+		return nil
+	}
+	rows := make([]models.HistorisVersi, len(historis))
+	for i, h := range historis {
+		rows[i] = models.HistorisVersi{
+			VersiID:           versiID,
+			Jadwal:            h.Jadwal,
+			TotalPenggunaan:   h.TotalPenggunaan,
+			PenggunaanEfektif: h.PenggunaanEfektif,
+			PersentaseEfektif: h.PersentaseEfektif,
+		}
+	}
+	return tx.CreateInBatches(rows, 500).Error
+}
+
+// simpanVersiModel menyimpan model sebagai versi baru dan mengisi
+// model.VersiID. Jika aktifkan, versi lain dinonaktifkan.
+func simpanVersiModel(tx *gorm.DB, versi *models.QTableVersi, model *config.Model, aktifkan bool) error {
+	if aktifkan {
+		if err := tx.Model(&models.QTableVersi{}).Where("aktif = ?", true).Update("aktif", false).Error; err != nil {
+			return err
+		}
+	}
+
+	versi.Aktif = aktifkan
+	if err := tx.Create(versi).Error; err != nil {
+		return err
+	}
+	model.VersiID = versi.ID
+
+	nilai := make([]models.QValue, 0, len(model.QTable)*5)
+	for state, aksi := range model.QTable {
+		for a, q := range aksi {
+			nilai = append(nilai, models.QValue{VersiID: versi.ID, State: state, Aksi: a, Nilai: q})
+		}
+	}
+	if len(nilai) > 0 {
+		if err := tx.CreateInBatches(nilai, 500).Error; err != nil {
+			return err
+		}
+	}
+	return simpanHistoris(tx, versi.ID, model.HistoricalBest)
+}
+
+// versiAktif mengambil versi Q-table aktif. Pemanggil yang akan mengubah versi
+// harus memegang advisory lock qTableLockKey.
+func versiAktif(tx *gorm.DB) (models.QTableVersi, error) {
+	var versi models.QTableVersi
+	err := tx.Where("aktif = ?", true).Order("id DESC").First(&versi).Error
+	return versi, err
+}
+
+// tingkatSelesaiUser menghitung rasio halaman selesai terhadap target pada
+// log harian user di rentang [mulai, selesai).
+func tingkatSelesaiUser(db *gorm.DB, userID uint, mulai, selesai time.Time) (float64, error) {
+	var total struct {
+		Target  int
+		Selesai int
+	}
+	err := db.Model(&models.LogHarian{}).
+		Select("COALESCE(SUM(total_target_halaman), 0) as target, COALESCE(SUM(total_selesai_halaman), 0) as selesai").
+		Where("user_id = ? AND tanggal >= ? AND tanggal < ?", userID, mulai, selesai).
+		Scan(&total).Error
+	if err != nil || total.Target == 0 {
+		return 0, err
+	}
+	return math.Min(1, float64(total.Selesai)/float64(total.Target)), nil
+}
+
+// PerbaruiQTable memproses rekomendasi yang jendela evaluasinya sudah lewat:
+// menghitung reward dari tingkat penyelesaian log harian dan EfektifitasJadwal
+// yang masih berlaku untuk jadwal tersebut, menerapkan pembaruan Q-learning
+// pada salinan tabel aktif, lalu menyimpannya sebagai versi baru. Dijalankan
+// oleh scheduler setiap hari.
+func PerbaruiQTable(db *gorm.DB, tanggal time.Time, params qlearning.Params, jendelaHari int) (string, error) {
+	tanggal = time.Date(tanggal.Year(), tanggal.Month(), tanggal.Day(), 0, 0, 0, 0, time.UTC)
+	batas := tanggal.AddDate(0, 0, -jendelaHari)
+
+	var model *config.Model
+	var versi models.QTableVersi
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", qTableLockKey).Error; err != nil {
+			return err
+		}
+
+		var rekomendasi []models.JadwalRekomendasi
+		if err := tx.Where("diterapkan_pada IS NOT NULL AND diterapkan_pada <= ? AND diproses_pada IS NULL", batas).
+			Where("NOT EXISTS (SELECT 1 FROM umpan_balik_rekomendasis u WHERE u.rekomendasi_id = jadwal_rekomendasis.id)").
+			// Rekomendasi dari varian eksperimen dipilih oleh model lain; hanya
+			// varian kontrol yang memakai model aktif ikut memperbarui tabel.
+			Where("(varian_id IS NULL OR varian_id IN (SELECT id FROM varian_eksperimens WHERE versi_id IS NULL))").
+			Order("diterapkan_pada, id").
+			Find(&rekomendasi).Error; err != nil {
+			return err
+		}
+		if len(rekomendasi) == 0 {
+			return nil
+		}
+
+		induk, err := versiAktif(tx)
+		if err != nil {
+			return fmt.Errorf("versi Q-table aktif tidak ditemukan: %w", err)
+		}
+		aktif, err := muatModel(tx, induk.ID)
+		if err != nil {
+			return err
+		}
+		// Data historis tidak berubah oleh pembaruan online dan ikut disalin
+		// agar setiap versi lengkap.
+		model = &config.Model{QTable: aktif.QTable.Salin(), HistoricalBest: aktif.HistoricalBest}
+		tabel := model.QTable
+
+		umpanBalik := make([]models.UmpanBalikRekomendasi, 0, len(rekomendasi))
+		diproses := make([]uint, 0, len(rekomendasi))
+		for _, r := range rekomendasi {
+			diproses = append(diproses, r.ID)
+			// Rekomendasi Mirip atau default dibuat untuk state yang tidak ada
+			// di model; umpan baliknya tidak boleh menambah state baru ke tabel.
+			if r.TipeRekomendasi != "Spesifik" && len(aktif.QTable[r.State]) == 0 {
+				continue
+			}
+
+			mulai := *r.DiterapkanPada
+			selesai := mulai.AddDate(0, 0, jendelaHari)
+
+			tingkat, err := tingkatSelesaiUser(tx, r.UserID, mulai, selesai)
+			if err != nil {
+				return err
+			}
+
+			// Penilaian user hanya dipakai jika ditujukan untuk jadwal ini dan
+			// diberikan setelah jadwal diterapkan; selain itu reward hanya
+			// berasal dari tingkat penyelesaian.
+			aksi := r.JadwalDiterapkan()
+			var efektifitas int
+			var jadwal models.JadwalPersonal
+			if err := tx.Where("user_id = ?", r.UserID).First(&jadwal).Error; err == nil {
+				dinilai := jadwal.EfektifitasDinilaiPada
+				if jadwal.Jadwal.String() == slot.Parse(aksi).String() && dinilai != nil && dinilai.After(mulai) {
+					efektifitas = jadwal.EfektifitasJadwal
+				}
+			} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+
+			// Kesibukan dan hafalan user dianggap tetap selama jendela evaluasi,
+			// sehingga state berikutnya sama dengan state rekomendasi.
+			reward := qlearning.Reward(efektifitas, tingkat)
+			qLama, qBaru := tabel.Update(r.State, aksi, reward, r.State, params)
+
+			umpanBalik = append(umpanBalik, models.UmpanBalikRekomendasi{
+				RekomendasiID:  r.ID,
+				UserID:         r.UserID,
+				State:          r.State,
+				Aksi:           aksi,
+				TanggalMulai:   mulai,
+				TanggalSelesai: selesai,
+				TingkatSelesai: tingkat,
+				Efektifitas:    efektifitas,
+				Reward:         reward,
+				QLama:          qLama,
+				QBaru:          qBaru,
+			})
+		}
+
+		if err := tx.Model(&models.JadwalRekomendasi{}).Where("id IN ?", diproses).Update("diproses_pada", time.Now()).Error; err != nil {
+			return err
+		}
+		if len(umpanBalik) == 0 {
+			return nil
+		}
+
+		versi = models.QTableVersi{
+			Sumber:       models.SumberQTableOnline,
+			IndukID:      &induk.ID,
+			Alpha:        params.Alpha,
+			Gamma:        params.Gamma,
+			JumlahUpdate: len(umpanBalik),
+		}
+		if err := simpanVersiModel(tx, &versi, model, true); err != nil {
+			return err
+		}
+
+		for i := range umpanBalik {
+			umpanBalik[i].VersiID = versi.ID
+		}
+		return tx.CreateInBatches(umpanBalik, 200).Error
+	})
+	if err != nil {
+		return "", err
+	}
+	if versi.ID == 0 {
+		return "tidak ada umpan balik baru", nil
+	}
+
+	config.SetModel(model)
+	return fmt.Sprintf("%d umpan balik diterapkan ke Q-table versi %d", versi.JumlahUpdate, versi.ID), nil
+}
+
+func toQTableVersiResponse(v models.QTableVersi) dto.QTableVersiResponse {
+	return dto.QTableVersiResponse{
+		ID:           v.ID,
+		Sumber:       v.Sumber,
+		IndukID:      v.IndukID,
+		Alpha:        v.Alpha,
+		Gamma:        v.Gamma,
+		JumlahUpdate: v.JumlahUpdate,
+		Aktif:        v.Aktif,
+		Catatan:      v.Catatan,
+		Checksum:     v.Checksum,
+		CreatedAt:    v.CreatedAt,
+	}
+}
+
+func (s *QLearningService) GetAllVersiQTable(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	offset := (page - 1) * limit
+
+	var total int64
+	if err := s.DB.Model(&models.QTableVersi{}).Count(&total).Error; err != nil {
+		logrus.WithError(err).Error("Gagal menghitung versi Q-table")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal mengambil versi Q-table", err.Error())
+	}
+
+	var versi []models.QTableVersi
+	if err := s.DB.Order("id DESC").Limit(limit).Offset(offset).Find(&versi).Error; err != nil {
+		logrus.WithError(err).Error("Gagal mengambil versi Q-table")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal mengambil versi Q-table", err.Error())
+	}
+
+	response := make([]dto.QTableVersiResponse, len(versi))
+	for i, v := range versi {
+		response[i] = toQTableVersiResponse(v)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Versi Q-table berhasil diambil", fiber.Map{
+		"pagination": fiber.Map{
+			"current_page": page,
+			"total_data":   total,
+			"total_pages":  int(math.Ceil(float64(total) / float64(limit))),
+		},
+		"versi": response,
+	})
+}
+
+func (s *QLearningService) GetVersiQTable(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "ID versi tidak valid", nil)
+	}
+
+	var versi models.QTableVersi
+	if err := s.DB.First(&versi, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.ResponseError(c, fiber.StatusNotFound, errVersiQTableTidakDitemukan.Error(), nil)
+		}
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal mengambil versi Q-table", err.Error())
+	}
+
+	model, err := muatModel(s.DB, versi.ID)
+	if err != nil {
+		logrus.WithError(err).Error("Gagal mengambil nilai Q")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal mengambil versi Q-table", err.Error())
+	}
+
+	historis := make([]dto.HistorisJadwalResponse, len(model.HistoricalBest))
+	for i, h := range model.HistoricalBest {
+		historis[i] = dto.HistorisJadwalResponse{
+			Jadwal:            h.Jadwal,
+			TotalPenggunaan:   h.TotalPenggunaan,
+			PenggunaanEfektif: h.PenggunaanEfektif,
+			PersentaseEfektif: h.PersentaseEfektif,
+		}
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Versi Q-table berhasil diambil", dto.QTableVersiDetailResponse{
+		QTableVersiResponse: toQTableVersiResponse(versi),
+		QTable:              model.QTable,
+		HistoricalBest:      historis,
+	})
+}
+
+// RollbackQTable mengaktifkan kembali versi model sebelumnya. Pembaruan
+// online berikutnya dilanjutkan dari versi ini.
+func (s *QLearningService) RollbackQTable(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(*utils.Claims)
+	if !ok || claims == nil {
+		return utils.ResponseError(c, fiber.StatusUnauthorized, "Unauthorized: Token tidak valid atau tidak ada", nil)
+	}
+
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "ID versi tidak valid", nil)
+	}
+
+	log := logrus.WithFields(logrus.Fields{
+		"handler":     "RollbackQTable",
+		"versiID":     id,
+		"requesterID": claims.ID,
+	})
+
+	var versi models.QTableVersi
+	var model *config.Model
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", qTableLockKey).Error; err != nil {
+			return err
+		}
+		if err := tx.First(&versi, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errVersiQTableTidakDitemukan
+			}
+			return err
+		}
+
+		var err error
+		if model, err = muatModel(tx, versi.ID); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.QTableVersi{}).Where("aktif = ? AND id <> ?", true, versi.ID).Update("aktif", false).Error; err != nil {
+			return err
+		}
+		versi.Aktif = true
+		return tx.Model(&versi).Update("aktif", true).Error
+	})
+	if err != nil {
+		if errors.Is(err, errVersiQTableTidakDitemukan) {
+			return utils.ResponseError(c, fiber.StatusNotFound, err.Error(), nil)
+		}
+		log.WithError(err).Error("Gagal melakukan rollback Q-table")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal melakukan rollback Q-table", err.Error())
+	}
+
+	config.SetModel(model)
+	log.Info("Q-table berhasil dikembalikan ke versi sebelumnya")
+	return utils.SuccessResponse(c, fiber.StatusOK, "Q-table berhasil di-rollback", toQTableVersiResponse(versi))
+}
+
+func (s *QLearningService) GetAllUmpanBalik(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	offset := (page - 1) * limit
+
+	query := s.DB.Model(&models.UmpanBalikRekomendasi{})
+	if versiID := c.Query("versi_id"); versiID != "" {
+		query = query.Where("versi_id = ?", versiID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		logrus.WithError(err).Error("Gagal menghitung umpan balik rekomendasi")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal mengambil umpan balik", err.Error())
+	}
+
+	var umpanBalik []models.UmpanBalikRekomendasi
+	if err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&umpanBalik).Error; err != nil {
+		logrus.WithError(err).Error("Gagal mengambil umpan balik rekomendasi")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal mengambil umpan balik", err.Error())
+	}
+
+	response := make([]dto.UmpanBalikRekomendasiResponse, len(umpanBalik))
+	for i, u := range umpanBalik {
+		response[i] = dto.UmpanBalikRekomendasiResponse{
+			ID:             u.ID,
+			RekomendasiID:  u.RekomendasiID,
+			UserID:         u.UserID,
+			VersiID:        u.VersiID,
+			State:          u.State,
+			Aksi:           u.Aksi,
+			TanggalMulai:   u.TanggalMulai.Format("02-01-2006"),
+			TanggalSelesai: u.TanggalSelesai.Format("02-01-2006"),
+			TingkatSelesai: u.TingkatSelesai,
+			Efektifitas:    u.Efektifitas,
+			Reward:         u.Reward,
+			QLama:          u.QLama,
+			QBaru:          u.QBaru,
+			CreatedAt:      u.CreatedAt,
+		}
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Umpan balik rekomendasi berhasil diambil", fiber.Map{
+		"pagination": fiber.Map{
+			"current_page": page,
+			"total_data":   total,
+			"total_pages":  int(math.Ceil(float64(total) / float64(limit))),
+		},
+		"umpan_balik": response,
+	})
+}
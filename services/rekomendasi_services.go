@@ -1,23 +1,222 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"math"
-	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/habbazettt/muraja-server/config"
 	"github.com/habbazettt/muraja-server/dto"
 	"github.com/habbazettt/muraja-server/models"
+	"github.com/habbazettt/muraja-server/qlearning"
+	"github.com/habbazettt/muraja-server/slot"
 	"github.com/habbazettt/muraja-server/utils"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
+// kebijakanHistoris menandai rekomendasi fallback dari data historis, yang
+// tidak dipilih oleh kebijakan Q-learning.
+const kebijakanHistoris = "historis"
+
 type RekomendasiService struct {
-	DB *gorm.DB
+	DB        *gorm.DB
+	Kebijakan qlearning.Kebijakan
+}
+
+func NewRekomendasiService(db *gorm.DB) *RekomendasiService {
+	kebijakan, err := qlearning.KebijakanFromEnv()
+	if err != nil {
+		logrus.WithError(err).Warn("Kebijakan rekomendasi tidak valid, memakai greedy")
+	}
+	return &RekomendasiService{DB: db, Kebijakan: kebijakan}
+}
+
+// kunjunganState menghitung berapa kali setiap aksi sudah direkomendasikan
+// pada sebuah state.
+func (s *RekomendasiService) kunjunganState(state string) (map[string]int, error) {
+	var rows []struct {
+		RekomendasiJadwal string
+		Jumlah            int
+	}
+	err := s.DB.Model(&models.JadwalRekomendasi{}).
+		Select("rekomendasi_jadwal, COUNT(*) as jumlah").
+		Where("state = ?", state).
+		Group("rekomendasi_jadwal").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	kunjungan := make(map[string]int, len(rows))
+	for _, r := range rows {
+		kunjungan[r.RekomendasiJadwal] = r.Jumlah
+	}
+	return kunjungan, nil
+}
+
+// maksTopN membatasi jumlah alternatif dalam satu rekomendasi.
+const maksTopN = 10
+
+// maksStateMirip membatasi jumlah state mirip yang nilai Q-nya digabung saat
+// state user tidak ada di model.
+const maksStateMirip = 3
+
+// nilaiQState mengembalikan nilai Q aksi pada state, atau gabungan nilai Q
+// state-state mirip beserta sumbernya jika state tidak ada di model.
+func nilaiQState(model *config.Model, state string) (map[string]float64, []qlearning.StateMirip) {
+	if nilaiQ := model.QTable[state]; len(nilaiQ) > 0 {
+		return nilaiQ, nil
+	}
+	sumber, nilaiQ := model.QTable.Mirip(state, maksStateMirip)
+	return nilaiQ, sumber
+}
+
+// persentaseHistoris mengembalikan persentase efektif historis sebuah jadwal,
+// atau nil jika jadwal tidak ada di data historis.
+func persentaseHistoris(historis []config.HistoricalInfo, jadwal string) *float64 {
+	for _, info := range historis {
+		if info.Jadwal == jadwal {
+			persen := info.PersentaseEfektif
+			return &persen
+		}
+	}
+	return nil
+}
+
+// alasanPeringkat menjelaskan posisi sebuah jadwal di antara aksi pada state.
+func alasanPeringkat(state string, peringkat int, selisih float64, persen *float64) string {
+	kesibukan, kategori := qlearning.UraiState(state)
+	alasan := fmt.Sprintf("Nilai Q tertinggi untuk kesibukan %s dengan hafalan %s", kesibukan, kategori)
+	if peringkat > 1 {
+		alasan = fmt.Sprintf("Peringkat %d untuk kesibukan %s dengan hafalan %s, %.2f poin di bawah jadwal terbaik",
+			peringkat, kesibukan, kategori, selisih)
+	}
+	if persen != nil {
+		alasan += fmt.Sprintf("; efektif %.0f%% secara historis", *persen)
+	}
+	return alasan
+}
+
+// alasanHistoris menjelaskan jadwal yang diambil dari data historis karena
+// state tidak ada di model.
+func alasanHistoris(peringkat int, info config.HistoricalInfo) string {
+	return fmt.Sprintf("State belum ada di model; peringkat %d data historis, efektif %.0f%% dari %d penggunaan",
+		peringkat, info.PersentaseEfektif, info.TotalPenggunaan)
+}
+
+// alternatifQ menyusun hingga topN jadwal berperingkat menurut nilai Q.
+func alternatifQ(state string, nilaiQ map[string]float64, historis []config.HistoricalInfo, topN int) []dto.AlternatifRekomendasi {
+	peringkat := qlearning.Peringkat(nilaiQ)
+	if len(peringkat) > topN {
+		peringkat = peringkat[:topN]
+	}
+
+	alternatif := make([]dto.AlternatifRekomendasi, len(peringkat))
+	for i, a := range peringkat {
+		q := a.Nilai
+		selisih := peringkat[0].Nilai - a.Nilai
+		persen := persentaseHistoris(historis, a.Aksi)
+		alternatif[i] = dto.AlternatifRekomendasi{
+			Peringkat:                 i + 1,
+			RekomendasiJadwal:         a.Aksi,
+			EstimasiQValue:            &q,
+			PersentaseEfektifHistoris: persen,
+			SelisihDariTerbaik:        &selisih,
+			Alasan:                    alasanPeringkat(state, i+1, selisih, persen),
+		}
+	}
+	return alternatif
+}
+
+// alternatifHistoris menyusun hingga topN jadwal dari data historis, yang
+// sudah terurut menurut persentase efektif.
+func alternatifHistoris(historis []config.HistoricalInfo, topN int) []dto.AlternatifRekomendasi {
+	if len(historis) > topN {
+		historis = historis[:topN]
+	}
+
+	alternatif := make([]dto.AlternatifRekomendasi, len(historis))
+	for i, info := range historis {
+		persen := info.PersentaseEfektif
+		alternatif[i] = dto.AlternatifRekomendasi{
+			Peringkat:                 i + 1,
+			RekomendasiJadwal:         info.Jadwal,
+			PersentaseEfektifHistoris: &persen,
+			Alasan:                    alasanHistoris(i+1, info),
+		}
+	}
+	return alternatif
+}
+
+// tentukanState menentukan kesibukan dan kategori hafalan untuk rekomendasi.
+// Nilai dari permintaan dipakai jika ada, selain itu diambil dari jadwal
+// personal user; kategori hafalan dihitung dari TotalHafalan dengan rentang
+// yang dipakai model. Keduanya divalidasi terhadap state yang dikenal model.
+func (s *RekomendasiService) tentukanState(model *config.Model, req dto.RecommendationRequest, userID uint) (string, string, error) {
+	var profil *models.JadwalPersonal
+	if req.Kesibukan == "" || req.KategoriHafalan == "" {
+		var jadwal models.JadwalPersonal
+		err := s.DB.Where("user_id = ?", userID).First(&jadwal).Error
+		if err == nil {
+			profil = &jadwal
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", "", newErrRespons(fiber.StatusInternalServerError, "Gagal mengambil jadwal personal", err.Error())
+		}
+	}
+
+	kesibukan := req.Kesibukan
+	if kesibukan == "" && profil != nil {
+		kesibukan = profil.Kesibukan
+	}
+	kesibukan = qlearning.NormalisasiKesibukan(kesibukan)
+	if kesibukan == "" {
+		return "", "", newErrRespons(fiber.StatusBadRequest, "Kesibukan wajib diisi atau lengkapi jadwal personal terlebih dahulu", nil)
+	}
+
+	// Kombinasi baru dari aktivitas yang dikenal tetap diterima dan dilayani
+	// lewat state mirip.
+	aktivitasDikenal := make(map[string]bool)
+	for _, a := range model.QTable.Aktivitas() {
+		aktivitasDikenal[a] = true
+	}
+	for _, a := range qlearning.Aktivitas(kesibukan) {
+		if !aktivitasDikenal[a] {
+			return "", "", newErrRespons(fiber.StatusBadRequest, fmt.Sprintf("Kesibukan %q tidak dikenal", kesibukan), fiber.Map{
+				"kesibukan_valid": model.QTable.Kesibukan(),
+				"aktivitas_valid": model.QTable.Aktivitas(),
+			})
+		}
+	}
+
+	rentang := model.QTable.RentangHafalan()
+	if req.KategoriHafalan != "" {
+		for _, r := range rentang {
+			if strings.EqualFold(strings.TrimSpace(req.KategoriHafalan), r.String()) {
+				return kesibukan, r.String(), nil
+			}
+		}
+		valid := make([]string, len(rentang))
+		for i, r := range rentang {
+			valid[i] = r.String()
+		}
+		return "", "", newErrRespons(fiber.StatusBadRequest, fmt.Sprintf("Kategori hafalan %q tidak dikenal", req.KategoriHafalan), fiber.Map{
+			"kategori_hafalan_valid": valid,
+		})
+	}
+
+	if profil == nil {
+		return "", "", newErrRespons(fiber.StatusBadRequest, "Jadwal personal belum diisi, kirim kategori_hafalan atau lengkapi total hafalan", nil)
+	}
+	kategori, ok := qlearning.KategoriDariJuz(rentang, profil.TotalHafalan)
+	if !ok {
+		return "", "", newErrRespons(fiber.StatusBadRequest, fmt.Sprintf("Total hafalan %d juz di luar kategori model", profil.TotalHafalan), nil)
+	}
+	return kesibukan, kategori, nil
 }
 
 func (s *RekomendasiService) GetRecommendation(c *fiber.Ctx) error {
@@ -36,50 +235,111 @@ func (s *RekomendasiService) GetRecommendation(c *fiber.Ctx) error {
 		return utils.ResponseError(c, fiber.StatusBadRequest, "Cannot parse request body", err.Error())
 	}
 
-	stateString := fmt.Sprintf("%s_%s", req.Kesibukan, req.KategoriHafalan)
+	if req.TopN < 0 || req.TopN > maksTopN {
+		log.WithField("top_n", req.TopN).Warn("top_n di luar batas")
+		return utils.ResponseError(c, fiber.StatusBadRequest, "top_n tidak valid",
+			fmt.Sprintf("top_n harus di antara 0 dan %d", maksTopN))
+	}
+
+	// Model diambil sekali agar Q-table dan data historis berasal dari versi
+	// yang sama meskipun model diganti di tengah permintaan.
+	model, varian, err := modelUntukUser(s.DB, claims.ID)
+	if err != nil && model == nil {
+		log.WithError(err).Error("Gagal memuat model varian eksperimen")
+		return responsError(c, err)
+	}
+	if err != nil {
+		log.WithError(err).Warn("Gagal menentukan varian eksperimen, memakai model aktif")
+	}
+
+	kesibukan, kategoriHafalan, err := s.tentukanState(model, req, claims.ID)
+	if err != nil {
+		log.WithError(err).Warn("Gagal menentukan state rekomendasi")
+		return responsError(c, err)
+	}
+	stateString := qlearning.State(kesibukan, kategoriHafalan)
 	log = log.WithField("state", stateString)
 
 	var bestAction string
 	var qValue *float64
 	var recType string
-	var persentaseEfektif *float64
+	var alasan string
+	var alternatif []dto.AlternatifRekomendasi
+	kebijakan := qlearning.Kebijakan{Nama: kebijakanHistoris}
+	propensitas := 1.0
+
+	stateActions, stateSumber := nilaiQState(model, stateString)
+	if len(stateSumber) > 0 {
+		recType = "Mirip"
+	} else if len(stateActions) > 0 {
+		recType = "Spesifik"
+	}
+
+	if len(stateActions) > 0 {
+		kunjungan, err := s.kunjunganState(stateString)
+		if err != nil {
+			log.WithError(err).Error("Gagal menghitung kunjungan state")
+			return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal membuat rekomendasi", err.Error())
+		}
 
-	if stateActions, ok := config.QTableModel[stateString]; ok {
-		var maxQ float64 = -1.0
-		isFirst := true
-		for action, val := range stateActions {
-			if isFirst || val > maxQ {
-				maxQ = val
-				bestAction = action
-				isFirst = false
+		kebijakan = s.Kebijakan
+		bestAction, propensitas = kebijakan.Pilih(stateActions, kunjungan, nil)
+		q := stateActions[bestAction]
+		qValue = &q
+
+		alternatif = alternatifQ(stateString, stateActions, model.HistoricalBest, len(stateActions))
+		for _, a := range alternatif {
+			if a.RekomendasiJadwal == bestAction {
+				alasan = a.Alasan
+				if a.Peringkat > 1 {
+					alasan = fmt.Sprintf("Dipilih kebijakan %s untuk eksplorasi; %s", kebijakan.Nama, alasan)
+				}
+				break
 			}
 		}
-		qValue = &maxQ
-		recType = "Spesifik"
+		if len(stateSumber) > 0 {
+			sumber := make([]string, len(stateSumber))
+			for i, m := range stateSumber {
+				sumber[i] = fmt.Sprintf("%s (%.2f)", m.State, m.Kemiripan)
+			}
+			alasan = fmt.Sprintf("State belum ada di model, nilai Q diperkirakan dari state mirip %s; %s",
+				strings.Join(sumber, ", "), alasan)
+		}
 	} else {
-		if len(config.HistoricalBest) > 0 {
-			bestAction = config.HistoricalBest[0].Jadwal
+		if len(model.HistoricalBest) > 0 {
+			bestAction = model.HistoricalBest[0].Jadwal
 			recType = "Umum (Historis Terbaik)"
+			alternatif = alternatifHistoris(model.HistoricalBest, len(model.HistoricalBest))
+			alasan = alternatif[0].Alasan
 		} else {
 			bestAction = "Tidak ada jadwal default"
 			recType = "Tidak Ada Rekomendasi"
 		}
 	}
 
-	if bestAction != "Tidak ada jadwal default" && len(config.HistoricalBest) > 0 {
-		for _, info := range config.HistoricalBest {
-			if info.Jadwal == bestAction {
-				persen := info.PersentaseEfektif
-				persentaseEfektif = &persen
-				break
-			}
-		}
+	if req.TopN > 1 && len(alternatif) > req.TopN {
+		alternatif = alternatif[:req.TopN]
+	} else if req.TopN <= 1 {
+		alternatif = nil
+	}
+
+	var persentaseEfektif *float64
+	if bestAction != "Tidak ada jadwal default" {
+		persentaseEfektif = persentaseHistoris(model.HistoricalBest, bestAction)
 	}
 
 	log = log.WithFields(logrus.Fields{
 		"rekomendasi": bestAction,
 		"tipe":        recType,
+		"kebijakan":   kebijakan.Nama,
+		"propensitas": propensitas,
+		"alternatif":  len(alternatif),
+		"stateSumber": len(stateSumber),
+		"modelVersi":  model.VersiID,
 	})
+	if varian != nil {
+		log = log.WithFields(logrus.Fields{"eksperimenID": varian.EksperimenID, "varian": varian.Nama})
+	}
 
 	response := dto.RecommendationResponse{
 		State:                     stateString,
@@ -87,14 +347,49 @@ func (s *RekomendasiService) GetRecommendation(c *fiber.Ctx) error {
 		TipeRekomendasi:           recType,
 		EstimasiQValue:            qValue,
 		PersentaseEfektifHistoris: persentaseEfektif,
+		Kebijakan:                 kebijakan.Nama,
+		Propensitas:               &propensitas,
+		Alasan:                    alasan,
+		Alternatif:                alternatif,
+		Kesibukan:                 kesibukan,
+		KategoriHafalan:           kategoriHafalan,
+	}
+	for _, m := range stateSumber {
+		response.StateSumber = append(response.StateSumber, dto.StateMiripResponse{State: m.State, Kemiripan: m.Kemiripan})
 	}
 
 	if bestAction != "Tidak ada jadwal default" {
 		rekomendasiRecord := models.JadwalRekomendasi{
-			State:             stateString,
-			RekomendasiJadwal: response.RekomendasiJadwal,
-			TipeRekomendasi:   response.TipeRekomendasi,
-			EstimasiQValue:    response.EstimasiQValue,
+			State:              stateString,
+			RekomendasiJadwal:  response.RekomendasiJadwal,
+			TipeRekomendasi:    response.TipeRekomendasi,
+			EstimasiQValue:     response.EstimasiQValue,
+			Kebijakan:          kebijakan.Nama,
+			ParameterKebijakan: kebijakan.Parameter,
+			Propensitas:        &propensitas,
+		}
+		if model.VersiID != 0 {
+			rekomendasiRecord.ModelVersiID = &model.VersiID
+			response.ModelVersiID = &model.VersiID
+		}
+		if varian != nil {
+			rekomendasiRecord.EksperimenID = &varian.EksperimenID
+			rekomendasiRecord.VarianID = &varian.ID
+		}
+		for _, a := range alternatif {
+			rekomendasiRecord.Kandidat = append(rekomendasiRecord.Kandidat, models.KandidatRekomendasi{
+				Peringkat:                 a.Peringkat,
+				Jadwal:                    a.RekomendasiJadwal,
+				EstimasiQValue:            a.EstimasiQValue,
+				PersentaseEfektifHistoris: a.PersentaseEfektifHistoris,
+				Alasan:                    a.Alasan,
+			})
+		}
+		for _, m := range stateSumber {
+			rekomendasiRecord.StateSumber = append(rekomendasiRecord.StateSumber, models.StateSumberRekomendasi{
+				State:     m.State,
+				Kemiripan: m.Kemiripan,
+			})
 		}
 
 		rekomendasiRecord.UserID = claims.ID
@@ -112,6 +407,87 @@ func (s *RekomendasiService) GetRecommendation(c *fiber.Ctx) error {
 	return utils.SuccessResponse(c, fiber.StatusOK, "Rekomendasi berhasil dibuat", response)
 }
 
+// PilihRekomendasi mencatat jadwal yang dipilih user dari rekomendasi utama
+// atau alternatifnya. Pilihan tidak dapat diubah setelah rekomendasi diterapkan.
+func (s *RekomendasiService) PilihRekomendasi(c *fiber.Ctx) error {
+	claims := c.Locals("user").(*utils.Claims)
+	rekomendasiID := c.Params("id")
+
+	log := logrus.WithFields(logrus.Fields{
+		"handler":       "PilihRekomendasi",
+		"userID":        claims.ID,
+		"rekomendasiID": rekomendasiID,
+	})
+	log.Info("Menerima permintaan untuk memilih jadwal rekomendasi")
+
+	var req dto.PilihRekomendasiRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.WithError(err).Error("Gagal mem-parsing request body")
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Cannot parse request body", err.Error())
+	}
+
+	jadwal := slot.Parse(req.Jadwal).String()
+	if jadwal == "" {
+		log.Warn("Jadwal pilihan kosong")
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Jadwal wajib diisi", nil)
+	}
+
+	var rekomendasi models.JadwalRekomendasi
+	if err := s.DB.Preload("Kandidat").
+		Where("id = ? AND user_id = ?", rekomendasiID, claims.ID).
+		First(&rekomendasi).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Warn("Rekomendasi tidak ditemukan")
+			return utils.ResponseError(c, fiber.StatusNotFound, "Rekomendasi tidak ditemukan", nil)
+		}
+		log.WithError(err).Error("Gagal mengambil rekomendasi")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal mengambil rekomendasi", err.Error())
+	}
+
+	if rekomendasi.DiterapkanPada != nil {
+		log.Warn("Rekomendasi sudah diterapkan")
+		return utils.ResponseError(c, fiber.StatusConflict, "Rekomendasi sudah diterapkan, pilihan tidak dapat diubah", nil)
+	}
+
+	valid := slot.Parse(rekomendasi.RekomendasiJadwal).String() == jadwal
+	for _, k := range rekomendasi.Kandidat {
+		if valid {
+			break
+		}
+		valid = slot.Parse(k.Jadwal).String() == jadwal
+	}
+	if !valid {
+		log.WithField("jadwal", jadwal).Warn("Jadwal bukan bagian dari rekomendasi")
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Jadwal tidak termasuk dalam rekomendasi maupun alternatifnya", nil)
+	}
+
+	now := time.Now()
+	rekomendasi.JadwalDipilih = &jadwal
+	rekomendasi.DipilihPada = &now
+	if err := s.DB.Model(&rekomendasi).Updates(map[string]interface{}{
+		"jadwal_dipilih": jadwal,
+		"dipilih_pada":   now,
+	}).Error; err != nil {
+		log.WithError(err).Error("Gagal menyimpan pilihan jadwal")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal menyimpan pilihan jadwal", err.Error())
+	}
+
+	log.WithField("jadwal", jadwal).Info("Pilihan jadwal rekomendasi berhasil disimpan")
+	return utils.SuccessResponse(c, fiber.StatusOK, "Pilihan jadwal berhasil disimpan", dto.RecommendationResponse{
+		ID:                        rekomendasi.ID,
+		State:                     rekomendasi.State,
+		UserID:                    rekomendasi.UserID,
+		RekomendasiJadwal:         rekomendasi.RekomendasiJadwal,
+		TipeRekomendasi:           rekomendasi.TipeRekomendasi,
+		EstimasiQValue:            rekomendasi.EstimasiQValue,
+		PersentaseEfektifHistoris: persentaseHistoris(config.ActiveModel().HistoricalBest, rekomendasi.RekomendasiJadwal),
+		ModelVersiID:              rekomendasi.ModelVersiID,
+		Kebijakan:                 rekomendasi.Kebijakan,
+		Propensitas:               rekomendasi.Propensitas,
+		JadwalDipilih:             rekomendasi.JadwalDipilih,
+	})
+}
+
 func (s *RekomendasiService) GetAllRekomendasi(c *fiber.Ctx) error {
 	claims := c.Locals("user").(*utils.Claims)
 	userID := claims.ID
@@ -146,16 +522,9 @@ func (s *RekomendasiService) GetAllRekomendasi(c *fiber.Ctx) error {
 		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal mengambil riwayat rekomendasi", err.Error())
 	}
 
+	historis := config.ActiveModel().HistoricalBest
 	responseDTOs := make([]dto.RecommendationResponse, len(riwayatRekomendasi))
 	for i, rec := range riwayatRekomendasi {
-		var persentaseEfektif *float64
-		for _, info := range config.HistoricalBest {
-			if info.Jadwal == rec.RekomendasiJadwal {
-				persen := info.PersentaseEfektif
-				persentaseEfektif = &persen
-				break
-			}
-		}
 		responseDTOs[i] = dto.RecommendationResponse{
 			ID:                        rec.ID,
 			State:                     rec.State,
@@ -163,7 +532,11 @@ func (s *RekomendasiService) GetAllRekomendasi(c *fiber.Ctx) error {
 			RekomendasiJadwal:         rec.RekomendasiJadwal,
 			TipeRekomendasi:           rec.TipeRekomendasi,
 			EstimasiQValue:            rec.EstimasiQValue,
-			PersentaseEfektifHistoris: persentaseEfektif,
+			PersentaseEfektifHistoris: persentaseHistoris(historis, rec.RekomendasiJadwal),
+			ModelVersiID:              rec.ModelVersiID,
+			Kebijakan:                 rec.Kebijakan,
+			Propensitas:               rec.Propensitas,
+			JadwalDipilih:             rec.JadwalDipilih,
 		}
 	}
 
@@ -187,22 +560,7 @@ func (s *RekomendasiService) GetAllKesibukan(c *fiber.Ctx) error {
 	log := logrus.WithField("handler", "GetAllKesibukan")
 	log.Info("Menerima permintaan untuk mengambil semua opsi kesibukan")
 
-	kesibukanSet := make(map[string]bool)
-
-	for stateString := range config.QTableModel {
-		lastIndex := strings.LastIndex(stateString, "_")
-		if lastIndex != -1 {
-			kesibukan := stateString[:lastIndex]
-			kesibukanSet[kesibukan] = true
-		}
-	}
-
-	uniqueKesibukan := make([]string, 0, len(kesibukanSet))
-	for k := range kesibukanSet {
-		uniqueKesibukan = append(uniqueKesibukan, k)
-	}
-
-	sort.Strings(uniqueKesibukan)
+	uniqueKesibukan := config.ActiveQTable().Kesibukan()
 
 	log.WithField("count", len(uniqueKesibukan)).Info("Berhasil mengambil daftar kesibukan unik")
 
@@ -0,0 +1,280 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/habbazettt/muraja-server/dto"
+	"github.com/habbazettt/muraja-server/models"
+	"github.com/habbazettt/muraja-server/mushaf"
+	"github.com/habbazettt/muraja-server/slot"
+	"github.com/habbazettt/muraja-server/srs"
+	"github.com/habbazettt/muraja-server/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const defaultMaksHalamanRencana = 20
+
+var errRencanaKosong = errors.New("tidak ada halaman yang jatuh tempo hari ini")
+
+type sesiRencana struct {
+	WaktuMurojaah slot.Slot
+	Awal          int
+	Akhir         int
+	Kekuatan      float64
+}
+
+type rencanaHarian struct {
+	TotalJatuhTempo int
+	Sesi            []sesiRencana
+}
+
+// slotJadwal mengembalikan slot JadwalPersonal, atau ba'da shubuh jika
+// jadwal masih kosong.
+func slotJadwal(jadwal slot.Daftar) []slot.Slot {
+	if len(jadwal) == 0 {
+		return []slot.Slot{slot.BadaShubuh}
+	}
+	return jadwal
+}
+
+// susunRencana memilih halaman yang jatuh tempo, paling mendesak lebih dulu,
+// lalu membaginya secara berurutan ke slot waktu murojaah user. Halaman yang
+// sudah menjadi target di log hari itu tidak diusulkan lagi.
+func susunRencana(db *gorm.DB, userID uint, tanggal time.Time, maksHalaman int) (rencanaHarian, error) {
+	var rencana rencanaHarian
+
+	var jadwal models.JadwalPersonal
+	slots := slotJadwal(nil)
+	if err := db.Where("user_id = ?", userID).First(&jadwal).Error; err == nil {
+		slots = slotJadwal(jadwal.Jadwal)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return rencana, err
+	}
+
+	var records []models.KekuatanHalaman
+	if err := db.Where("user_id = ? AND (jatuh_tempo IS NULL OR jatuh_tempo <= ?)", userID, tanggal).
+		Find(&records).Error; err != nil {
+		return rencana, err
+	}
+
+	var targetHariIni []models.DetailLog
+	if err := db.Joins("JOIN log_harians ON log_harians.id = detail_logs.log_harian_id").
+		Where("log_harians.user_id = ? AND log_harians.tanggal = ?", userID, tanggal).
+		Find(&targetHariIni).Error; err != nil {
+		return rencana, err
+	}
+	sudahDitarget := func(halaman int) bool {
+		for _, detail := range targetHariIni {
+			// Halaman sesi dengan layout yang sudah tidak disediakan tidak
+			// sebanding dengan halaman peta hafalan.
+			if detail.MushafLayout != mushaf.DefaultLayout {
+				continue
+			}
+			if halaman >= detail.TargetStartPage && halaman <= detail.TargetEndPage {
+				return true
+			}
+		}
+		return false
+	}
+
+	jatuhTempo := make([]models.KekuatanHalaman, 0, len(records))
+	for _, record := range records {
+		if !sudahDitarget(record.Halaman) {
+			jatuhTempo = append(jatuhTempo, record)
+		}
+	}
+	rencana.TotalJatuhTempo = len(jatuhTempo)
+
+	sort.SliceStable(jatuhTempo, func(i, j int) bool {
+		ki, kj := kartuHalaman(jatuhTempo[i]).Keterlambatan(tanggal), kartuHalaman(jatuhTempo[j]).Keterlambatan(tanggal)
+		if ki != kj {
+			return ki > kj
+		}
+		if jatuhTempo[i].Kekuatan != jatuhTempo[j].Kekuatan {
+			return jatuhTempo[i].Kekuatan < jatuhTempo[j].Kekuatan
+		}
+		return jatuhTempo[i].Halaman < jatuhTempo[j].Halaman
+	})
+	if len(jatuhTempo) > maksHalaman {
+		jatuhTempo = jatuhTempo[:maksHalaman]
+	}
+	if len(jatuhTempo) == 0 {
+		return rencana, nil
+	}
+
+	sort.Slice(jatuhTempo, func(i, j int) bool { return jatuhTempo[i].Halaman < jatuhTempo[j].Halaman })
+
+	// Halaman dibagi rata per slot, lalu halaman yang bersambung di dalam satu
+	// slot digabung menjadi satu sesi.
+	if len(slots) > len(jatuhTempo) {
+		slots = slots[:len(jatuhTempo)]
+	}
+	for i, waktu := range slots {
+		bagian := jatuhTempo[i*len(jatuhTempo)/len(slots) : (i+1)*len(jatuhTempo)/len(slots)]
+
+		var sesi *sesiRencana
+		var jumlah int
+		for _, record := range bagian {
+			if sesi != nil && record.Halaman == sesi.Akhir+1 {
+				sesi.Akhir = record.Halaman
+				sesi.Kekuatan += record.Kekuatan
+				jumlah++
+				continue
+			}
+			if sesi != nil {
+				sesi.Kekuatan /= float64(jumlah)
+				rencana.Sesi = append(rencana.Sesi, *sesi)
+			}
+			sesi = &sesiRencana{WaktuMurojaah: waktu, Awal: record.Halaman, Akhir: record.Halaman, Kekuatan: record.Kekuatan}
+			jumlah = 1
+		}
+		if sesi != nil {
+			sesi.Kekuatan /= float64(jumlah)
+			rencana.Sesi = append(rencana.Sesi, *sesi)
+		}
+	}
+
+	return rencana, nil
+}
+
+func parseMaksHalamanRencana(c *fiber.Ctx, layout *mushaf.Layout) int {
+	maks := c.QueryInt("maks_halaman", defaultMaksHalamanRencana)
+	if maks < 1 {
+		maks = defaultMaksHalamanRencana
+	}
+	return min(maks, layout.TotalHalaman)
+}
+
+func toRencanaDetailLog(layout *mushaf.Layout, sesi sesiRencana) dto.RencanaDetailLog {
+	target := newDetailLogTarget(layout, sesi.Awal, sesi.Akhir, sesi.Akhir-sesi.Awal+1)
+	return dto.RencanaDetailLog{
+		WaktuMurojaah:      string(sesi.WaktuMurojaah),
+		TargetStartJuz:     target.TargetStartJuz,
+		TargetStartHalaman: target.TargetStartHalaman,
+		TargetEndJuz:       target.TargetEndJuz,
+		TargetEndHalaman:   target.TargetEndHalaman,
+		TargetStartPage:    target.TargetStartPage,
+		TargetEndPage:      target.TargetEndPage,
+		TotalTargetHalaman: target.TotalTargetHalaman,
+		RataRataKekuatan:   sesi.Kekuatan,
+	}
+}
+
+// GetRencanaHarian mengusulkan DetailLog hari ini berdasarkan jadwal
+// spaced-repetition, tanpa menyimpan apa pun.
+func (s *LogMurojaahService) GetRencanaHarian(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(*utils.Claims)
+	if !ok || claims == nil {
+		return utils.ResponseError(c, fiber.StatusUnauthorized, "Unauthorized: Token tidak valid atau tidak ada", nil)
+	}
+
+	targetUserID, err := s.resolveTargetUserID(c, claims)
+	if err != nil {
+		return targetUserErrorResponse(c, err)
+	}
+
+	log := logrus.WithFields(logrus.Fields{
+		"handler":     "GetRencanaHarian",
+		"userID":      targetUserID,
+		"requesterID": claims.ID,
+	})
+
+	// Peta hafalan tersimpan dalam layout default, begitu pula rencananya.
+	layout := mushaf.Default()
+	tanggal := srs.Tanggal(time.Now())
+	rencana, err := susunRencana(s.DB, targetUserID, tanggal, parseMaksHalamanRencana(c, layout))
+	if err != nil {
+		log.WithError(err).Error("Gagal menyusun rencana murojaah")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal menyusun rencana murojaah", err.Error())
+	}
+
+	response := dto.RencanaMurojaahResponse{
+		Tanggal:         tanggal.Format("02-01-2006"),
+		MushafLayout:    layout.Kode,
+		TotalJatuhTempo: rencana.TotalJatuhTempo,
+		Rencana:         make([]dto.RencanaDetailLog, len(rencana.Sesi)),
+	}
+	for i, sesi := range rencana.Sesi {
+		response.Rencana[i] = toRencanaDetailLog(layout, sesi)
+		response.TotalTargetHalaman += response.Rencana[i].TotalTargetHalaman
+	}
+
+	log.WithField("jumlahSesi", len(rencana.Sesi)).Info("Berhasil menyusun rencana murojaah")
+	return utils.SuccessResponse(c, fiber.StatusOK, "Rencana murojaah berhasil disusun", response)
+}
+
+// TerimaRencanaHarian menyusun ulang rencana hari ini dan langsung menyimpannya
+// sebagai DetailLog.
+func (s *LogMurojaahService) TerimaRencanaHarian(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(*utils.Claims)
+	if !ok || claims == nil {
+		return utils.ResponseError(c, fiber.StatusUnauthorized, "Unauthorized: Token tidak valid atau tidak ada", nil)
+	}
+	userID := claims.ID
+
+	log := logrus.WithFields(logrus.Fields{
+		"handler": "TerimaRencanaHarian",
+		"userID":  userID,
+	})
+
+	layout := mushaf.Default()
+	maksHalaman := parseMaksHalamanRencana(c, layout)
+
+	var created []models.DetailLog
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		tanggal := srs.Tanggal(time.Now())
+
+		var logHarian models.LogHarian
+		if err := tx.Where(models.LogHarian{UserID: userID, Tanggal: tanggal}).FirstOrCreate(&logHarian).Error; err != nil {
+			return err
+		}
+
+		// Mengunci log hari ini agar dua kali "terima" tidak membuat sesi ganda.
+		if err := tx.Exec("SELECT id FROM log_harians WHERE id = ? FOR UPDATE", logHarian.ID).Error; err != nil {
+			return err
+		}
+
+		rencana, err := susunRencana(tx, userID, tanggal, maksHalaman)
+		if err != nil {
+			return err
+		}
+		if len(rencana.Sesi) == 0 {
+			return errRencanaKosong
+		}
+
+		for _, sesi := range rencana.Sesi {
+			detail := newDetailLogTarget(layout, sesi.Awal, sesi.Akhir, sesi.Akhir-sesi.Awal+1)
+			detail.LogHarianID = logHarian.ID
+			detail.MushafLayout = layout.Kode
+			setWaktuMurojaah(&detail, sesi.WaktuMurojaah)
+			detail.Status = models.StatusSesiBelumSelesai
+			if err := tx.Create(&detail).Error; err != nil {
+				return err
+			}
+			created = append(created, detail)
+		}
+
+		return s.recalculateTotals(tx, logHarian.ID)
+	})
+
+	if err != nil {
+		if errors.Is(err, errRencanaKosong) {
+			return utils.ResponseError(c, fiber.StatusUnprocessableEntity, "Tidak ada halaman yang jatuh tempo hari ini", nil)
+		}
+		log.WithError(err).Error("Gagal menerapkan rencana murojaah dalam transaksi")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal menerapkan rencana murojaah", err.Error())
+	}
+
+	response := make([]dto.DetailLogResponse, len(created))
+	for i, detail := range created {
+		response[i] = toDetailLogResponse(detail)
+	}
+
+	log.WithField("jumlahSesi", len(created)).Info("Berhasil menerapkan rencana murojaah")
+	return utils.SuccessResponse(c, fiber.StatusCreated, "Rencana murojaah berhasil diterapkan", response)
+}
@@ -0,0 +1,196 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/habbazettt/muraja-server/dto"
+	"github.com/habbazettt/muraja-server/models"
+	"github.com/habbazettt/muraja-server/mushaf"
+	"github.com/habbazettt/muraja-server/srs"
+	"github.com/habbazettt/muraja-server/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// sisaTarget mengembalikan rentang halaman yang belum diselesaikan.
+func sisaTarget(detail models.DetailLog) (int, int, bool) {
+	awal := detail.TargetStartPage + detail.TotalSelesaiHalaman
+	if awal > detail.TargetEndPage || detail.TargetStartPage == 0 {
+		return 0, 0, false
+	}
+	return awal, detail.TargetEndPage, true
+}
+
+// JalankanRollover memindahkan sisa target DetailLog yang belum selesai pada
+// tanggal tertentu ke log hari berikutnya, untuk user yang mengaktifkan
+// rollover otomatis. Aman dijalankan berulang: setiap detail hanya dipindahkan
+// sekali. Kegagalan per user tidak menghentikan user lain; semuanya digabung
+// dalam error yang dikembalikan bersama jumlah yang berhasil dipindahkan.
+func JalankanRollover(db *gorm.DB, tanggal time.Time) (int, error) {
+	tanggal = srs.Tanggal(tanggal)
+	besok := tanggal.AddDate(0, 0, 1)
+
+	log := logrus.WithFields(logrus.Fields{
+		"job":     "rollover",
+		"tanggal": tanggal.Format("2006-01-02"),
+	})
+
+	var logs []models.LogHarian
+	if err := db.Joins("JOIN users ON users.id = log_harians.user_id").
+		Where("log_harians.tanggal = ? AND users.rollover_otomatis = ?", tanggal, true).
+		Preload("DetailLogs", "total_selesai_halaman < total_target_halaman AND id NOT IN (SELECT detail_log_asal_id FROM rollover_logs)").
+		Find(&logs).Error; err != nil {
+		return 0, err
+	}
+
+	total := 0
+	var errs []error
+	for _, logHarian := range logs {
+		if len(logHarian.DetailLogs) == 0 {
+			continue
+		}
+
+		var dipindah int
+		err := db.Transaction(func(tx *gorm.DB) error {
+			dipindah = 0
+
+			var logBesok models.LogHarian
+			if err := tx.Where(models.LogHarian{UserID: logHarian.UserID, Tanggal: besok}).FirstOrCreate(&logBesok).Error; err != nil {
+				return err
+			}
+
+			for _, detail := range logHarian.DetailLogs {
+				awal, akhir, ok := sisaTarget(detail)
+				if !ok {
+					continue
+				}
+
+				// Sesi dengan layout yang sudah tidak disediakan tidak dipindahkan:
+				// nomor halamannya tidak dapat dibaca sebagai layout lain.
+				layout, err := mushaf.Get(detail.MushafLayout)
+				if err != nil {
+					log.WithFields(logrus.Fields{"detailLogID": detail.ID, "layout": detail.MushafLayout}).
+						Warn("Sisa target dengan layout mushaf yang tidak disediakan dilewati")
+					continue
+				}
+
+				// Audit dibuat lebih dulu: unique index pada detail asal mencegah
+				// rollover ganda jika job berjalan bersamaan.
+				audit := models.RolloverLog{
+					UserID:          logHarian.UserID,
+					DetailLogAsalID: detail.ID,
+					TanggalAsal:     tanggal,
+					TanggalTujuan:   besok,
+					WaktuMurojaah:   detail.WaktuMurojaah,
+					MushafLayout:    layout.Kode,
+					StartPage:       awal,
+					EndPage:         akhir,
+					TotalHalaman:    akhir - awal + 1,
+				}
+				if err := tx.Create(&audit).Error; err != nil {
+					return err
+				}
+
+				baru := newDetailLogTarget(layout, awal, akhir, akhir-awal+1)
+				baru.LogHarianID = logBesok.ID
+				baru.MushafLayout = layout.Kode
+				baru.WaktuMurojaah = detail.WaktuMurojaah
+				baru.Slot = detail.Slot
+				baru.Status = models.StatusSesiBelumSelesai
+				baru.RolloverDariID = &detail.ID
+				baru.Catatan = fmt.Sprintf("Lanjutan sesi %s tanggal %s", detail.WaktuMurojaah, tanggal.Format("02-01-2006"))
+				if err := tx.Create(&baru).Error; err != nil {
+					return err
+				}
+
+				if err := tx.Model(&audit).Update("detail_log_baru_id", baru.ID).Error; err != nil {
+					return err
+				}
+				dipindah++
+			}
+
+			return (&LogMurojaahService{DB: tx}).recalculateTotals(tx, logBesok.ID)
+		})
+		if err != nil {
+			log.WithError(err).WithField("userID", logHarian.UserID).Error("Gagal memindahkan sisa target")
+			errs = append(errs, fmt.Errorf("user %d: %w", logHarian.UserID, err))
+			continue
+		}
+		total += dipindah
+	}
+
+	log.WithFields(logrus.Fields{"jumlahDetail": total, "gagal": len(errs)}).Info("Rollover sisa target selesai")
+	return total, errors.Join(errs...)
+}
+
+func (s *LogMurojaahService) GetRiwayatRollover(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(*utils.Claims)
+	if !ok || claims == nil {
+		return utils.ResponseError(c, fiber.StatusUnauthorized, "Unauthorized: Token tidak valid atau tidak ada", nil)
+	}
+
+	targetUserID, err := s.resolveTargetUserID(c, claims)
+	if err != nil {
+		return targetUserErrorResponse(c, err)
+	}
+
+	log := logrus.WithFields(logrus.Fields{
+		"handler":     "GetRiwayatRollover",
+		"userID":      targetUserID,
+		"requesterID": claims.ID,
+	})
+
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	offset := (page - 1) * limit
+
+	var total int64
+	query := s.DB.Model(&models.RolloverLog{}).Where("user_id = ?", targetUserID)
+	if err := query.Count(&total).Error; err != nil {
+		log.WithError(err).Error("Gagal menghitung riwayat rollover")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal mengambil riwayat rollover", err.Error())
+	}
+
+	var riwayat []models.RolloverLog
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&riwayat).Error; err != nil {
+		log.WithError(err).Error("Gagal mengambil riwayat rollover")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Gagal mengambil riwayat rollover", err.Error())
+	}
+
+	response := make([]dto.RolloverLogResponse, len(riwayat))
+	for i, r := range riwayat {
+		response[i] = dto.RolloverLogResponse{
+			ID:              r.ID,
+			DetailLogAsalID: r.DetailLogAsalID,
+			DetailLogBaruID: r.DetailLogBaruID,
+			TanggalAsal:     r.TanggalAsal.Format("02-01-2006"),
+			TanggalTujuan:   r.TanggalTujuan.Format("02-01-2006"),
+			WaktuMurojaah:   r.WaktuMurojaah,
+			MushafLayout:    r.MushafLayout,
+			StartPage:       r.StartPage,
+			EndPage:         r.EndPage,
+			TotalHalaman:    r.TotalHalaman,
+			CreatedAt:       r.CreatedAt,
+		}
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Riwayat rollover berhasil diambil", fiber.Map{
+		"pagination": fiber.Map{
+			"current_page": page,
+			"total_data":   total,
+			"total_pages":  int(math.Ceil(float64(total) / float64(limit))),
+		},
+		"rollover": response,
+	})
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/habbazettt/muraja-server/dto"
+	"github.com/habbazettt/muraja-server/models"
+	"github.com/habbazettt/muraja-server/sholat"
+	"github.com/habbazettt/muraja-server/utils"
+	"gorm.io/gorm"
+)
+
+type SholatService struct {
+	DB *gorm.DB
+}
+
+func (s *SholatService) GetAllMetode(c *fiber.Ctx) error {
+	return utils.SuccessResponse(c, fiber.StatusOK, "Daftar metode perhitungan berhasil diambil", sholat.SemuaMetode())
+}
+
+func queryFloat(c *fiber.Ctx, key string, fallback *float64) (*float64, error) {
+	v := c.Query(key)
+	if v == "" {
+		return fallback, nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// GetJadwalSholat menghitung jadwal sholat harian. Parameter yang tidak diisi
+// (lat, lng, elevasi, zona, metode, madhab) diambil dari profil user.
+func (s *SholatService) GetJadwalSholat(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(*utils.Claims)
+	if !ok || claims == nil {
+		return utils.ResponseError(c, fiber.StatusUnauthorized, "Unauthorized: Token tidak valid atau tidak ada", nil)
+	}
+
+	var user models.User
+	if err := s.DB.First(&user, claims.ID).Error; err != nil {
+		return utils.ResponseError(c, fiber.StatusNotFound, "User tidak ditemukan", nil)
+	}
+
+	lat, errLat := queryFloat(c, "lat", user.Latitude)
+	lng, errLng := queryFloat(c, "lng", user.Longitude)
+	if errLat != nil || errLng != nil {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Koordinat tidak valid", nil)
+	}
+	if lat == nil || lng == nil {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Koordinat belum diisi: kirim lat dan lng atau lengkapi profil", nil)
+	}
+	elevasi, err := queryFloat(c, "elevasi", &user.Elevasi)
+	if err != nil || *elevasi < 0 {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Elevasi tidak valid", nil)
+	}
+
+	zonaNama := c.Query("zona", user.ZonaWaktu)
+	zona, err := time.LoadLocation(zonaNama)
+	if err != nil || zonaNama == "" {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Zona waktu tidak valid", nil)
+	}
+
+	metode, ok := sholat.GetMetode(c.Query("metode", user.MetodeSholat))
+	if !ok {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Metode perhitungan tidak dikenal", nil)
+	}
+	madhab := c.Query("madhab", user.Madhab)
+	if !sholat.IsValidMadhab(madhab) {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Madhab tidak valid", nil)
+	}
+
+	tanggal := time.Now().In(zona)
+	if v := c.Query("tanggal"); v != "" {
+		if tanggal, err = time.ParseInLocation("2006-01-02", v, zona); err != nil {
+			return utils.ResponseError(c, fiber.StatusBadRequest, "Format tanggal tidak valid, gunakan YYYY-MM-DD", nil)
+		}
+	}
+
+	kalkulator := sholat.Kalkulator{Metode: metode, Madhab: sholat.Madhab(madhab)}
+	jadwal, err := kalkulator.JadwalHarian(tanggal, sholat.Lokasi{
+		Latitude:  *lat,
+		Longitude: *lng,
+		Elevasi:   *elevasi,
+		Zona:      zona,
+	})
+	if err != nil {
+		return utils.ResponseError(c, fiber.StatusUnprocessableEntity, err.Error(), nil)
+	}
+
+	response := dto.JadwalSholatResponse{
+		Tanggal:   tanggal.Format("2006-01-02"),
+		Latitude:  *lat,
+		Longitude: *lng,
+		Elevasi:   *elevasi,
+		ZonaWaktu: zona.String(),
+		Metode:    metode.Kode,
+		Madhab:    madhab,
+		Jadwal:    make([]dto.WaktuSholatResponse, 0, len(sholat.Urutan)),
+	}
+	for _, w := range sholat.Urutan {
+		response.Jadwal = append(response.Jadwal, dto.WaktuSholatResponse{
+			Waktu: string(w),
+			Jam:   jadwal[w].Format("15:04"),
+			Pada:  jadwal[w],
+		})
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Jadwal sholat berhasil dihitung", response)
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/habbazettt/muraja-server/models"
+	"github.com/habbazettt/muraja-server/slot"
+	"github.com/habbazettt/muraja-server/utils"
+)
+
+var errSlotBukanRekomendasi = errors.New("waktu murojaah tidak termasuk dalam jadwal rekomendasi")
+
+// setWaktuMurojaah menyimpan slot (atau label custom) sebuah sesi beserta
+// kategori bakunya yang dipakai untuk statistik.
+func setWaktuMurojaah(detail *models.DetailLog, s slot.Slot) {
+	detail.WaktuMurojaah = string(s)
+	detail.Slot = s.Kategori()
+}
+
+// slotDariRekomendasi memilih slot sesi dari jadwal rekomendasi. Jika user
+// tidak memilih, slot pertama yang dipakai.
+func slotDariRekomendasi(rekomendasi models.JadwalRekomendasi, pilihan string) (slot.Slot, error) {
+	slots := slot.Parse(rekomendasi.JadwalDiterapkan())
+	if len(slots) == 0 {
+		return slot.BadaShubuh, nil
+	}
+	if pilihan == "" {
+		return slots[0], nil
+	}
+
+	dipilih := slot.Normalisasi(pilihan)
+	for _, s := range slots {
+		if s == dipilih {
+			return s, nil
+		}
+	}
+	return "", errSlotBukanRekomendasi
+}
+
+// GetSlotMurojaah mengembalikan daftar slot baku beserta alias yang dikenali.
+func (s *LogMurojaahService) GetSlotMurojaah(c *fiber.Ctx) error {
+	return utils.SuccessResponse(c, fiber.StatusOK, "Daftar slot murojaah berhasil diambil", slot.Semua())
+}
@@ -3,10 +3,13 @@ package services
 import (
 	"math"
 	"strconv"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/habbazettt/muraja-server/dto"
 	"github.com/habbazettt/muraja-server/models"
+	"github.com/habbazettt/muraja-server/mushaf"
+	"github.com/habbazettt/muraja-server/sholat"
 	"github.com/habbazettt/muraja-server/utils"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
@@ -16,6 +19,16 @@ type UserService struct {
 	DB *gorm.DB
 }
 
+func toLokasiUserResponse(user models.User) dto.LokasiUserResponse {
+	return dto.LokasiUserResponse{
+		Latitude:     user.Latitude,
+		Longitude:    user.Longitude,
+		Elevasi:      user.Elevasi,
+		MetodeSholat: user.MetodeSholat,
+		Madhab:       user.Madhab,
+	}
+}
+
 func (s *UserService) GetAllUsers(c *fiber.Ctx) error {
 	page, _ := strconv.Atoi(c.Query("page", "1"))
 	limit, _ := strconv.Atoi(c.Query("limit", "10"))
@@ -49,6 +62,11 @@ func (s *UserService) GetAllUsers(c *fiber.Ctx) error {
 			Nama:                 m.Nama,
 			Email:                m.Email,
 			UserType:             m.UserType,
+			MushafLayout:         m.MushafLayout,
+			RolloverOtomatis:     m.RolloverOtomatis,
+			ZonaWaktu:            m.ZonaWaktu,
+			Lokasi:               toLokasiUserResponse(m),
+			UstadzID:             m.UstadzID,
 			IsDataMurojaahFilled: m.IsDataMurojaahFilled,
 		}
 	}
@@ -81,7 +99,7 @@ func (s *UserService) GetUserById(c *fiber.Ctx) error {
 		return utils.ResponseError(c, fiber.StatusBadRequest, "Invalid ID format", nil)
 	}
 
-	if claims.Role != "admin" && claims.ID != uint(requestedID) {
+	if !utils.HasPermission(claims.Role, utils.PermUserReadAny) && claims.ID != uint(requestedID) {
 		return utils.ResponseError(c, fiber.StatusForbidden, "Forbidden: You can only access your own data", nil)
 	}
 
@@ -107,6 +125,11 @@ func (s *UserService) GetUserById(c *fiber.Ctx) error {
 		Nama:                 user.Nama,
 		Email:                user.Email,
 		UserType:             user.UserType,
+		MushafLayout:         user.MushafLayout,
+		RolloverOtomatis:     user.RolloverOtomatis,
+		ZonaWaktu:            user.ZonaWaktu,
+		Lokasi:               toLokasiUserResponse(user),
+		UstadzID:             user.UstadzID,
 		IsDataMurojaahFilled: user.IsDataMurojaahFilled,
 		JadwalPersonal:       jadwalPersonalDTO,
 	}
@@ -119,7 +142,21 @@ func (s *UserService) GetUserById(c *fiber.Ctx) error {
 }
 
 func (s *UserService) UpdateUser(c *fiber.Ctx) error {
+	claims, ok := c.Locals("user").(*utils.Claims)
+	if !ok || claims == nil {
+		return utils.ResponseError(c, fiber.StatusUnauthorized, "Unauthorized: Invalid claims", nil)
+	}
+
 	id := c.Params("id")
+	requestedID, err := strconv.Atoi(id)
+	if err != nil {
+		return utils.ResponseError(c, fiber.StatusBadRequest, "Invalid ID format", nil)
+	}
+
+	if !utils.HasPermission(claims.Role, utils.PermUserUpdateAny) && claims.ID != uint(requestedID) {
+		return utils.ResponseError(c, fiber.StatusForbidden, "Forbidden: You can only update your own data", nil)
+	}
+
 	var user models.User
 
 	if err := s.DB.First(&user, id).Error; err != nil {
@@ -146,10 +183,93 @@ func (s *UserService) UpdateUser(c *fiber.Ctx) error {
 	}
 
 	if updateRequest.UserType != nil && *updateRequest.UserType != user.UserType {
+		if !utils.HasPermission(claims.Role, utils.PermUserManageRole) {
+			return utils.ResponseError(c, fiber.StatusForbidden, "Forbidden: Only admin can change user type", nil)
+		}
+		if !models.IsValidRole(*updateRequest.UserType) {
+			return utils.ResponseError(c, fiber.StatusBadRequest, "Invalid user type", nil)
+		}
 		user.UserType = *updateRequest.UserType
 		updated = true
 	}
 
+	if updateRequest.UstadzID != nil {
+		if !utils.HasPermission(claims.Role, utils.PermUserManageRole) {
+			return utils.ResponseError(c, fiber.StatusForbidden, "Forbidden: Only admin can assign ustadz", nil)
+		}
+
+		if *updateRequest.UstadzID == 0 {
+			if user.UstadzID != nil {
+				user.UstadzID = nil
+				updated = true
+			}
+		} else if user.UstadzID == nil || *user.UstadzID != *updateRequest.UstadzID {
+			var ustadz models.User
+			if err := s.DB.Where("id = ? AND user_type = ?", *updateRequest.UstadzID, models.RoleUstadz).First(&ustadz).Error; err != nil {
+				return utils.ResponseError(c, fiber.StatusBadRequest, "Ustadz not found", nil)
+			}
+			user.UstadzID = &ustadz.ID
+			updated = true
+		}
+	}
+
+	if updateRequest.MushafLayout != nil && *updateRequest.MushafLayout != user.MushafLayout {
+		if !mushaf.IsValid(*updateRequest.MushafLayout) {
+			return utils.ResponseError(c, fiber.StatusBadRequest, "Unknown mushaf layout", nil)
+		}
+		user.MushafLayout = *updateRequest.MushafLayout
+		updated = true
+	}
+
+	if updateRequest.RolloverOtomatis != nil && *updateRequest.RolloverOtomatis != user.RolloverOtomatis {
+		user.RolloverOtomatis = *updateRequest.RolloverOtomatis
+		updated = true
+	}
+
+	if updateRequest.ZonaWaktu != nil && *updateRequest.ZonaWaktu != user.ZonaWaktu {
+		if _, err := time.LoadLocation(*updateRequest.ZonaWaktu); err != nil || *updateRequest.ZonaWaktu == "" {
+			return utils.ResponseError(c, fiber.StatusBadRequest, "Invalid time zone", nil)
+		}
+		user.ZonaWaktu = *updateRequest.ZonaWaktu
+		updated = true
+	}
+
+	if updateRequest.Latitude != nil || updateRequest.Longitude != nil {
+		if updateRequest.Latitude == nil || updateRequest.Longitude == nil {
+			return utils.ResponseError(c, fiber.StatusBadRequest, "Latitude and longitude must be set together", nil)
+		}
+		lat, lng := *updateRequest.Latitude, *updateRequest.Longitude
+		if lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+			return utils.ResponseError(c, fiber.StatusBadRequest, "Invalid coordinates", nil)
+		}
+		user.Latitude, user.Longitude = &lat, &lng
+		updated = true
+	}
+
+	if updateRequest.Elevasi != nil && *updateRequest.Elevasi != user.Elevasi {
+		if *updateRequest.Elevasi < 0 || *updateRequest.Elevasi > 9000 {
+			return utils.ResponseError(c, fiber.StatusBadRequest, "Invalid elevation", nil)
+		}
+		user.Elevasi = *updateRequest.Elevasi
+		updated = true
+	}
+
+	if updateRequest.MetodeSholat != nil && *updateRequest.MetodeSholat != user.MetodeSholat {
+		if _, ok := sholat.GetMetode(*updateRequest.MetodeSholat); !ok {
+			return utils.ResponseError(c, fiber.StatusBadRequest, "Unknown prayer time calculation method", nil)
+		}
+		user.MetodeSholat = *updateRequest.MetodeSholat
+		updated = true
+	}
+
+	if updateRequest.Madhab != nil && *updateRequest.Madhab != user.Madhab {
+		if !sholat.IsValidMadhab(*updateRequest.Madhab) {
+			return utils.ResponseError(c, fiber.StatusBadRequest, "Invalid madhab", nil)
+		}
+		user.Madhab = *updateRequest.Madhab
+		updated = true
+	}
+
 	if !updated {
 		return utils.ResponseError(c, fiber.StatusBadRequest, "No changes detected", nil)
 	}
@@ -159,6 +279,8 @@ func (s *UserService) UpdateUser(c *fiber.Ctx) error {
 		return utils.ResponseError(c, fiber.StatusInternalServerError, "Failed to update user", err.Error())
 	}
 
+	utils.InvalidateCachedUser(user.ID)
+
 	logrus.WithFields(logrus.Fields{
 		"user_id": user.ID,
 	}).Info("User updated successfully")
@@ -168,6 +290,11 @@ func (s *UserService) UpdateUser(c *fiber.Ctx) error {
 		Nama:                 user.Nama,
 		Email:                user.Email,
 		UserType:             user.UserType,
+		MushafLayout:         user.MushafLayout,
+		RolloverOtomatis:     user.RolloverOtomatis,
+		ZonaWaktu:            user.ZonaWaktu,
+		Lokasi:               toLokasiUserResponse(user),
+		UstadzID:             user.UstadzID,
 		IsDataMurojaahFilled: user.IsDataMurojaahFilled,
 	}
 
@@ -183,7 +310,13 @@ func (s *UserService) DeleteUser(c *fiber.Ctx) error {
 		return utils.ResponseError(c, fiber.StatusNotFound, "User not found", nil)
 	}
 
-	s.DB.Delete(&user)
+	if err := s.DB.Delete(&user).Error; err != nil {
+		logrus.WithError(err).Error("Failed to delete user")
+		return utils.ResponseError(c, fiber.StatusInternalServerError, "Failed to delete user", err.Error())
+	}
+
+	utils.InvalidateCachedUser(user.ID)
+
 	logrus.WithFields(logrus.Fields{
 		"user_id": user.ID,
 	}).Info("User deleted successfully")
@@ -0,0 +1,219 @@
+package sholat
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Metode adalah parameter perhitungan waktu sholat sebuah otoritas.
+type Metode struct {
+	Kode string `json:"kode"`
+	Nama string `json:"nama"`
+	// SudutSubuh dan SudutIsya adalah kedalaman matahari di bawah ufuk (derajat).
+	SudutSubuh float64 `json:"sudut_subuh"`
+	SudutIsya  float64 `json:"sudut_isya,omitempty"`
+	// MenitIsya dipakai sebagai ganti SudutIsya jika diisi (Isya = Maghrib + n menit).
+	MenitIsya int `json:"menit_isya,omitempty"`
+	// Ihtiyat adalah tambahan waktu kehati-hatian untuk semua waktu kecuali terbit,
+	// yang justru dikurangi.
+	Ihtiyat time.Duration `json:"-"`
+}
+
+var metode = map[string]Metode{
+	"kemenag": {Kode: "kemenag", Nama: "Kementerian Agama RI", SudutSubuh: 20, SudutIsya: 18, Ihtiyat: 2 * time.Minute},
+	"mwl":     {Kode: "mwl", Nama: "Muslim World League", SudutSubuh: 18, SudutIsya: 17},
+	"isna":    {Kode: "isna", Nama: "Islamic Society of North America", SudutSubuh: 15, SudutIsya: 15},
+	"egypt":   {Kode: "egypt", Nama: "Egyptian General Authority of Survey", SudutSubuh: 19.5, SudutIsya: 17.5},
+	"makkah":  {Kode: "makkah", Nama: "Umm al-Qura, Makkah", SudutSubuh: 18.5, MenitIsya: 90},
+	"karachi": {Kode: "karachi", Nama: "University of Islamic Sciences, Karachi", SudutSubuh: 18, SudutIsya: 18},
+	"jakim":   {Kode: "jakim", Nama: "JAKIM Malaysia", SudutSubuh: 20, SudutIsya: 18, Ihtiyat: 2 * time.Minute},
+}
+
+const DefaultMetode = "kemenag"
+
+// GetMetode mengembalikan metode berdasarkan kode.
+func GetMetode(kode string) (Metode, bool) {
+	m, ok := metode[kode]
+	return m, ok
+}
+
+// SemuaMetode mengembalikan semua metode yang didukung, urut berdasarkan kode.
+func SemuaMetode() []Metode {
+	all := make([]Metode, 0, len(metode))
+	for _, m := range metode {
+		all = append(all, m)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Kode < all[j].Kode })
+	return all
+}
+
+// Madhab menentukan panjang bayangan untuk waktu Ashar.
+type Madhab string
+
+const (
+	Syafii Madhab = "syafii"
+	Hanafi Madhab = "hanafi"
+)
+
+func IsValidMadhab(m string) bool {
+	return Madhab(m) == Syafii || Madhab(m) == Hanafi
+}
+
+func (m Madhab) faktorBayangan() float64 {
+	if m == Hanafi {
+		return 2
+	}
+	return 1
+}
+
+// Kalkulator menghitung waktu sholat secara astronomis tanpa layanan eksternal.
+type Kalkulator struct {
+	Metode Metode
+	Madhab Madhab
+}
+
+var ErrTidakTerdefinisi = errors.New("matahari tidak terbit atau terbenam pada lokasi dan tanggal ini")
+
+func (k Kalkulator) JadwalHarian(tanggal time.Time, lokasi Lokasi) (Jadwal, error) {
+	if lokasi.Latitude < -90 || lokasi.Latitude > 90 || lokasi.Longitude < -180 || lokasi.Longitude > 180 {
+		return nil, fmt.Errorf("koordinat tidak valid: %f, %f", lokasi.Latitude, lokasi.Longitude)
+	}
+	zona := lokasi.Zona
+	if zona == nil {
+		zona = time.UTC
+	}
+
+	awalHari := time.Date(tanggal.Year(), tanggal.Month(), tanggal.Day(), 0, 0, 0, 0, zona)
+	_, offsetDetik := awalHari.Add(12 * time.Hour).Zone()
+	zonaJam := float64(offsetDetik) / 3600
+
+	h := hitungan{
+		jd:  julian(tanggal.Year(), int(tanggal.Month()), tanggal.Day()) - lokasi.Longitude/(15*24),
+		lat: lokasi.Latitude,
+	}
+
+	// Satu iterasi dari tebakan awal sudah cukup teliti (< 1 menit).
+	sudutTerbit := 0.833 + 0.0347*math.Sqrt(math.Max(0, lokasi.Elevasi))
+	terbit := h.waktuSudut(sudutTerbit, 6.0/24, true)
+	terbenam := h.waktuSudut(sudutTerbit, 18.0/24, false)
+	if math.IsNaN(terbit) || math.IsNaN(terbenam) {
+		return nil, ErrTidakTerdefinisi
+	}
+
+	jam := map[Waktu]float64{
+		Subuh:   h.waktuSudut(k.Metode.SudutSubuh, 5.0/24, true),
+		Terbit:  terbit,
+		Dzuhur:  h.tengahHari(12.0 / 24),
+		Ashar:   h.waktuAshar(k.Madhab.faktorBayangan(), 13.0/24),
+		Maghrib: terbenam,
+	}
+	if k.Metode.MenitIsya > 0 {
+		jam[Isya] = terbenam + float64(k.Metode.MenitIsya)/60
+	} else {
+		jam[Isya] = h.waktuSudut(k.Metode.SudutIsya, 18.0/24, false)
+	}
+
+	// Lintang tinggi: jika sudut Subuh/Isya tidak tercapai, gunakan porsi malam
+	// sebanding dengan sudutnya (metode angle-based).
+	malam := 24 - (terbenam - terbit)
+	if math.IsNaN(jam[Subuh]) {
+		jam[Subuh] = terbit - malam*k.Metode.SudutSubuh/60
+	}
+	if math.IsNaN(jam[Isya]) {
+		jam[Isya] = terbenam + malam*k.Metode.SudutIsya/60
+	}
+
+	jadwal := make(Jadwal, len(jam))
+	for w, t := range jam {
+		t += zonaJam - lokasi.Longitude/15
+		waktu := awalHari.Add(time.Duration(t * float64(time.Hour))).Round(time.Minute)
+		if w == Terbit {
+			waktu = waktu.Add(-k.Metode.Ihtiyat)
+		} else {
+			waktu = waktu.Add(k.Metode.Ihtiyat)
+		}
+		jadwal[w] = waktu
+	}
+	return jadwal, nil
+}
+
+type hitungan struct {
+	jd  float64
+	lat float64
+}
+
+// posisiMatahari mengembalikan deklinasi (derajat) dan equation of time (jam).
+func posisiMatahari(jd float64) (float64, float64) {
+	d := jd - 2451545.0
+	g := fixSudut(357.529 + 0.98560028*d)
+	q := fixSudut(280.459 + 0.98564736*d)
+	l := fixSudut(q + 1.915*sinD(g) + 0.020*sinD(2*g))
+	e := 23.439 - 0.00000036*d
+
+	ra := atan2D(cosD(e)*sinD(l), cosD(l)) / 15
+	eqt := q/15 - fixJam(ra)
+	dekl := asinD(sinD(e) * sinD(l))
+	return dekl, eqt
+}
+
+func (h hitungan) tengahHari(t float64) float64 {
+	_, eqt := posisiMatahari(h.jd + t)
+	return fixJam(12 - eqt)
+}
+
+// waktuSudut menghitung saat matahari berada sudut derajat di bawah ufuk,
+// sebelum tengah hari jika pagi bernilai true.
+func (h hitungan) waktuSudut(sudut, t float64, pagi bool) float64 {
+	dekl, _ := posisiMatahari(h.jd + t)
+	siang := h.tengahHari(t)
+	cosT := (-sinD(sudut) - sinD(dekl)*sinD(h.lat)) / (cosD(dekl) * cosD(h.lat))
+	if cosT < -1 || cosT > 1 {
+		return math.NaN()
+	}
+	selisih := acosD(cosT) / 15
+	if pagi {
+		return siang - selisih
+	}
+	return siang + selisih
+}
+
+func (h hitungan) waktuAshar(faktor, t float64) float64 {
+	dekl, _ := posisiMatahari(h.jd + t)
+	sudut := -acotD(faktor + tanD(math.Abs(h.lat-dekl)))
+	return h.waktuSudut(sudut, t, false)
+}
+
+func julian(tahun, bulan, hari int) float64 {
+	if bulan <= 2 {
+		tahun--
+		bulan += 12
+	}
+	a := math.Floor(float64(tahun) / 100)
+	b := 2 - a + math.Floor(a/4)
+	return math.Floor(365.25*float64(tahun+4716)) + math.Floor(30.6001*float64(bulan+1)) + float64(hari) + b - 1524.5
+}
+
+func radian(d float64) float64  { return d * math.Pi / 180 }
+func derajat(r float64) float64 { return r * 180 / math.Pi }
+
+func sinD(d float64) float64      { return math.Sin(radian(d)) }
+func cosD(d float64) float64      { return math.Cos(radian(d)) }
+func tanD(d float64) float64      { return math.Tan(radian(d)) }
+func asinD(x float64) float64     { return derajat(math.Asin(x)) }
+func acosD(x float64) float64     { return derajat(math.Acos(x)) }
+func acotD(x float64) float64     { return derajat(math.Atan(1 / x)) }
+func atan2D(y, x float64) float64 { return derajat(math.Atan2(y, x)) }
+
+func fixSudut(a float64) float64 { return fix(a, 360) }
+func fixJam(a float64) float64   { return fix(a, 24) }
+
+func fix(a, b float64) float64 {
+	a = a - b*math.Floor(a/b)
+	if a < 0 {
+		return a + b
+	}
+	return a
+}
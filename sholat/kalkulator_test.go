@@ -0,0 +1,132 @@
+package sholat
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// toleransiKemenag menampung pembulatan ke menit pada jadwal resmi Kemenag dan
+// hasil kalkulator.
+const toleransiKemenag = time.Minute
+
+func TestKalkulatorKemenagJakarta(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Skipf("zona Asia/Jakarta tidak tersedia: %v", err)
+	}
+	metode, _ := GetMetode("kemenag")
+	k := Kalkulator{Metode: metode, Madhab: Syafii}
+	lokasi := Lokasi{Latitude: -6.1754, Longitude: 106.8272, Zona: jakarta}
+
+	// Jadwal imsakiyah Kemenag untuk DKI Jakarta, 1 Ramadhan 1445 H.
+	tanggal := time.Date(2024, 3, 12, 0, 0, 0, 0, jakarta)
+	jadwal, err := k.JadwalHarian(tanggal, lokasi)
+	if err != nil {
+		t.Fatalf("JadwalHarian error: %v", err)
+	}
+
+	// selisih adalah offset yang diketahui antara kalkulator dan Kemenag.
+	// Subuh dan Terbit Kemenag lebih awal daripada hitungan astronomis murni
+	// dengan sudut yang sama; kalkulator belum mereplikasi koreksi itu.
+	tests := []struct {
+		waktu   Waktu
+		jam     string
+		selisih time.Duration
+	}{
+		{Subuh, "04:39", 3 * time.Minute},
+		{Terbit, "05:54", 2 * time.Minute},
+		{Dzuhur, "12:04", 0},
+		{Maghrib, "18:09", 0},
+		{Isya, "19:18", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.waktu), func(t *testing.T) {
+			acuan, err := time.ParseInLocation("2006-01-02 15:04", "2024-03-12 "+tt.jam, jakarta)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := jadwal[tt.waktu]
+			if selisih := got.Sub(acuan.Add(tt.selisih)).Abs(); selisih > toleransiKemenag {
+				t.Errorf("%s = %s, Kemenag %s + %v (selisih %v)", tt.waktu, got.Format("15:04"), tt.jam, tt.selisih, selisih)
+			}
+		})
+	}
+}
+
+// rasioBayangan menghitung panjang bayangan benda dibagi tingginya pada saat t,
+// dari ketinggian matahari di lokasi tersebut.
+func rasioBayangan(t time.Time, lokasi Lokasi) (rasio, dekl float64) {
+	jd := 2440587.5 + float64(t.Unix())/86400
+	dekl, eqt := posisiMatahari(jd)
+	utc := t.UTC()
+	jamMatahari := float64(utc.Hour()) + float64(utc.Minute())/60 + float64(utc.Second())/3600 + lokasi.Longitude/15 + eqt
+	sudutJam := 15 * (jamMatahari - 12)
+	tinggi := asinD(sinD(lokasi.Latitude)*sinD(dekl) + cosD(lokasi.Latitude)*cosD(dekl)*cosD(sudutJam))
+	return 1 / tanD(tinggi), dekl
+}
+
+// Ashar dimulai saat bayangan sama dengan panjang benda (Syafi'i) atau dua
+// kalinya (Hanafi), ditambah bayangan saat tengah hari.
+func TestKalkulatorAshar(t *testing.T) {
+	metode, _ := GetMetode("kemenag")
+	tests := []struct {
+		name   string
+		madhab Madhab
+		faktor float64
+		lokasi Lokasi
+		waktu  time.Time
+	}{
+		{"Syafi'i Jakarta", Syafii, 1, Lokasi{Latitude: -6.1754, Longitude: 106.8272}, time.Date(2024, 3, 12, 0, 0, 0, 0, time.UTC)},
+		{"Hanafi Jakarta", Hanafi, 2, Lokasi{Latitude: -6.1754, Longitude: 106.8272}, time.Date(2024, 3, 12, 0, 0, 0, 0, time.UTC)},
+		{"Syafi'i Istanbul musim panas", Syafii, 1, Lokasi{Latitude: 41.0082, Longitude: 28.9784}, time.Date(2024, 6, 21, 0, 0, 0, 0, time.UTC)},
+		{"Hanafi Istanbul musim panas", Hanafi, 2, Lokasi{Latitude: 41.0082, Longitude: 28.9784}, time.Date(2024, 6, 21, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jadwal, err := Kalkulator{Metode: metode, Madhab: tt.madhab}.JadwalHarian(tt.waktu, tt.lokasi)
+			if err != nil {
+				t.Fatalf("JadwalHarian error: %v", err)
+			}
+			ashar := jadwal[Ashar].Add(-metode.Ihtiyat)
+
+			// Hasil dibulatkan ke menit, sehingga rasio yang diharapkan harus
+			// berada di antara rasio satu menit sebelum dan sesudahnya.
+			sebelum, dekl := rasioBayangan(ashar.Add(-time.Minute), tt.lokasi)
+			sesudah, _ := rasioBayangan(ashar.Add(time.Minute), tt.lokasi)
+			want := tt.faktor + tanD(math.Abs(tt.lokasi.Latitude-dekl))
+			if want < sebelum || want > sesudah {
+				t.Errorf("Ashar %s: rasio bayangan %.3f–%.3f, want %.3f", ashar.Format("15:04"), sebelum, sesudah, want)
+			}
+		})
+	}
+
+	syafii, _ := Kalkulator{Metode: metode, Madhab: Syafii}.JadwalHarian(tests[0].waktu, tests[0].lokasi)
+	hanafi, _ := Kalkulator{Metode: metode, Madhab: Hanafi}.JadwalHarian(tests[0].waktu, tests[0].lokasi)
+	if !hanafi[Ashar].After(syafii[Ashar]) {
+		t.Errorf("Ashar Hanafi %s tidak lebih lambat dari Syafi'i %s", hanafi[Ashar].Format("15:04"), syafii[Ashar].Format("15:04"))
+	}
+}
+
+func TestKalkulatorKoordinatTidakValid(t *testing.T) {
+	metode, _ := GetMetode(DefaultMetode)
+	k := Kalkulator{Metode: metode, Madhab: Syafii}
+
+	tests := []struct {
+		name   string
+		lokasi Lokasi
+	}{
+		{"latitude di atas 90", Lokasi{Latitude: 91, Longitude: 106}},
+		{"longitude di bawah -180", Lokasi{Latitude: -6, Longitude: -181}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := k.JadwalHarian(time.Date(2024, 3, 12, 0, 0, 0, 0, time.UTC), tt.lokasi); err == nil {
+				t.Error("diharapkan error untuk koordinat tidak valid")
+			}
+		})
+	}
+}
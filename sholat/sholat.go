@@ -0,0 +1,111 @@
+// Package sholat menyediakan waktu sholat harian yang dipakai untuk mengubah
+// slot murojaah relatif ("bada shubuh", "bada isya") menjadi waktu konkret.
+package sholat
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+type Waktu string
+
+const (
+	Subuh   Waktu = "subuh"
+	Terbit  Waktu = "terbit"
+	Dzuhur  Waktu = "dzuhur"
+	Ashar   Waktu = "ashar"
+	Maghrib Waktu = "maghrib"
+	Isya    Waktu = "isya"
+)
+
+// Urutan adalah semua waktu dalam urutan kemunculannya dalam sehari.
+var Urutan = []Waktu{Subuh, Terbit, Dzuhur, Ashar, Maghrib, Isya}
+
+// Jadwal berisi waktu sholat satu hari dalam zona waktu lokasi.
+type Jadwal map[Waktu]time.Time
+
+// Lokasi adalah posisi dan zona waktu tempat jadwal dihitung.
+type Lokasi struct {
+	Latitude  float64
+	Longitude float64
+	Elevasi   float64
+	Zona      *time.Location
+}
+
+// Provider menghasilkan jadwal sholat untuk sebuah tanggal dan lokasi.
+type Provider interface {
+	JadwalHarian(tanggal time.Time, lokasi Lokasi) (Jadwal, error)
+}
+
+// JadwalTetap adalah provider sederhana dengan jam yang sama setiap hari,
+// berguna selama koordinat user belum diketahui.
+type JadwalTetap map[Waktu]time.Duration
+
+// DefaultJadwalTetap kira-kira sesuai waktu sholat di Pulau Jawa.
+var DefaultJadwalTetap = JadwalTetap{
+	Subuh:   4*time.Hour + 30*time.Minute,
+	Terbit:  5*time.Hour + 45*time.Minute,
+	Dzuhur:  11*time.Hour + 50*time.Minute,
+	Ashar:   15*time.Hour + 10*time.Minute,
+	Maghrib: 17*time.Hour + 50*time.Minute,
+	Isya:    19 * time.Hour,
+}
+
+func (j JadwalTetap) JadwalHarian(tanggal time.Time, lokasi Lokasi) (Jadwal, error) {
+	zona := lokasi.Zona
+	if zona == nil {
+		zona = time.UTC
+	}
+
+	awalHari := time.Date(tanggal.Year(), tanggal.Month(), tanggal.Day(), 0, 0, 0, 0, zona)
+	jadwal := make(Jadwal, len(Urutan))
+	for _, w := range Urutan {
+		offset, ok := j[w]
+		if !ok {
+			offset = DefaultJadwalTetap[w]
+		}
+		jadwal[w] = awalHari.Add(offset)
+	}
+	return jadwal, nil
+}
+
+// ParseJadwalTetap membaca format "subuh=04:30,dzuhur=11:50,...". Waktu yang
+// tidak disebut memakai DefaultJadwalTetap.
+func ParseJadwalTetap(s string) (JadwalTetap, error) {
+	jadwal := make(JadwalTetap, len(DefaultJadwalTetap))
+	for w, d := range DefaultJadwalTetap {
+		jadwal[w] = d
+	}
+
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("format jadwal tidak valid: %q", part)
+		}
+		w := Waktu(strings.ToLower(strings.TrimSpace(kv[0])))
+		if _, ok := DefaultJadwalTetap[w]; !ok {
+			return nil, fmt.Errorf("waktu sholat tidak dikenal: %q", kv[0])
+		}
+		jam, err := time.Parse("15:04", strings.TrimSpace(kv[1]))
+		if err != nil {
+			return nil, fmt.Errorf("jam tidak valid untuk %s: %q", w, kv[1])
+		}
+		jadwal[w] = time.Duration(jam.Hour())*time.Hour + time.Duration(jam.Minute())*time.Minute
+	}
+	return jadwal, nil
+}
+
+// ProviderFromEnv memakai JADWAL_SHOLAT_TETAP jika diisi, selain itu
+// DefaultJadwalTetap.
+func ProviderFromEnv() (Provider, error) {
+	if v := os.Getenv("JADWAL_SHOLAT_TETAP"); v != "" {
+		return ParseJadwalTetap(v)
+	}
+	return DefaultJadwalTetap, nil
+}
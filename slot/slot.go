@@ -0,0 +1,225 @@
+// Package slot mendefinisikan slot waktu murojaah baku beserta alias
+// penulisannya, sehingga "Ba'da Subuh" dan "bada shubuh" dianggap sesi yang sama.
+package slot
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Slot adalah kode slot baku, atau label bebas yang sudah dirapikan untuk
+// slot custom.
+type Slot string
+
+const (
+	BadaShubuh   Slot = "bada shubuh"
+	Dhuha        Slot = "dhuha"
+	BadaDzuhur   Slot = "bada dzuhur"
+	BadaAshar    Slot = "bada ashar"
+	BadaMaghrib  Slot = "bada maghrib"
+	BadaIsya     Slot = "bada isya"
+	SebelumTidur Slot = "sebelum tidur"
+	Custom       Slot = "custom"
+)
+
+type Info struct {
+	Kode  Slot     `json:"kode"`
+	Nama  string   `json:"nama"`
+	Alias []string `json:"alias"`
+}
+
+// daftar berisi slot baku urut kronologis dalam sehari.
+var daftar = []Info{
+	{Kode: BadaShubuh, Nama: "Ba'da Shubuh", Alias: []string{"bada subuh", "ba'da subuh", "setelah subuh", "subuh", "fajr"}},
+	{Kode: Dhuha, Nama: "Dhuha", Alias: []string{"duha", "waktu dhuha", "pagi"}},
+	{Kode: BadaDzuhur, Nama: "Ba'da Dzuhur", Alias: []string{"bada zuhur", "ba'da dhuhur", "setelah dzuhur", "dzuhur", "siang"}},
+	{Kode: BadaAshar, Nama: "Ba'da Ashar", Alias: []string{"bada asar", "ba'da ashr", "setelah ashar", "ashar", "sore"}},
+	{Kode: BadaMaghrib, Nama: "Ba'da Maghrib", Alias: []string{"bada magrib", "ba'da maghrib", "setelah maghrib", "maghrib"}},
+	{Kode: BadaIsya, Nama: "Ba'da Isya", Alias: []string{"bada isya'", "ba'da isha", "setelah isya", "isya", "malam"}},
+	{Kode: SebelumTidur, Nama: "Sebelum Tidur", Alias: []string{"menjelang tidur", "mau tidur", "before sleep"}},
+	{Kode: Custom, Nama: "Lainnya"},
+}
+
+// kataAwalan adalah variasi penulisan "ba'da" (setelah).
+var kataAwalan = map[string]bool{
+	"bada": true, "bakda": true, "badah": true, "setelah": true, "sesudah": true,
+	"habis": true, "abis": true, "after": true, "ba": true, "da": true,
+}
+
+// kataPengisi diabaikan saat mencocokkan, mis. "setelah sholat subuh".
+var kataPengisi = map[string]bool{
+	"ai": true, "waktu": true, "sholat": true, "shalat": true, "salat": true, "solat": true, "sembahyang": true,
+}
+
+// kataSlot memetakan satu kata kunci ke slot bakunya.
+var kataSlot = map[string]Slot{
+	"subuh": BadaShubuh, "shubuh": BadaShubuh, "shubh": BadaShubuh, "subh": BadaShubuh, "fajr": BadaShubuh, "fajar": BadaShubuh,
+	"dhuha": Dhuha, "duha": Dhuha, "pagi": Dhuha,
+	"dzuhur": BadaDzuhur, "zuhur": BadaDzuhur, "dhuhur": BadaDzuhur, "duhur": BadaDzuhur, "zhuhur": BadaDzuhur, "dhuhr": BadaDzuhur, "zuhr": BadaDzuhur, "siang": BadaDzuhur,
+	"ashar": BadaAshar, "asar": BadaAshar, "ashr": BadaAshar, "asr": BadaAshar, "sore": BadaAshar,
+	"maghrib": BadaMaghrib, "magrib": BadaMaghrib, "mahgrib": BadaMaghrib,
+	"isya": BadaIsya, "isyak": BadaIsya, "isha": BadaIsya, "isa": BadaIsya, "malam": BadaIsya,
+	"tidur": SebelumTidur, "sleep": SebelumTidur,
+}
+
+// Semua mengembalikan semua slot baku, urut kronologis.
+func Semua() []Info {
+	return append([]Info(nil), daftar...)
+}
+
+func urutan(s Slot) int {
+	for i, info := range daftar {
+		if info.Kode == s {
+			return i
+		}
+	}
+	return len(daftar)
+}
+
+// IsBaku memeriksa apakah s adalah salah satu slot baku selain Custom.
+func (s Slot) IsBaku() bool {
+	return s != Custom && urutan(s) < len(daftar)
+}
+
+// Kategori mengembalikan slot baku s, atau Custom untuk label bebas.
+func (s Slot) Kategori() Slot {
+	if s.IsBaku() {
+		return s
+	}
+	return Custom
+}
+
+func rapikan(teks string) string {
+	teks = strings.ToLower(teks)
+	teks = strings.NewReplacer("'", "", "’", "", "`", "", "-", " ", "_", " ", ".", " ").Replace(teks)
+	return strings.Join(strings.Fields(teks), " ")
+}
+
+// Normalisasi mengubah teks bebas menjadi slot baku. Teks yang tidak dikenali
+// dikembalikan sebagai label custom yang sudah dirapikan. Jika teks berisi
+// beberapa slot dipisah koma, hanya yang pertama yang dipakai.
+func Normalisasi(teks string) Slot {
+	if i := strings.Index(teks, ","); i >= 0 {
+		teks = teks[:i]
+	}
+	teks = strings.TrimPrefix(strings.TrimSpace(strings.ToLower(teks)), "ai:")
+
+	rapi := rapikan(teks)
+	if rapi == "" {
+		return ""
+	}
+
+	sebelum := false
+	for _, kata := range strings.Fields(rapi) {
+		switch {
+		case kataPengisi[kata] || kataAwalan[kata]:
+			continue
+		case kata == "sebelum" || kata == "menjelang" || kata == "before" || kata == "mau":
+			sebelum = true
+			continue
+		}
+
+		s, ok := kataSlot[kata]
+		if !ok {
+			break
+		}
+		// "sebelum subuh" bukan slot baku, hanya "sebelum tidur" yang dikenali.
+		if sebelum && s != SebelumTidur {
+			break
+		}
+		return s
+	}
+
+	if rapi == string(Custom) {
+		return Custom
+	}
+	return Slot(rapi)
+}
+
+// Daftar adalah kumpulan slot, disimpan sebagai array JSON.
+type Daftar []Slot
+
+// NormalisasiDaftar menormalkan setiap slot, membuang duplikat, lalu
+// mengurutkannya secara kronologis dengan slot custom di akhir.
+func NormalisasiDaftar(items []string) Daftar {
+	seen := make(map[Slot]bool, len(items))
+	hasil := make(Daftar, 0, len(items))
+	for _, item := range items {
+		s := Normalisasi(item)
+		if s == "" || seen[s] {
+			continue
+		}
+		seen[s] = true
+		hasil = append(hasil, s)
+	}
+	sort.SliceStable(hasil, func(i, j int) bool { return urutan(hasil[i]) < urutan(hasil[j]) })
+	return hasil
+}
+
+// Parse menerima daftar slot dalam format lama ("bada shubuh, bada isya").
+func Parse(teks string) Daftar {
+	return NormalisasiDaftar(strings.Split(teks, ","))
+}
+
+// String menghasilkan format yang sama dengan aksi pada model Q-learning.
+func (d Daftar) String() string {
+	parts := make([]string, len(d))
+	for i, s := range d {
+		parts[i] = string(s)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// UnmarshalJSON menerima array slot maupun string dipisah koma.
+func (d *Daftar) UnmarshalJSON(data []byte) error {
+	var items []string
+	if err := json.Unmarshal(data, &items); err != nil {
+		var teks string
+		if errTeks := json.Unmarshal(data, &teks); errTeks != nil {
+			return errors.New("jadwal harus berupa array slot atau string dipisah koma")
+		}
+		*d = Parse(teks)
+		return nil
+	}
+	*d = NormalisasiDaftar(items)
+	return nil
+}
+
+func (d Daftar) Value() (driver.Value, error) {
+	if d == nil {
+		d = Daftar{}
+	}
+	b, err := json.Marshal([]Slot(d))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan membaca array JSON, dan tetap menerima format lama dipisah koma.
+func (d *Daftar) Scan(value any) error {
+	var raw string
+	switch v := value.(type) {
+	case nil:
+		*d = nil
+		return nil
+	case []byte:
+		raw = string(v)
+	case string:
+		raw = v
+	default:
+		return fmt.Errorf("tipe jadwal tidak didukung: %T", value)
+	}
+
+	var items []Slot
+	if err := json.Unmarshal([]byte(raw), &items); err == nil {
+		*d = items
+		return nil
+	}
+	*d = Parse(raw)
+	return nil
+}
@@ -0,0 +1,41 @@
+package slot
+
+import "testing"
+
+func TestNormalisasi(t *testing.T) {
+	tests := []struct {
+		teks string
+		want Slot
+	}{
+		{"bada shubuh", BadaShubuh},
+		{"Ba'da Subuh", BadaShubuh},
+		{"setelah sholat subuh", BadaShubuh},
+		{"ai:fajr", BadaShubuh},
+		{"Waktu Dhuha", Dhuha},
+		{"pagi", Dhuha},
+		{"ba'da dhuhur", BadaDzuhur},
+		{"habis zuhur", BadaDzuhur},
+		{"Bakda Asar", BadaAshar},
+		{"sesudah shalat ashr", BadaAshar},
+		{"bada_magrib", BadaMaghrib},
+		{"Ba’da Isya’", BadaIsya},
+		{"after isha", BadaIsya},
+		{"malam", BadaIsya},
+		{"menjelang tidur", SebelumTidur},
+		{"before sleep", SebelumTidur},
+		{"bada subuh, bada isya", BadaShubuh},
+		{"custom", Custom},
+		{"sebelum subuh", Slot("sebelum subuh")},
+		{"  Halaqah  Pekanan ", Slot("halaqah pekanan")},
+		{"", ""},
+		{" , isya", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.teks, func(t *testing.T) {
+			if got := Normalisasi(tt.teks); got != tt.want {
+				t.Errorf("Normalisasi(%q) = %q, want %q", tt.teks, got, tt.want)
+			}
+		})
+	}
+}
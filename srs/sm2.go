@@ -0,0 +1,82 @@
+// Package srs berisi penjadwal spaced-repetition (varian SM-2) untuk
+// menentukan kapan sebuah halaman hafalan perlu dimurojaah lagi.
+package srs
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	EaseAwal    = 2.5
+	EaseMinimum = 1.3
+
+	KualitasMaksimum = 5
+	// KualitasLulus adalah batas bawah kualitas yang dianggap berhasil diingat.
+	KualitasLulus = 3
+)
+
+// Kartu adalah status penjadwalan satu halaman.
+type Kartu struct {
+	EaseFactor   float64
+	IntervalHari int
+	Repetisi     int
+	JatuhTempo   time.Time
+}
+
+// Baru mengembalikan kartu untuk halaman yang belum pernah dijadwalkan.
+func Baru() Kartu {
+	return Kartu{EaseFactor: EaseAwal}
+}
+
+// Review menerapkan satu kali murojaah dengan kualitas 0..5 pada tanggal tertentu
+// dan mengembalikan kartu dengan jadwal berikutnya.
+func Review(k Kartu, kualitas int, tanggal time.Time) Kartu {
+	kualitas = max(0, min(KualitasMaksimum, kualitas))
+	if k.EaseFactor < EaseMinimum {
+		k.EaseFactor = EaseAwal
+	}
+
+	if kualitas < KualitasLulus {
+		k.Repetisi = 0
+		k.IntervalHari = 1
+	} else {
+		switch k.Repetisi {
+		case 0:
+			k.IntervalHari = 1
+		case 1:
+			k.IntervalHari = 6
+		default:
+			k.IntervalHari = int(math.Round(float64(k.IntervalHari) * k.EaseFactor))
+		}
+		k.Repetisi++
+	}
+
+	selisih := float64(KualitasMaksimum - kualitas)
+	k.EaseFactor = max(EaseMinimum, k.EaseFactor+0.1-selisih*(0.08+selisih*0.02))
+	k.JatuhTempo = Tanggal(tanggal).AddDate(0, 0, k.IntervalHari)
+
+	return k
+}
+
+// JatuhTempoPada memeriksa apakah kartu perlu dimurojaah pada tanggal tersebut.
+// Kartu tanpa jatuh tempo selalu dianggap jatuh tempo.
+func (k Kartu) JatuhTempoPada(tanggal time.Time) bool {
+	return k.JatuhTempo.IsZero() || !k.JatuhTempo.After(Tanggal(tanggal))
+}
+
+// Keterlambatan mengembalikan jumlah hari lewat jatuh tempo relatif terhadap
+// interval. Nilai lebih besar berarti lebih mendesak.
+func (k Kartu) Keterlambatan(tanggal time.Time) float64 {
+	if k.JatuhTempo.IsZero() {
+		return math.Inf(1)
+	}
+	hari := Tanggal(tanggal).Sub(k.JatuhTempo).Hours() / 24
+	return hari / float64(max(1, k.IntervalHari))
+}
+
+// Tanggal memotong waktu menjadi awal hari dalam UTC.
+func Tanggal(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -9,22 +9,26 @@ import (
 )
 
 type Claims struct {
-	ID   uint   `json:"id"`
-	Role string `json:"role"`
+	ID      uint   `json:"id"`
+	Role    string `json:"role"`
+	Version int    `json:"ver"`
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(userID uint, role string) (string, error) {
+func GenerateToken(userID uint, role string, version int, jti string, ttl time.Duration) (string, error) {
 	secret := os.Getenv("JWT_SECRET")
 	if secret == "" {
 		return "", errors.New("JWT_SECRET tidak ditemukan dalam environment")
 	}
 
 	claims := Claims{
-		ID:   userID,
-		Role: role,
+		ID:      userID,
+		Role:    role,
+		Version: version,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 24)),
+			ID:        jti,
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
 		},
 	}
 
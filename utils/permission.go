@@ -0,0 +1,64 @@
+package utils
+
+import "github.com/habbazettt/muraja-server/models"
+
+type Permission string
+
+const (
+	PermUserList       Permission = "user:list"
+	PermUserReadAny    Permission = "user:read:any"
+	PermUserUpdateAny  Permission = "user:update:any"
+	PermUserManageRole Permission = "user:manage-role"
+	PermUserDelete     Permission = "user:delete"
+
+	PermLogReadAssigned Permission = "log:read:assigned"
+	PermLogReadAny      Permission = "log:read:any"
+
+	PermJadwalReadAll Permission = "jadwal:read:all"
+
+	PermHalaqahCreate Permission = "halaqah:create"
+	PermHalaqahManage Permission = "halaqah:manage"
+
+	PermKesibukanRead    Permission = "rekomendasi:kesibukan:read"
+	PermInvitationManage Permission = "invitation:manage"
+	PermJobManage        Permission = "job:manage"
+	PermModelManage      Permission = "model:manage"
+)
+
+// rolePermissions memetakan setiap role ke kumpulan permission-nya. Hak atas
+// data milik sendiri tidak dicantumkan karena selalu dimiliki setiap user.
+var rolePermissions = map[string][]Permission{
+	models.RoleUser: {},
+	models.RoleUstadz: {
+		PermLogReadAssigned,
+		PermHalaqahCreate,
+	},
+	models.RoleAdmin: {
+		PermUserList,
+		PermUserReadAny,
+		PermUserUpdateAny,
+		PermUserManageRole,
+		PermUserDelete,
+		PermLogReadAny,
+		PermJadwalReadAll,
+		PermHalaqahCreate,
+		PermHalaqahManage,
+		PermKesibukanRead,
+		PermInvitationManage,
+		PermJobManage,
+		PermModelManage,
+	},
+}
+
+func HasPermission(role string, permission Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+func PermissionsOf(role string) []Permission {
+	return append([]Permission(nil), rolePermissions[role]...)
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// CachedUser adalah potongan data user yang dibutuhkan middleware untuk
+// memvalidasi ulang token tanpa query ke database di setiap request.
+type CachedUser struct {
+	Role         string
+	TokenVersion int
+}
+
+type userCacheEntry struct {
+	user      CachedUser
+	expiresAt time.Time
+}
+
+var userCache = struct {
+	sync.RWMutex
+	entries map[uint]userCacheEntry
+}{entries: make(map[uint]userCacheEntry)}
+
+func GetCachedUser(userID uint) (CachedUser, bool) {
+	userCache.RLock()
+	entry, ok := userCache.entries[userID]
+	userCache.RUnlock()
+
+	if !ok || time.Now().After(entry.expiresAt) {
+		return CachedUser{}, false
+	}
+	return entry.user, true
+}
+
+func SetCachedUser(userID uint, user CachedUser, ttl time.Duration) {
+	userCache.Lock()
+	defer userCache.Unlock()
+
+	userCache.entries[userID] = userCacheEntry{user: user, expiresAt: time.Now().Add(ttl)}
+}
+
+// InvalidateCachedUser wajib dipanggil setiap kali role, password, atau
+// keberadaan user berubah agar perubahan langsung berlaku.
+func InvalidateCachedUser(userID uint) {
+	userCache.Lock()
+	defer userCache.Unlock()
+
+	delete(userCache.entries, userID)
+}